@@ -123,18 +123,27 @@ To create prom.AwsDynamodbConnect, see package github.com/btnguyen2k/prom
 package dynamodb
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/prom"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 /*
@@ -161,8 +170,7 @@ func (mapper *GenericRowMapperDynamodb) ToRow(table string, bo godal.IGenericBo)
 	if bo == nil {
 		return nil, nil
 	}
-	result := make(map[string]interface{})
-	return result, bo.GboTransferViaJson(&result)
+	return bo.GboToMap()
 }
 
 /*
@@ -281,15 +289,26 @@ Function implementations (n = No, y = Yes, i = inherited):
 	(y) GdaoDeleteMany(storageId string, filter interface{}) (int, error)
 	(y) GdaoFetchOne(storageId string, filter interface{}) (godal.IGenericBo, error)
 	(y) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error)
+	(y) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error)
+	(y) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error)
+	(y) GdaoCount(storageId string, filter interface{}) (int64, error)
+	(y) GdaoExists(storageId string, filter interface{}) (bool, error)
 	(y) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
 	(y) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error)
+	(y) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error)
 	(y) GdaoSave(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
+
+GenericDaoDynamodb also implements godal.IGenericDaoWithContext and godal.ITransactionalDao (since v0.3.0).
 
 Available: since v0.2.0
 */
 type GenericDaoDynamodb struct {
 	*godal.AbstractGenericDao
 	dynamodbConnect *prom.AwsDynamodbConnect
+	keySchemas      sync.Map // cache of key schemas, {<table_name>:<index_name> -> *keySchema}
 }
 
 /*
@@ -319,6 +338,109 @@ func (dao *GenericDaoDynamodb) extractKeysAttributes(table string, item prom.Aws
 	return keysAttrs
 }
 
+var (
+	// AWS error codes, see https://docs.aws.amazon.com/amazondynamodb/latest/developerguide/Programming.Errors.html
+	dynamodbErrorKinds = map[string]error{
+		dynamodb.ErrCodeConditionalCheckFailedException:        godal.ErrConflict,
+		dynamodb.ErrCodeProvisionedThroughputExceededException: godal.ErrThrottled,
+		dynamodb.ErrCodeRequestLimitExceeded:                   godal.ErrThrottled,
+		"ThrottlingException":                                  godal.ErrThrottled,
+		dynamodb.ErrCodeTransactionConflictException:           godal.ErrConcurrentModification,
+		dynamodb.ErrCodeTransactionInProgressException:         godal.ErrConcurrentModification,
+		dynamodb.ErrCodeResourceNotFoundException:              godal.ErrNotFound,
+	}
+
+	// transaction cancellation reasons
+	dynamodbTxCancellationKinds = map[string]error{
+		"ConditionalCheckFailed":        godal.ErrConflict,
+		"TransactionConflict":           godal.ErrConcurrentModification,
+		"ThrottlingError":               godal.ErrThrottled,
+		"ProvisionedThroughputExceeded": godal.ErrThrottled,
+	}
+)
+
+/*
+TranslateError maps an AWS error to godal's error kinds (godal.ErrConflict, godal.ErrThrottled, etc) by wrapping it into a
+*godal.DaoError; errors of unknown kinds are returned as-is.
+
+	- ConditionalCheckFailedException: godal.ErrConflict
+	- ProvisionedThroughputExceededException, RequestLimitExceeded, ThrottlingException: godal.ErrThrottled
+	- TransactionConflictException, TransactionInProgressException: godal.ErrConcurrentModification
+	- ResourceNotFoundException: godal.ErrNotFound
+	- RequestCanceled because of context deadline, and context.DeadlineExceeded: godal.ErrTimeout
+	- TransactionCanceledException: kind of the first known cancellation reason (ConditionalCheckFailed, TransactionConflict,
+	  ThrottlingError, ProvisionedThroughputExceeded)
+
+Errors returned by GdaoXxx functions are translated, except for errors already reported as godal.GdaoErrorDuplicatedEntry or
+godal.GdaoErrorConcurrentModification.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) TranslateError(err error) error {
+	var daoErr *godal.DaoError
+	if err == nil || errors.As(err, &daoErr) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return godal.WrapError(godal.ErrTimeout, err)
+	}
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) {
+		return err
+	}
+	switch aerr.Code() {
+	case request.CanceledErrorCode:
+		if aerr.OrigErr() == context.DeadlineExceeded {
+			return godal.WrapError(godal.ErrTimeout, err)
+		}
+	case dynamodb.ErrCodeTransactionCanceledException:
+		if matches := reTxCancellationReasons.FindStringSubmatch(aerr.Message()); matches != nil {
+			for _, reason := range strings.Split(matches[1], ",") {
+				if kind := dynamodbTxCancellationKinds[strings.TrimSpace(reason)]; kind != nil {
+					return godal.WrapError(kind, err)
+				}
+			}
+		}
+	}
+	return godal.WrapError(dynamodbErrorKinds[aerr.Code()], err)
+}
+
+/*
+IsRetryableError is the classifier of retryable errors used with the DAO's retry policy (see godal.AbstractGenericDao.SetRetryPolicy):
+throttling errors and transaction conflicts (see TranslateError and godal.IsRetryableError).
+
+Calls made to DynamoDB outside of a transaction, and GdaoWithTransaction, are retried according to the DAO's retry policy. Note that
+the AWS SDK also retries throttled requests (see aws.Config.MaxRetries) before the DAO's retry policy applies.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) IsRetryableError(err error) bool {
+	return godal.IsRetryableError(dao.TranslateError(err)) || request.IsErrorThrottle(err)
+}
+
+// startOp reports the start of a Gdao* call to the DAO's instrumentation, see godal.AbstractGenericDao.StartOp.
+func (dao *GenericDaoDynamodb) startOp(ctx aws.Context, operation, table string) (aws.Context, *godal.DaoOperation) {
+	return dao.StartOp(ctx, &godal.DaoOperation{Backend: "dynamodb", Operation: operation, StorageId: table})
+}
+
+// retry calls fn under the DAO's retry policy, unless ctx is carrying a transaction: calls made inside a transaction are not retried
+// individually.
+func (dao *GenericDaoDynamodb) retry(ctx context.Context, fn func() error) error {
+	if dao.txFromContext(ctx) != nil {
+		return fn()
+	}
+	return dao.WithRetry(ctx, dao.IsRetryableError, fn)
+}
+
+// getItem fetches an item by its key, see prom.AwsDynamodbConnect.GetItem.
+func (dao *GenericDaoDynamodb) getItem(ctx aws.Context, table string, keyFilter map[string]interface{}) (item prom.AwsDynamodbItem, err error) {
+	err = dao.retry(ctx, func() error {
+		item, err = dao.dynamodbConnect.GetItem(ctx, table, keyFilter)
+		return err
+	})
+	return item, err
+}
+
 /*
 toConditionBuilder builds a ConditionBuilder from input.
 
@@ -326,6 +448,90 @@ toConditionBuilder builds a ConditionBuilder from input.
 	- if input is string, slice/array of bytes: assume input is a map in JSON, convert it to map to build ConditionBuilder
 	- if input is a map: build an "and" condition connecting sub-conditions where each sub-condition is an "equal" condition built from map entry
 */
+// filterOptToConditionBuilder translates a godal.FilterOpt to expression.ConditionBuilder.
+func filterOptToConditionBuilder(f *godal.FilterOpt) (expression.ConditionBuilder, error) {
+	if f == nil {
+		return expression.ConditionBuilder{}, errors.New("nil filter")
+	}
+	name := expression.Name(f.Field)
+	switch f.Operator {
+	case godal.FilterOpEq:
+		return name.Equal(expression.Value(f.Value)), nil
+	case godal.FilterOpNe:
+		// absent/NULL attributes must not match, the same as other comparisons
+		return expression.And(name.AttributeExists(), expression.Not(name.AttributeType(expression.Null)), name.NotEqual(expression.Value(f.Value))), nil
+	case godal.FilterOpGt:
+		return name.GreaterThan(expression.Value(f.Value)), nil
+	case godal.FilterOpGte:
+		return name.GreaterThanEqual(expression.Value(f.Value)), nil
+	case godal.FilterOpLt:
+		return name.LessThan(expression.Value(f.Value)), nil
+	case godal.FilterOpLte:
+		return name.LessThanEqual(expression.Value(f.Value)), nil
+	case godal.FilterOpIn:
+		if len(f.Values) == 0 {
+			return expression.ConditionBuilder{}, errors.New("filter IN requires at least one value")
+		}
+		others := make([]expression.OperandBuilder, 0, len(f.Values)-1)
+		for _, v := range f.Values[1:] {
+			others = append(others, expression.Value(v))
+		}
+		return name.In(expression.Value(f.Values[0]), others...), nil
+	case godal.FilterOpBetween:
+		if len(f.Values) != 2 {
+			return expression.ConditionBuilder{}, errors.New("filter BETWEEN requires exactly two values")
+		}
+		return name.Between(expression.Value(f.Values[0]), expression.Value(f.Values[1])), nil
+	case godal.FilterOpPrefix:
+		prefix, err := reddo.ToString(f.Value)
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		return name.BeginsWith(prefix), nil
+	case godal.FilterOpIsNull:
+		return name.AttributeNotExists().Or(name.AttributeType(expression.Null)), nil
+	case godal.FilterOpExists:
+		return name.AttributeExists().And(expression.Not(name.AttributeType(expression.Null))), nil
+	case godal.FilterOpAnd, godal.FilterOpOr:
+		if len(f.Filters) == 0 {
+			return expression.ConditionBuilder{}, errors.New("filter AND/OR requires at least one sub-filter")
+		}
+		conds := make([]expression.ConditionBuilder, len(f.Filters))
+		for i, sub := range f.Filters {
+			var err error
+			if conds[i], err = filterOptToConditionBuilder(sub); err != nil {
+				return expression.ConditionBuilder{}, err
+			}
+		}
+		if len(conds) == 1 {
+			return conds[0], nil
+		}
+		if f.Operator == godal.FilterOpAnd {
+			return expression.And(conds[0], conds[1], conds[2:]...), nil
+		}
+		return expression.Or(conds[0], conds[1], conds[2:]...), nil
+	case godal.FilterOpNot:
+		if len(f.Filters) != 1 {
+			return expression.ConditionBuilder{}, errors.New("filter NOT requires exactly one sub-filter")
+		}
+		cond, err := filterOptToConditionBuilder(f.Filters[0])
+		if err != nil {
+			return expression.ConditionBuilder{}, err
+		}
+		return expression.Not(cond), nil
+	}
+	return expression.ConditionBuilder{}, errors.New(fmt.Sprintf("unsupported filter operator %d", f.Operator))
+}
+
+// isNilValue returns true if v is a nil pointer/map/slice/interface.
+func isNilValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
 func toConditionBuilder(input interface{}) (*expression.ConditionBuilder, error) {
 	switch input.(type) {
 	case expression.ConditionBuilder:
@@ -333,9 +539,19 @@ func toConditionBuilder(input interface{}) (*expression.ConditionBuilder, error)
 		return &result, nil
 	case *expression.ConditionBuilder:
 		return input.(*expression.ConditionBuilder), nil
+	case godal.FilterOpt:
+		f := input.(godal.FilterOpt)
+		input = &f
+	}
+	if f, ok := input.(*godal.FilterOpt); ok && f != nil {
+		result, err := filterOptToConditionBuilder(f)
+		if err != nil {
+			return nil, err
+		}
+		return &result, nil
 	}
 	v := reflect.ValueOf(input)
-	if input == nil || v.IsNil() {
+	if input == nil || isNilValue(v) {
 		return nil, nil
 	}
 	for ; v.Kind() == reflect.Ptr; v = v.Elem() {
@@ -387,7 +603,7 @@ func toMap(input interface{}) (map[string]interface{}, error) {
 		return *input.(*map[string]interface{}), nil
 	}
 	v := reflect.ValueOf(input)
-	if input == nil || v.IsNil() {
+	if input == nil || isNilValue(v) {
 		return nil, nil
 	}
 	for ; v.Kind() == reflect.Ptr; v = v.Elem() {
@@ -414,6 +630,169 @@ func toMap(input interface{}) (map[string]interface{}, error) {
 	return nil, errors.New(fmt.Sprintf("cannot convert %v to map[string]interface{}", input))
 }
 
+// toSortingOpt converts the input to a godal.SortingOpt; fields of a map sorting are sorted by name.
+func toSortingOpt(input interface{}) (*godal.SortingOpt, error) {
+	switch o := input.(type) {
+	case *godal.SortingOpt:
+		return o, nil
+	case godal.SortingOpt:
+		return &o, nil
+	}
+	v := reflect.ValueOf(input)
+	if input == nil || isNilValue(v) {
+		return nil, nil
+	}
+	m, err := reddo.ToMap(input, reflect.TypeOf(map[string]int{}))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("cannot convert %v to godal.SortingOpt", input))
+	}
+	fields := make([]string, 0)
+	for k := range m.(map[string]int) {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	result := godal.NewSortingOpt()
+	for _, field := range fields {
+		result.Add(field, godal.SortingOrder(m.(map[string]int)[field]))
+	}
+	return result, nil
+}
+
+// keySchema holds names of the partition key and sort key attributes of a table or index.
+type keySchema struct {
+	hashKey, rangeKey string
+}
+
+/*
+getKeySchema returns the key schema of a table, or of one of its secondary indexes if indexName is not empty.
+
+Key schemas are fetched via "describe-table" operation and cached.
+*/
+func (dao *GenericDaoDynamodb) getKeySchema(ctx aws.Context, tableName, indexName string) (*keySchema, error) {
+	cacheKey := tableName + ":" + indexName
+	if v, ok := dao.keySchemas.Load(cacheKey); ok {
+		return v.(*keySchema), nil
+	}
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	var output *dynamodb.DescribeTableOutput
+	err := dao.retry(ctx, func() (err error) {
+		output, err = dao.dynamodbConnect.GetDb().DescribeTableWithContext(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableName)})
+		return err
+	})
+	if err != nil {
+		return nil, dao.TranslateError(err)
+	}
+	elements := output.Table.KeySchema
+	if indexName != "" {
+		elements = nil
+		for _, index := range output.Table.GlobalSecondaryIndexes {
+			if aws.StringValue(index.IndexName) == indexName {
+				elements = index.KeySchema
+			}
+		}
+		for _, index := range output.Table.LocalSecondaryIndexes {
+			if aws.StringValue(index.IndexName) == indexName {
+				elements = index.KeySchema
+			}
+		}
+		if elements == nil {
+			return nil, errors.New(fmt.Sprintf("cannot find index [%s] of table [%s]", indexName, tableName))
+		}
+	}
+	result := &keySchema{}
+	for _, element := range elements {
+		switch aws.StringValue(element.KeyType) {
+		case dynamodb.KeyTypeHash:
+			result.hashKey = aws.StringValue(element.AttributeName)
+		case dynamodb.KeyTypeRange:
+			result.rangeKey = aws.StringValue(element.AttributeName)
+		}
+	}
+	dao.keySchemas.Store(cacheKey, result)
+	return result, nil
+}
+
+var keyConditionOps = map[godal.FilterOperator]bool{
+	godal.FilterOpEq: true, godal.FilterOpGt: true, godal.FilterOpGte: true, godal.FilterOpLt: true, godal.FilterOpLte: true,
+	godal.FilterOpBetween: true, godal.FilterOpPrefix: true,
+}
+
+/*
+buildQueryConditions splits a filter into key-condition and non-key filter of a "query" operation:
+
+	- the filter must be a map[string]interface{} (or JSON string) or a godal.FilterOpt, and must be an "and" of conditions (or a single condition)
+	- an "equal" condition on the partition key is required and becomes the key-condition
+	- the first condition on the sort key that is supported by key-condition (=, <, <=, >, >=, between, begins_with) is also added to the key-condition
+	- remaining conditions are combined to form the non-key filter
+*/
+func buildQueryConditions(filter interface{}, schema *keySchema) (*expression.ConditionBuilder, *expression.ConditionBuilder, error) {
+	var f *godal.FilterOpt
+	switch t := filter.(type) {
+	case *godal.FilterOpt:
+		f = t
+	case godal.FilterOpt:
+		f = &t
+	case expression.ConditionBuilder, *expression.ConditionBuilder:
+		return nil, nil, errors.New("sorting requires filter to be a map or a godal.FilterOpt, expression.ConditionBuilder can not be split into key-condition and filter")
+	default:
+		m, err := toMap(filter)
+		if err != nil {
+			return nil, nil, errors.New(fmt.Sprintf("sorting requires filter to be a map or a godal.FilterOpt: %s", err))
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		f = godal.FilterAnd()
+		for _, k := range keys {
+			f.Filters = append(f.Filters, godal.FilterEq(k, m[k]))
+		}
+	}
+	conditions := []*godal.FilterOpt{f}
+	if f != nil && f.Operator == godal.FilterOpAnd {
+		conditions = f.Filters
+	}
+	var hashCond, rangeCond *godal.FilterOpt
+	others := make([]*godal.FilterOpt, 0)
+	for _, c := range conditions {
+		if c == nil {
+			continue
+		}
+		if hashCond == nil && c.Operator == godal.FilterOpEq && c.Field == schema.hashKey {
+			hashCond = c
+		} else if rangeCond == nil && schema.rangeKey != "" && c.Field == schema.rangeKey && keyConditionOps[c.Operator] {
+			rangeCond = c
+		} else {
+			others = append(others, c)
+		}
+	}
+	if hashCond == nil {
+		return nil, nil, errors.New(fmt.Sprintf("sorting requires an \"equal\" condition on partition key [%s]", schema.hashKey))
+	}
+	keyCond, err := filterOptToConditionBuilder(hashCond)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rangeCond != nil {
+		c, err := filterOptToConditionBuilder(rangeCond)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyCond = keyCond.And(c)
+	}
+	if len(others) == 0 {
+		return &keyCond, nil, nil
+	}
+	nonKeyCond, err := filterOptToConditionBuilder(godal.FilterAnd(others...))
+	if err != nil {
+		return nil, nil, err
+	}
+	return &keyCond, &nonKeyCond, nil
+}
+
 /*----------------------------------------------------------------------*/
 
 /*
@@ -426,12 +805,29 @@ func (dao *GenericDaoDynamodb) GdaoDelete(table string, bo godal.IGenericBo) (in
 /*
 GdaoDeleteWithContext is extended-implementation of godal.IGenericDao.GdaoDelete.
 */
-func (dao *GenericDaoDynamodb) GdaoDeleteWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
+func (dao *GenericDaoDynamodb) GdaoDeleteWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDelete", table)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeDelete, table, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.deleteItem(ctx, table, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterDelete, table, bo, numRows, err)
+}
+
+// deleteItem deletes an item, see GdaoDeleteWithContext.
+func (dao *GenericDaoDynamodb) deleteItem(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
 	if keyFilter, err := toMap(dao.GdaoCreateFilter(table, bo)); err != nil {
 		return 0, err
+	} else if tx := dao.txFromContext(ctx); tx != nil {
+		txItem, err := dao.dynamodbConnect.BuildTxDelete(table, keyFilter, nil)
+		return tx.add(txItem, nil, err)
 	} else {
-		_, err := dao.dynamodbConnect.DeleteItem(ctx, table, keyFilter, nil)
-		return 1, err
+		err := dao.retry(ctx, func() (err error) {
+			_, err = dao.dynamodbConnect.DeleteItem(ctx, table, keyFilter, nil)
+			return err
+		})
+		return 1, dao.TranslateError(err)
 	}
 }
 
@@ -441,6 +837,7 @@ GdaoDeleteMany implements godal.IGenericDao.GdaoDeleteMany.
 	- this function uses "scan" operation, hence it has performance impact if table has large number of items
 	- filter can be a expression.ConditionBuilder (or pointer to it) or a map[string]interface{} (it can be a string/[]byte representing map[string]interface{} in JSON)
 		If filter is a map[string]interface{}, it is used to build an "and" condition connecting sub-conditions where each sub-condition is an "equal" condition built from map entry.
+		filter can also be a godal.FilterOpt, which is translated to expression.ConditionBuilder (since v0.3.0).
 		nil filter means "match all".
 */
 func (dao *GenericDaoDynamodb) GdaoDeleteMany(table string, filter interface{}) (int, error) {
@@ -453,29 +850,43 @@ GdaoDeleteManyWithContext is extended-implementation of godal.IGenericDao.GdaoDe
 	- this function uses "scan" operation, hence it has performance impact if table has large number of items
 	- filter can be a expression.ConditionBuilder (or pointer to it) or a map[string]interface{} (it can be a string/[]byte representing map[string]interface{} in JSON)
 		If filter is a map[string]interface{}, it is used to build an "and" condition connecting sub-conditions where each sub-condition is an "equal" condition built from map entry.
+		filter can also be a godal.FilterOpt, which is translated to expression.ConditionBuilder (since v0.3.0).
 		nil filter means "match all".
 */
-func (dao *GenericDaoDynamodb) GdaoDeleteManyWithContext(ctx aws.Context, table string, filter interface{}) (int, error) {
+func (dao *GenericDaoDynamodb) GdaoDeleteManyWithContext(ctx aws.Context, table string, filter interface{}) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDeleteMany", table)
+	defer func() { op.Finish(numRows, err) }()
 	f, err := toConditionBuilder(filter)
 	if err != nil {
 		return 0, err
 	}
 	counter := 0
+	tx := dao.txFromContext(ctx)
 	err = dao.dynamodbConnect.ScanItemsWithCallback(ctx, table, f, "", nil, func(item prom.AwsDynamodbItem, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (b bool, e error) {
 		keyFilter := dao.extractKeysAttributes(table, item)
-		_, err := dao.dynamodbConnect.DeleteItem(ctx, table, keyFilter, nil)
+		var err error
+		if tx != nil {
+			txItem, e := dao.dynamodbConnect.BuildTxDelete(table, keyFilter, nil)
+			_, err = tx.add(txItem, nil, e)
+		} else {
+			err = dao.retry(ctx, func() (err error) {
+				_, err = dao.dynamodbConnect.DeleteItem(ctx, table, keyFilter, nil)
+				return err
+			})
+		}
 		if err == nil {
 			counter++
 		}
 		return true, err
 	})
-	return counter, err
+	return counter, dao.TranslateError(err)
 }
 
 /*
 GdaoFetchOne implements godal.IGenericDao.GdaoFetchOne.
 
 	- keyFilter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- keyFilter can also be a godal.FilterOpt (since v0.3.0); in which case "scan" operation is used and the first matched item is returned
 */
 func (dao *GenericDaoDynamodb) GdaoFetchOne(table string, filter interface{}) (godal.IGenericBo, error) {
 	return dao.GdaoFetchOneWithContext(nil, table, filter)
@@ -485,14 +896,40 @@ func (dao *GenericDaoDynamodb) GdaoFetchOne(table string, filter interface{}) (g
 GdaoFetchOneWithContext is extended-implementation of godal.IGenericDao.GdaoFetchOne.
 
 	- keyFilter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- keyFilter can also be a godal.FilterOpt (since v0.3.0); in which case "scan" operation is used and the first matched item is returned
 */
-func (dao *GenericDaoDynamodb) GdaoFetchOneWithContext(ctx aws.Context, table string, keyFilter interface{}) (godal.IGenericBo, error) {
+func (dao *GenericDaoDynamodb) GdaoFetchOneWithContext(ctx aws.Context, table string, keyFilter interface{}) (bo godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchOne", table)
+	defer func() {
+		if bo != nil {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	switch keyFilter.(type) {
+	case godal.FilterOpt, *godal.FilterOpt:
+		if boList, err := dao.GdaoFetchManyWithContext(ctx, table, keyFilter, nil, 0, 1); err != nil || len(boList) == 0 {
+			return nil, err
+		} else {
+			return boList[0], nil
+		}
+	}
 	if f, err := toMap(keyFilter); err != nil {
 		return nil, err
-	} else if item, err := dao.dynamodbConnect.GetItem(ctx, table, f); err != nil {
-		return nil, err
+	} else if tx := dao.txFromContext(ctx); tx != nil {
+		if err := tx.checkEnded(); err != nil {
+			return nil, err
+		}
+		if item, err := dao.txGetItem(ctx, table, f); err != nil || item == nil {
+			return nil, dao.TranslateError(err)
+		} else {
+			return dao.RowToBo(ctx, table, item)
+		}
+	} else if item, err := dao.getItem(ctx, table, f); err != nil {
+		return nil, dao.TranslateError(err)
 	} else {
-		return dao.GetRowMapper().ToBo(table, item)
+		return dao.RowToBo(ctx, table, item)
 	}
 }
 
@@ -507,8 +944,9 @@ GdaoFetchMany implements godal.IGenericDao.GdaoFetchMany.
 		if 'true' another read is made to fetch the whole item from table (additional read capacity is consumed!)
 	- filter can be a expression.ConditionBuilder (or pointer to it) or a map[string]interface{} (it can be a string/[]byte representing map[string]interface{} in JSON)
 		If filter is a map[string]interface{}, it is used to build an "and" condition connecting sub-conditions where each sub-condition is an "equal" condition built from map entry.
+		filter can also be a godal.FilterOpt, which is translated to expression.ConditionBuilder (since v0.3.0).
 		nil filter means "match all".
-	- sorting will not be used as DynamoDB does not currently support custom sorting of queried items.
+	- sorting can be a godal.SortingOpt or a map[string]int (since v0.3.0), see GdaoFetchManyWithContext for details.
 */
 func (dao *GenericDaoDynamodb) GdaoFetchMany(table string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
 	return dao.GdaoFetchManyWithContext(nil, table, filter, sorting, startOffset, numItems)
@@ -525,138 +963,1370 @@ GdaoFetchManyWithContext is extended-implementation of godal.IGenericDao.GdaoFet
 		if 'true' another read is made to fetch the whole item from table (additional read capacity is consumed!)
 	- filter can be a expression.ConditionBuilder (or pointer to it) or a map[string]interface{} (it can be a string/[]byte representing map[string]interface{} in JSON)
 		If filter is a map[string]interface{}, it is used to build an "and" condition connecting sub-conditions where each sub-condition is an "equal" condition built from map entry.
+		filter can also be a godal.FilterOpt, which is translated to expression.ConditionBuilder (since v0.3.0).
 		nil filter means "match all"
-	- sorting will not be used as DynamoDB does not currently support custom sorting of queried items.
+	- sorting can be a godal.SortingOpt or a map[string]int (since v0.3.0). DynamoDB can only sort items by the sort key, hence:
+		sorting must contain only the sort key of the table (or index), and filter must be a map[string]interface{} or a godal.FilterOpt
+		containing an "equal" condition on the partition key; "query" operation is used instead of "scan" in such case.
+		Otherwise, error is returned.
 */
-func (dao *GenericDaoDynamodb) GdaoFetchManyWithContext(ctx aws.Context, table string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
-	f, err := toConditionBuilder(filter)
+func (dao *GenericDaoDynamodb) GdaoFetchManyWithContext(ctx aws.Context, table string, filter interface{}, sorting interface{}, startOffset, numItems int) (boList []godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchMany", table)
+	defer func() { op.Finish(len(boList), err) }()
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	input, err := dao.buildFetchInput(ctx, table, filter, sorting)
 	if err != nil {
 		return nil, err
 	}
+	it := &dynamodbBoIterator{ctx: ctx, dao: dao, input: input, hasMorePages: true}
+	defer func() { _ = it.Close() }()
+	for i := 0; i < startOffset && it.nextRaw() != nil; i++ {
+	}
 	result := make([]godal.IGenericBo, 0)
-	myOffset := -1
-	myCounter := 0
+	for (numItems <= 0 || len(result) < numItems) && it.Next() {
+		result = append(result, it.Bo())
+	}
+	return result, it.Err()
+}
+
+/*
+GdaoFetchIterator implements godal.IGenericDao.GdaoFetchIterator.
+
+	- table, filter and sorting: see GdaoFetchManyWithContext.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchIterator(table string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithContext(nil, table, filter, sorting)
+}
+
+/*
+GdaoFetchIteratorWithContext is extended-implementation of godal.IGenericDao.GdaoFetchIterator.
+
+	- table, filter and sorting: see GdaoFetchManyWithContext.
+	- if ctx is nil, a context without deadline is used (the default timeout is not applied as the iteration can take long).
+	- the returned iterator is backed by paginated "scan" (or "query") operation: a page of items is fetched from DynamoDB only when
+	  the previous one has been consumed, and items are transformed to BOs lazily.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchIteratorWithContext(ctx aws.Context, table string, filter interface{}, sorting interface{}) (it godal.IGenericBoIterator, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchIterator", table)
+	defer func() { op.Finish(0, err) }()
+	if ctx == nil {
+		ctx = aws.BackgroundContext()
+	}
+	input, err := dao.buildFetchInput(ctx, table, filter, sorting)
+	if err != nil {
+		return nil, err
+	}
+	return &dynamodbBoIterator{ctx: ctx, dao: dao, input: input, hasMorePages: true}, nil
+}
+
+// fetchInput holds the "scan" (or "query") input built from table's specs, filter and sorting.
+type fetchInput struct {
+	table            string // table's specs in format <table_name>[:<index_name>[:<refetch-from-table:true/false>]]
+	tableName        string
+	refetchFromTable bool
+	scanInput        *dynamodb.ScanInput
+	queryInput       *dynamodb.QueryInput
+}
+
+// buildFetchInput builds the input to fetch items: "query" if sorting is specified, "scan" otherwise.
+func (dao *GenericDaoDynamodb) buildFetchInput(ctx aws.Context, table string, filter interface{}, sorting interface{}) (*fetchInput, error) {
+	s, err := toSortingOpt(sorting)
+	if err != nil {
+		return nil, err
+	}
 	tokens := strings.Split(table, ":")
-	tableName := tokens[0]
+	result := &fetchInput{table: table, tableName: tokens[0]}
 	indexName := ""
 	if len(tokens) > 1 {
 		indexName = tokens[1]
 	}
-	refetchFromTable := false
 	if len(tokens) > 2 {
-		if refetchFromTable, err = strconv.ParseBool(tokens[2]); err != nil {
-			refetchFromTable = false
+		if result.refetchFromTable, err = strconv.ParseBool(tokens[2]); err != nil {
+			result.refetchFromTable = false
 		}
 	}
-	err = dao.dynamodbConnect.ScanItemsWithCallback(ctx, tableName, f, indexName, nil, func(item prom.AwsDynamodbItem, lastEvaluatedKey map[string]*dynamodb.AttributeValue) (b bool, e error) {
-		myOffset++
-		if myOffset < startOffset {
-			return true, nil
-		}
-		if refetchFromTable {
-			pkAttrs := dao.extractKeysAttributes(tableName, item)
-			if item, err = dao.dynamodbConnect.GetItem(ctx, tableName, pkAttrs); err != nil {
-				return false, err
-			}
+	if s == nil || len(s.Fields) == 0 {
+		f, err := toConditionBuilder(filter)
+		if err != nil {
+			return nil, err
 		}
-		if gbo, err := dao.GetRowMapper().ToBo(table, item); err != nil {
-			return false, err
+		result.scanInput, err = dao.dynamodbConnect.BuildScanInput(result.tableName, f, indexName, nil)
+		return result, err
+	}
+
+	schema, err := dao.getKeySchema(ctx, result.tableName, indexName)
+	if err != nil {
+		return nil, err
+	}
+	if schema.rangeKey == "" {
+		return nil, errors.New(fmt.Sprintf("cannot sort items of [%s]: table/index has no sort key", table))
+	}
+	if len(s.Fields) != 1 || s.Fields[0].Field != schema.rangeKey {
+		return nil, errors.New(fmt.Sprintf("cannot sort items of [%s]: only sorting by sort key [%s] is supported", table, schema.rangeKey))
+	}
+	keyCond, nonKeyCond, err := buildQueryConditions(filter, schema)
+	if err != nil {
+		return nil, err
+	}
+	if result.queryInput, err = dao.dynamodbConnect.BuildQueryInput(result.tableName, keyCond, nonKeyCond, indexName, nil); err != nil {
+		return nil, err
+	}
+	result.queryInput.ScanIndexForward = aws.Bool(!s.Fields[0].Descending())
+	return result, nil
+}
+
+// dynamodbBoIterator implements godal.IGenericBoIterator.
+type dynamodbBoIterator struct {
+	ctx              aws.Context
+	dao              *GenericDaoDynamodb
+	input            *fetchInput
+	items            []map[string]*dynamodb.AttributeValue // items of the current page that have not been consumed
+	lastEvaluatedKey map[string]*dynamodb.AttributeValue
+	hasMorePages     bool
+	limit            int // if positive, maximum number of items to evaluate by the next page request
+	closed           bool
+	bo               godal.IGenericBo
+	err              error
+}
+
+// fetchPage fetches the next page of items.
+func (it *dynamodbBoIterator) fetchPage() {
+	db := it.dao.dynamodbConnect.GetDb()
+	var limit *int64
+	if it.limit > 0 {
+		limit = aws.Int64(int64(it.limit))
+	}
+	if it.input.queryInput != nil {
+		it.input.queryInput.ExclusiveStartKey = it.lastEvaluatedKey
+		it.input.queryInput.Limit = limit
+		var output *dynamodb.QueryOutput
+		if err := it.dao.retry(it.ctx, func() (err error) {
+			output, err = db.QueryWithContext(it.ctx, it.input.queryInput)
+			return err
+		}); err != nil {
+			it.err = it.dao.TranslateError(err)
 		} else {
-			result = append(result, gbo)
+			it.items, it.lastEvaluatedKey = output.Items, output.LastEvaluatedKey
 		}
-		myCounter++
-		if numItems > 0 && myCounter >= numItems {
-			return false, nil
+	} else {
+		it.input.scanInput.ExclusiveStartKey = it.lastEvaluatedKey
+		it.input.scanInput.Limit = limit
+		var output *dynamodb.ScanOutput
+		if err := it.dao.retry(it.ctx, func() (err error) {
+			output, err = db.ScanWithContext(it.ctx, it.input.scanInput)
+			return err
+		}); err != nil {
+			it.err = it.dao.TranslateError(err)
+		} else {
+			it.items, it.lastEvaluatedKey = output.Items, output.LastEvaluatedKey
 		}
-		return true, nil
-	})
-	return result, err
+	}
+	it.hasMorePages = it.err == nil && it.lastEvaluatedKey != nil
 }
 
-/*
-GdaoCreate implements godal.IGenericDao.GdaoCreate.
-*/
-func (dao *GenericDaoDynamodb) GdaoCreate(table string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoCreateWithContext(nil, table, bo)
+// nextRaw consumes the next item, fetching the next page if needed. It returns nil if there is no more item or an error occurred.
+func (it *dynamodbBoIterator) nextRaw() map[string]*dynamodb.AttributeValue {
+	// a page can be empty (e.g. all items of the page are filtered out) even if there are more pages
+	for len(it.items) == 0 {
+		if it.err != nil || it.closed || !it.hasMorePages {
+			return nil
+		}
+		it.fetchPage()
+	}
+	item := it.items[0]
+	it.items = it.items[1:]
+	return item
 }
 
-/*
-GdaoCreateWithContext is extended-implementation of godal.IGenericDao.GdaoCreate.
-*/
-func (dao *GenericDaoDynamodb) GdaoCreateWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
-	pkAttrs := dao.GetRowMapper().ColumnsList(table)
-	if pkAttrs == nil || len(pkAttrs) == 0 {
-		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+// Next implements godal.IGenericBoIterator.Next.
+func (it *dynamodbBoIterator) Next() bool {
+	it.bo = nil
+	raw := it.nextRaw()
+	if raw == nil {
+		return false
 	}
-	if item, err := dao.GetRowMapper().ToRow(table, bo); err != nil {
-		return 0, err
-	} else {
-		_, err := dao.dynamodbConnect.PutItemIfNotExist(ctx, table, item, pkAttrs)
-		if prom.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
-			return 0, godal.GdaoErrorDuplicatedEntry
+	item := prom.AwsDynamodbItem{}
+	if it.err = dynamodbattribute.UnmarshalMap(raw, &item); it.err != nil {
+		return false
+	}
+	if it.input.refetchFromTable {
+		pkAttrs := it.dao.extractKeysAttributes(it.input.tableName, item)
+		if item, it.err = it.dao.getItem(it.ctx, it.input.tableName, pkAttrs); it.err != nil {
+			it.err = it.dao.TranslateError(it.err)
+			return false
 		}
-		return 1, err
 	}
+	it.bo, it.err = it.dao.RowToBo(it.ctx, it.input.table, item)
+	return it.bo != nil && it.err == nil
+}
+
+// Bo implements godal.IGenericBoIterator.Bo.
+func (it *dynamodbBoIterator) Bo() godal.IGenericBo {
+	return it.bo
+}
+
+// Err implements godal.IGenericBoIterator.Err.
+func (it *dynamodbBoIterator) Err() error {
+	return it.err
+}
+
+// Close implements godal.IGenericBoIterator.Close.
+func (it *dynamodbBoIterator) Close() error {
+	it.closed = true
+	it.items = nil
+	return nil
 }
 
 /*
-GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+GdaoFetchPage implements godal.IGenericDao.GdaoFetchPage.
+
+	- table, filter and sorting: see GdaoFetchManyWithContext.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoDynamodb) GdaoUpdate(table string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoUpdateWithContext(nil, table, bo)
+func (dao *GenericDaoDynamodb) GdaoFetchPage(table string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithContext(nil, table, filter, sorting, pageToken, numItems)
 }
 
 /*
-GdaoUpdateWithContext is extended-implementation of godal.IGenericDao.GdaoUpdate.
+GdaoFetchPageWithContext is extended-implementation of godal.IGenericDao.GdaoFetchPage.
+
+	- table, filter and sorting: see GdaoFetchManyWithContext.
+	- the page token is the encoded LastEvaluatedKey of the "scan" (or "query") operation, hence items are never read twice.
+	- DynamoDB's Limit caps the number of evaluated items (not matched ones), hence fewer than numItems items may be returned along with a
+	  non-empty next page token; likewise, the last page may be empty.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoDynamodb) GdaoUpdateWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
-	var keyFilter, itemMap map[string]interface{}
-	var err error
-	if keyFilter, err = toMap(dao.GdaoCreateFilter(table, bo)); err != nil {
-		return 0, err
+func (dao *GenericDaoDynamodb) GdaoFetchPageWithContext(ctx aws.Context, table string, filter interface{}, sorting interface{}, pageToken string, numItems int) (boList []godal.IGenericBo, nextPageToken string, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchPage", table)
+	defer func() { op.Finish(len(boList), err) }()
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
 	}
-	var item interface{}
-	if item, err = dao.GetRowMapper().ToRow(table, bo); err != nil {
-		return 0, err
-	} else if itemMap, err = toMap(item); err != nil {
-		return 0, err
+	startKey, err := decodePageToken(pageToken)
+	if err != nil {
+		return nil, "", err
 	}
-
-	if pkAttrs := dao.GetRowMapper().ColumnsList(table); pkAttrs == nil || len(pkAttrs) == 0 {
-		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
-	} else {
-		// remove primary key attributes from update list
-		for _, pk := range pkAttrs {
-			delete(itemMap, pk)
-		}
-		condition := prom.AwsDynamodbExistsAllBuilder(pkAttrs)
-		if _, err = dao.dynamodbConnect.UpdateItem(ctx, table, keyFilter, condition, nil, itemMap, nil, nil); err != nil {
-			err = prom.AwsIgnoreErrorIfMatched(err, dynamodb.ErrCodeConditionalCheckFailedException)
-			return 0, err
+	input, err := dao.buildFetchInput(ctx, table, filter, sorting)
+	if err != nil {
+		return nil, "", err
+	}
+	it := &dynamodbBoIterator{ctx: ctx, dao: dao, input: input, lastEvaluatedKey: startKey, hasMorePages: true}
+	defer func() { _ = it.Close() }()
+	result := make([]godal.IGenericBo, 0)
+	for numItems <= 0 || len(result) < numItems {
+		// a page never holds more items than needed, hence LastEvaluatedKey always points to the last returned item
+		it.limit = numItems - len(result)
+		if !it.Next() {
+			break
 		}
-		return 1, nil
+		result = append(result, it.Bo())
 	}
+	if it.Err() != nil {
+		return nil, "", it.Err()
+	}
+	nextPageToken, err = encodePageToken(it.lastEvaluatedKey)
+	return result, nextPageToken, err
 }
 
-/*
-GdaoSave implements godal.IGenericDao.GdaoSave.
-*/
-func (dao *GenericDaoDynamodb) GdaoSave(table string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoSaveWithContext(nil, table, bo)
+// pageTokenAttr is the JSON form of a key attribute in a page token; key attributes can only be of type string, number or binary.
+type pageTokenAttr struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
 }
 
-/*
-GdaoSaveWithContext is extended-implementation of godal.IGenericDao.GdaoSave.
-*/
-func (dao *GenericDaoDynamodb) GdaoSaveWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
-	pkAttrs := dao.GetRowMapper().ColumnsList(table)
-	if pkAttrs == nil || len(pkAttrs) == 0 {
-		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+func encodePageToken(lastEvaluatedKey map[string]*dynamodb.AttributeValue) (string, error) {
+	if len(lastEvaluatedKey) == 0 {
+		return "", nil
 	}
-	if item, err := dao.GetRowMapper().ToRow(table, bo); err != nil {
-		return 0, err
-	} else {
-		_, err := dao.dynamodbConnect.PutItem(ctx, table, item, nil)
-		return 1, err
+	key := make(map[string]pageTokenAttr)
+	for k, v := range lastEvaluatedKey {
+		if v == nil || (v.S == nil && v.N == nil && v.B == nil) {
+			return "", errors.New(fmt.Sprintf("cannot build page token: unsupported type of key attribute [%s]", k))
+		}
+		key[k] = pageTokenAttr{S: v.S, N: v.N, B: v.B}
+	}
+	js, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(js), nil
+}
+
+func decodePageToken(pageToken string) (map[string]*dynamodb.AttributeValue, error) {
+	if pageToken == "" {
+		return nil, nil
+	}
+	js, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, godal.GdaoErrorInvalidPageToken
+	}
+	key := make(map[string]pageTokenAttr)
+	if json.Unmarshal(js, &key) != nil || len(key) == 0 {
+		return nil, godal.GdaoErrorInvalidPageToken
+	}
+	result := make(map[string]*dynamodb.AttributeValue)
+	for k, v := range key {
+		if v.S == nil && v.N == nil && v.B == nil {
+			return nil, godal.GdaoErrorInvalidPageToken
+		}
+		result[k] = &dynamodb.AttributeValue{S: v.S, N: v.N, B: v.B}
+	}
+	return result, nil
+}
+
+/*
+GdaoCount implements godal.IGenericDao.GdaoCount.
+
+	- table and filter: see GdaoCountWithContext.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCount(table string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithContext(nil, table, filter)
+}
+
+/*
+GdaoCountWithContext is extended-implementation of godal.IGenericDao.GdaoCount.
+
+	- table's format: <table_name>[:<index_name>], see GdaoFetchManyWithContext.
+	- filter: see GdaoFetchManyWithContext.
+	- items are counted via "query" operation with Select=COUNT if filter is a map[string]interface{} or a godal.FilterOpt containing an "equal"
+	  condition on the partition key, via "scan" operation with Select=COUNT otherwise. Items are not returned, but read capacity is consumed
+	  as if they were.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCountWithContext(ctx aws.Context, table string, filter interface{}) (count int64, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCount", table)
+	defer func() { op.Finish(int(count), err) }()
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	input, err := dao.buildCountInput(ctx, table, filter)
+	if err != nil {
+		return 0, err
+	}
+	return dao.countItems(ctx, input, false)
+}
+
+/*
+GdaoExists implements godal.IGenericDao.GdaoExists.
+
+	- table and filter: see GdaoCountWithContext.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoExists(table string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithContext(nil, table, filter)
+}
+
+/*
+GdaoExistsWithContext is extended-implementation of godal.IGenericDao.GdaoExists.
+
+	- table and filter: see GdaoCountWithContext; pages are read until the first matching item is found.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoExistsWithContext(ctx aws.Context, table string, filter interface{}) (exists bool, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoExists", table)
+	defer func() {
+		if exists {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	input, err := dao.buildCountInput(ctx, table, filter)
+	if err != nil {
+		return false, err
+	}
+	count, err := dao.countItems(ctx, input, true)
+	return count > 0, err
+}
+
+// buildCountInput builds the input to count items: "query" if filter contains an "equal" condition on the partition key, "scan" otherwise.
+func (dao *GenericDaoDynamodb) buildCountInput(ctx aws.Context, table string, filter interface{}) (*fetchInput, error) {
+	tokens := strings.Split(table, ":")
+	result := &fetchInput{table: table, tableName: tokens[0]}
+	indexName := ""
+	if len(tokens) > 1 {
+		indexName = tokens[1]
+	}
+	if filter != nil {
+		// "query" is only an optimization, "scan" is used if the filter can not be split into key-condition and filter
+		if schema, err := dao.getKeySchema(ctx, result.tableName, indexName); err == nil {
+			if keyCond, nonKeyCond, err := buildQueryConditions(filter, schema); err == nil {
+				if result.queryInput, err = dao.dynamodbConnect.BuildQueryInput(result.tableName, keyCond, nonKeyCond, indexName, nil); err != nil {
+					return nil, err
+				}
+				result.queryInput.Select = aws.String(dynamodb.SelectCount)
+				return result, nil
+			}
+		}
+	}
+	f, err := toConditionBuilder(filter)
+	if err != nil {
+		return nil, err
+	}
+	if result.scanInput, err = dao.dynamodbConnect.BuildScanInput(result.tableName, f, indexName, nil); err != nil {
+		return nil, err
+	}
+	result.scanInput.Select = aws.String(dynamodb.SelectCount)
+	return result, nil
+}
+
+// countItems sums up the counts of all pages of the input, or stops at the first page having matching items if stopAtFirst is true.
+func (dao *GenericDaoDynamodb) countItems(ctx aws.Context, input *fetchInput, stopAtFirst bool) (int64, error) {
+	db := dao.dynamodbConnect.GetDb()
+	var count int64
+	var lastEvaluatedKey map[string]*dynamodb.AttributeValue
+	for {
+		if input.queryInput != nil {
+			input.queryInput.ExclusiveStartKey = lastEvaluatedKey
+			var output *dynamodb.QueryOutput
+			err := dao.retry(ctx, func() (err error) {
+				output, err = db.QueryWithContext(ctx, input.queryInput)
+				return err
+			})
+			if err != nil {
+				return count, dao.TranslateError(err)
+			}
+			count, lastEvaluatedKey = count+aws.Int64Value(output.Count), output.LastEvaluatedKey
+		} else {
+			input.scanInput.ExclusiveStartKey = lastEvaluatedKey
+			var output *dynamodb.ScanOutput
+			err := dao.retry(ctx, func() (err error) {
+				output, err = db.ScanWithContext(ctx, input.scanInput)
+				return err
+			})
+			if err != nil {
+				return count, dao.TranslateError(err)
+			}
+			count, lastEvaluatedKey = count+aws.Int64Value(output.Count), output.LastEvaluatedKey
+		}
+		if lastEvaluatedKey == nil || (stopAtFirst && count > 0) {
+			return count, nil
+		}
+	}
+}
+
+/*
+GdaoCreate implements godal.IGenericDao.GdaoCreate.
+*/
+func (dao *GenericDaoDynamodb) GdaoCreate(table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithContext(nil, table, bo)
+}
+
+/*
+GdaoCreateWithContext is extended-implementation of godal.IGenericDao.GdaoCreate.
+*/
+func (dao *GenericDaoDynamodb) GdaoCreateWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreate", table)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, table, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.createItem(ctx, table, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterCreate, table, bo, numRows, err)
+}
+
+// createItem inserts a new item, see GdaoCreateWithContext.
+func (dao *GenericDaoDynamodb) createItem(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
+	pkAttrs := dao.GetRowMapper().ColumnsList(table)
+	if pkAttrs == nil || len(pkAttrs) == 0 {
+		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	}
+	if item, err := dao.GetRowMapper().ToRow(table, bo); err != nil {
+		return 0, err
+	} else if tx := dao.txFromContext(ctx); tx != nil {
+		txItem, err := dao.dynamodbConnect.BuildTxPutIfNotExist(table, item, pkAttrs)
+		return tx.add(txItem, godal.GdaoErrorDuplicatedEntry, err)
+	} else {
+		err := dao.retry(ctx, func() (err error) {
+			_, err = dao.dynamodbConnect.PutItemIfNotExist(ctx, table, item, pkAttrs)
+			return err
+		})
+		if prom.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 1, dao.TranslateError(err)
+	}
+}
+
+/*
+GdaoCreateMany implements godal.IGenericDao.GdaoCreateMany.
+*/
+func (dao *GenericDaoDynamodb) GdaoCreateMany(table string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithContext(nil, table, boList)
+}
+
+// maxBatchWriteItems is the maximum number of items per BatchWriteItem/TransactWriteItems call.
+const maxBatchWriteItems = 25
+
+/*
+GdaoCreateManyWithContext is extended-implementation of godal.IGenericDao.GdaoCreateMany.
+
+	- BatchWriteItem does not support condition expressions, hence BOs are inserted in chunks of 25 using TransactWriteItems with
+	  "insert if not exist" conditions. Note: transactional writes consume 2x write capacity units.
+	- if a chunk is cancelled because some items already exist, those items are reported with godal.GdaoErrorDuplicatedEntry and the remaining
+	  items of the chunk are re-submitted. If a chunk fails for any other reason, its items are created one by one using GdaoCreateWithContext.
+	- if ctx is carrying a transaction (see GdaoWithTransaction), BOs are added to the transaction one by one.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCreateManyWithContext(ctx aws.Context, table string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreateMany", table)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	if dao.txFromContext(ctx) != nil {
+		for i, bo := range boList {
+			results[i].NumRows, results[i].Error = dao.GdaoCreateWithContext(ctx, table, bo)
+		}
+		return results, bulkError(results)
+	}
+	pkAttrs := dao.GetRowMapper().ColumnsList(table)
+	if pkAttrs == nil || len(pkAttrs) == 0 {
+		return nil, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	}
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	txItems := make([]*dynamodb.TransactWriteItem, 0, len(boList))
+	indexes := make([]int, 0, len(boList)) // indexes[i] is the index of the BO of txItems[i]
+	for i, bo := range boList {
+		if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, table, bo); err != nil {
+			results[i].Error = err
+		} else if item, err := dao.GetRowMapper().ToRow(table, bo); err != nil {
+			results[i].Error = err
+		} else if txItem, err := dao.dynamodbConnect.BuildTxPutIfNotExist(table, item, pkAttrs); err != nil {
+			results[i].Error = err
+		} else {
+			txItems = append(txItems, txItem)
+			indexes = append(indexes, i)
+		}
+	}
+	for start := 0; start < len(txItems); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(txItems) {
+			end = len(txItems)
+		}
+		dao.txCreateChunk(ctx, table, boList, txItems[start:end], indexes[start:end], results)
+	}
+	for _, i := range indexes {
+		results[i].NumRows, results[i].Error = dao.AfterWrite(ctx, godal.HookAfterCreate, table, boList[i], results[i].NumRows, results[i].Error)
+	}
+	return results, bulkError(results)
+}
+
+// txCreateChunk inserts a chunk of items using TransactWriteItems and stores per-BO results into 'results'.
+func (dao *GenericDaoDynamodb) txCreateChunk(ctx aws.Context, table string, boList []godal.IGenericBo, txItems []*dynamodb.TransactWriteItem, indexes []int, results []godal.GdaoBulkResult) {
+	err := dao.retry(ctx, func() (err error) {
+		_, err = dao.dynamodbConnect.ExecTxWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: txItems})
+		return err
+	})
+	if err == nil {
+		for _, i := range indexes {
+			results[i].NumRows = 1
+		}
+		return
+	}
+	if prom.IsAwsError(err, dynamodb.ErrCodeTransactionCanceledException) {
+		// cancellation reasons are listed in the error message, in the same order as write items: "...[None, ConditionalCheckFailed, ...]"
+		if matches := reTxCancellationReasons.FindStringSubmatch(err.Error()); matches != nil {
+			if reasons := strings.Split(matches[1], ","); len(reasons) == len(txItems) {
+				remainingItems, remainingIndexes := make([]*dynamodb.TransactWriteItem, 0), make([]int, 0)
+				for j, reason := range reasons {
+					if strings.TrimSpace(reason) == "ConditionalCheckFailed" {
+						results[indexes[j]].Error = godal.GdaoErrorDuplicatedEntry
+					} else {
+						remainingItems = append(remainingItems, txItems[j])
+						remainingIndexes = append(remainingIndexes, indexes[j])
+					}
+				}
+				if len(remainingItems) < len(txItems) {
+					if len(remainingItems) > 0 {
+						dao.txCreateChunk(ctx, table, boList, remainingItems, remainingIndexes, results)
+					}
+					return
+				}
+			}
+		}
+	}
+	// fall back to creating items one by one
+	for _, i := range indexes {
+		results[i].NumRows, results[i].Error = dao.createItem(ctx, table, boList[i])
+	}
+}
+
+// bulkError returns the first error of the bulk results.
+func bulkError(results []godal.GdaoBulkResult) error {
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+	}
+	return nil
+}
+
+/*
+GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdate(table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithContext(nil, table, bo)
+}
+
+/*
+GdaoUpdateWithContext is extended-implementation of godal.IGenericDao.GdaoUpdate.
+
+	- attributes of the BO are SET, top-level attributes removed via godal.IGenericBo.GboDeleteAttr are REMOVEd from the item.
+	- if optimistic locking is enabled and ctx is carrying a transaction (see GdaoWithTransaction), the commit fails with
+	  godal.GdaoErrorConcurrentModification if the item does not exist or its version does not match.
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdateWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdate", table)
+	defer func() { op.Finish(numRows, err) }()
+	return dao.updateItem(ctx, table, bo, false)
+}
+
+// updateItem updates an existing item; if dirtyOnly is true, only the BO's dirty attributes (see godal.DirtyAttrs) are SET.
+func (dao *GenericDaoDynamodb) updateItem(ctx aws.Context, table string, bo godal.IGenericBo, dirtyOnly bool) (int, error) {
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, table, bo); err != nil {
+		return 0, err
+	}
+	numRows, err := dao.doUpdateItem(ctx, table, bo, dirtyOnly)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, table, bo, numRows, err)
+}
+
+// doUpdateItem is the implementation of updateItem, hooks and validation excluded.
+func (dao *GenericDaoDynamodb) doUpdateItem(ctx aws.Context, table string, bo godal.IGenericBo, dirtyOnly bool) (int, error) {
+	var keyFilter, itemMap map[string]interface{}
+	var err error
+	if keyFilter, err = toMap(dao.GdaoCreateFilter(table, bo)); err != nil {
+		return 0, err
+	}
+	var item interface{}
+	if item, err = dao.GetRowMapper().ToRow(table, bo); err != nil {
+		return 0, err
+	} else if itemMap, err = toMap(item); err != nil {
+		return 0, err
+	}
+
+	if pkAttrs := dao.GetRowMapper().ColumnsList(table); pkAttrs == nil || len(pkAttrs) == 0 {
+		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	} else {
+		// remove primary key attributes from update list
+		isPk := make(map[string]bool, len(pkAttrs))
+		for _, pk := range pkAttrs {
+			delete(itemMap, pk)
+			isPk[pk] = true
+		}
+		var attrsToRemove []string
+		for _, attr := range bo.GboDeletedAttrs() {
+			if _, ok := itemMap[attr]; !ok && !isPk[attr] {
+				attrsToRemove = append(attrsToRemove, attr)
+			}
+		}
+		condition := prom.AwsDynamodbExistsAllBuilder(pkAttrs)
+		var failErr error
+		if versionCondition, err := dao.versionCondition(table, itemMap); err != nil {
+			return 0, err
+		} else if versionCondition != nil {
+			*condition = condition.And(*versionCondition)
+			failErr = godal.GdaoErrorConcurrentModification
+		}
+		if dirtyOnly {
+			dirtyItem := make(map[string]interface{})
+			for _, attr := range godal.DirtyAttrs(bo) {
+				if v, ok := itemMap[attr]; ok {
+					dirtyItem[attr] = v
+				}
+			}
+			if field := dao.GetVersionField(table); field != "" {
+				dirtyItem[field] = itemMap[field]
+			}
+			if itemMap = dirtyItem; len(itemMap) == 0 && len(attrsToRemove) == 0 {
+				return 0, nil
+			}
+		}
+		if tx := dao.txFromContext(ctx); tx != nil {
+			txItem, err := dao.dynamodbConnect.BuildTxUpdate(table, keyFilter, condition, attrsToRemove, itemMap, nil, nil)
+			if numRows, err := tx.add(txItem, failErr, err); err != nil {
+				return numRows, err
+			}
+			return 1, dao.setWrittenVersion(table, bo, itemMap, failErr != nil)
+		}
+		if err = dao.retry(ctx, func() (err error) {
+			_, err = dao.dynamodbConnect.UpdateItem(ctx, table, keyFilter, condition, attrsToRemove, itemMap, nil, nil)
+			return err
+		}); err != nil {
+			if failErr != nil && prom.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+				// the item does not exist, or its version does not match
+				if existing, err := dao.getItem(ctx, table, keyFilter); err != nil {
+					return 0, dao.TranslateError(err)
+				} else if existing != nil {
+					return 0, failErr
+				}
+			}
+			err = prom.AwsIgnoreErrorIfMatched(err, dynamodb.ErrCodeConditionalCheckFailedException)
+			return 0, dao.TranslateError(err)
+		}
+		return 1, dao.setWrittenVersion(table, bo, itemMap, failErr != nil)
+	}
+}
+
+/*
+GdaoUpdateDirty implements godal.IGenericDao.GdaoUpdateDirty.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdateDirty(table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithContext(nil, table, bo)
+}
+
+/*
+GdaoUpdateDirtyWithContext is extended-implementation of godal.IGenericDao.GdaoUpdateDirty.
+
+	- only the BO's dirty top-level attributes (see godal.DirtyAttrs) are SET, top-level attributes removed via
+	  godal.IGenericBo.GboDeleteAttr are REMOVEd from the item.
+	- transactions and optimistic locking are handled the same way as GdaoUpdateWithContext.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdateDirtyWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdateDirty", table)
+	defer func() { op.Finish(numRows, err) }()
+	if !bo.GboIsDirty() {
+		return 0, nil
+	}
+	return dao.updateItem(ctx, table, bo, true)
+}
+
+/*
+GdaoPatch implements godal.IGenericDao.GdaoPatch.
+*/
+func (dao *GenericDaoDynamodb) GdaoPatch(table string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithContext(nil, table, bo, patch)
+}
+
+/*
+GdaoPatchWithContext is extended-implementation of godal.IGenericDao.GdaoPatch.
+
+Patch operators are mapped to UpdateItem expressions: PatchOpSet to "SET", PatchOpUnset to "REMOVE", PatchOpIncrement to "ADD"
+and PatchOpAppend to "SET field = list_append(if_not_exists(field, []), [value])".
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoPatchWithContext(ctx aws.Context, table string, bo godal.IGenericBo, patch *godal.PatchOpt) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoPatch", table)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforePatch(ctx, table, bo, patch); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.patchItem(ctx, table, bo, patch)
+	return dao.AfterPatch(ctx, table, bo, patch, numRows, err)
+}
+
+// patchItem patches an existing item, see GdaoPatchWithContext.
+func (dao *GenericDaoDynamodb) patchItem(ctx aws.Context, table string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	if patch == nil || len(patch.Fields) == 0 {
+		return 0, errors.New("patch must have at least one field")
+	}
+	pkAttrs := dao.GetRowMapper().ColumnsList(table)
+	if pkAttrs == nil || len(pkAttrs) == 0 {
+		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	}
+	keyFilter, err := toMap(dao.GdaoCreateFilter(table, bo))
+	if err != nil {
+		return 0, err
+	}
+	key, err := dynamodbattribute.MarshalMap(keyFilter)
+	if err != nil {
+		return 0, err
+	}
+	var update expression.UpdateBuilder
+	for _, f := range patch.Fields {
+		name := expression.Name(f.Field)
+		switch f.Operator {
+		case godal.PatchOpSet:
+			update = update.Set(name, expression.Value(f.Value))
+		case godal.PatchOpUnset:
+			update = update.Remove(name)
+		case godal.PatchOpIncrement:
+			update = update.Add(name, expression.Value(f.Value))
+		case godal.PatchOpAppend:
+			update = update.Set(name, expression.ListAppend(expression.IfNotExists(name, expression.Value([]interface{}{})), expression.Value([]interface{}{f.Value})))
+		default:
+			return 0, errors.New(fmt.Sprintf("unsupported patch operator %d", f.Operator))
+		}
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(*prom.AwsDynamodbExistsAllBuilder(pkAttrs)).Build()
+	if err != nil {
+		return 0, err
+	}
+	if tx := dao.txFromContext(ctx); tx != nil {
+		txItem, err := dao.dynamodbConnect.BuildTxUpdateRaw(table, key, expr)
+		return tx.add(txItem, nil, err)
+	}
+	err = dao.retry(ctx, func() (err error) {
+		_, err = dao.dynamodbConnect.UpdateItemWithInput(ctx, &dynamodb.UpdateItemInput{
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			Key:                       key,
+			TableName:                 aws.String(table),
+			UpdateExpression:          expr.Update(),
+		})
+		return err
+	})
+	if err != nil {
+		if prom.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+			return 0, nil
+		}
+		return 0, dao.TranslateError(err)
+	}
+	return 1, nil
+}
+
+/*
+GdaoSave implements godal.IGenericDao.GdaoSave.
+*/
+func (dao *GenericDaoDynamodb) GdaoSave(table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveWithContext(nil, table, bo)
+}
+
+/*
+GdaoSaveWithContext is extended-implementation of godal.IGenericDao.GdaoSave.
+*/
+func (dao *GenericDaoDynamodb) GdaoSaveWithContext(ctx aws.Context, table string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSave", table)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeSave, table, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.saveItem(ctx, table, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterSave, table, bo, numRows, err)
+}
+
+// saveItem puts an item, see GdaoSaveWithContext.
+func (dao *GenericDaoDynamodb) saveItem(ctx aws.Context, table string, bo godal.IGenericBo) (int, error) {
+	pkAttrs := dao.GetRowMapper().ColumnsList(table)
+	if pkAttrs == nil || len(pkAttrs) == 0 {
+		return 0, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	}
+	item, err := dao.GetRowMapper().ToRow(table, bo)
+	if err != nil {
+		return 0, err
+	}
+	var condition *expression.ConditionBuilder
+	versioned := false
+	if field := dao.GetVersionField(table); field != "" {
+		// optimistic locking: put the item if it does not exist or its version matches
+		itemMap, err := toMap(item)
+		if err != nil {
+			return 0, err
+		}
+		versionCondition, err := dao.versionCondition(table, itemMap)
+		if err != nil {
+			return 0, err
+		}
+		condition = prom.AwsDynamodbNotExistsAllBuilder(pkAttrs)
+		*condition = condition.Or(*versionCondition)
+		item, versioned = itemMap, true
+	}
+	if tx := dao.txFromContext(ctx); tx != nil {
+		txItem, err := dao.dynamodbConnect.BuildTxPut(table, item, condition)
+		if numRows, err := tx.add(txItem, godal.GdaoErrorConcurrentModification, err); err != nil {
+			return numRows, err
+		}
+		return 1, dao.setWrittenVersion(table, bo, item, versioned)
+	}
+	if err := dao.retry(ctx, func() (err error) {
+		_, err = dao.dynamodbConnect.PutItem(ctx, table, item, condition)
+		return err
+	}); err != nil {
+		if prom.IsAwsError(err, dynamodb.ErrCodeConditionalCheckFailedException) {
+			return 0, godal.GdaoErrorConcurrentModification
+		}
+		return 0, dao.TranslateError(err)
+	}
+	return 1, dao.setWrittenVersion(table, bo, item, versioned)
+}
+
+// setWrittenVersion sets the version written to the stored item (found in 'item', see versionCondition) to bo if 'written' is true.
+func (dao *GenericDaoDynamodb) setWrittenVersion(table string, bo godal.IGenericBo, item interface{}, written bool) error {
+	if !written {
+		return nil
+	}
+	version, err := reddo.ToInt(item.(map[string]interface{})[dao.GetVersionField(table)])
+	if err != nil {
+		return err
+	}
+	return dao.SetBoVersion(table, bo, version)
+}
+
+/*
+versionCondition returns the condition matching the version of 'item' if optimistic locking is enabled on the table
+(see godal.AbstractGenericDao.SetVersionField), and increments the version in 'item'. nil is returned otherwise.
+*/
+func (dao *GenericDaoDynamodb) versionCondition(table string, item map[string]interface{}) (*expression.ConditionBuilder, error) {
+	field := dao.GetVersionField(table)
+	if field == "" {
+		return nil, nil
+	}
+	current, next, err := godal.NextVersion(item, field)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := filterOptToConditionBuilder(godal.VersionFilter(field, current))
+	if err != nil {
+		return nil, err
+	}
+	item[field] = next
+	return &condition, nil
+}
+
+/*
+GdaoSaveMany implements godal.IGenericDao.GdaoSaveMany.
+*/
+func (dao *GenericDaoDynamodb) GdaoSaveMany(table string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyWithContext(nil, table, boList)
+}
+
+const (
+	// maxBatchWriteRetries is the number of times unprocessed items of a BatchWriteItem call are retried.
+	maxBatchWriteRetries = 5
+	// batchWriteRetryBackoff is the delay before the first retry, it doubles after each retry.
+	batchWriteRetryBackoff = 50 * time.Millisecond
+)
+
+/*
+GdaoSaveManyWithContext is extended-implementation of godal.IGenericDao.GdaoSaveMany.
+
+	- BOs are saved in chunks of 25 using BatchWriteItem. Unprocessed items are retried with exponential backoff; items that are still
+	  unprocessed after retries are reported with error. If a chunk fails, its items are saved one by one using GdaoSaveWithContext.
+	- BOs targeting the same item are put into different chunks, chunks are written in order: the last BO wins, as if BOs were saved
+	  one by one.
+	- if ctx is carrying a transaction (see GdaoWithTransaction), BOs are added to the transaction one by one.
+	- if optimistic locking is enabled on the table (see godal.AbstractGenericDao.SetVersionField), BOs are saved one by one using
+	  GdaoSaveWithContext (BatchWriteItem does not support condition expressions).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoSaveManyWithContext(ctx aws.Context, table string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSaveMany", table)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	if dao.txFromContext(ctx) != nil || dao.GetVersionField(table) != "" {
+		for i, bo := range boList {
+			results[i].NumRows, results[i].Error = dao.GdaoSaveWithContext(ctx, table, bo)
+		}
+		return results, bulkError(results)
+	}
+	pkAttrs := dao.GetRowMapper().ColumnsList(table)
+	if pkAttrs == nil || len(pkAttrs) == 0 {
+		return nil, errors.New(fmt.Sprintf("cannot find primary-key attribute list for table [%s]", table))
+	}
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	requests := make([]*dynamodb.WriteRequest, 0, len(boList))
+	indexes := make([]int, 0, len(boList)) // indexes[i] is the index of the BO of requests[i]
+	for i, bo := range boList {
+		if err := dao.BeforeWrite(ctx, godal.HookBeforeSave, table, bo); err != nil {
+			results[i].Error = err
+		} else if item, err := dao.GetRowMapper().ToRow(table, bo); err != nil {
+			results[i].Error = err
+		} else if av, err := dynamodbattribute.MarshalMap(item); err != nil {
+			results[i].Error = err
+		} else {
+			requests = append(requests, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: av}})
+			indexes = append(indexes, i)
+		}
+	}
+	for start := 0; start < len(requests); {
+		end := batchChunkEnd(requests, start, pkAttrs)
+		dao.batchSaveChunk(ctx, table, pkAttrs, boList, requests[start:end], indexes[start:end], results)
+		start = end
+	}
+	for _, i := range indexes {
+		results[i].NumRows, results[i].Error = dao.AfterWrite(ctx, godal.HookAfterSave, table, boList[i], results[i].NumRows, results[i].Error)
+	}
+	return results, bulkError(results)
+}
+
+// itemKey builds a string identifying an item by its primary key attributes.
+func itemKey(item map[string]*dynamodb.AttributeValue, pkAttrs []string) string {
+	key := make([]string, len(pkAttrs))
+	for i, pk := range pkAttrs {
+		key[i] = item[pk].String()
+	}
+	return strings.Join(key, "\x00")
+}
+
+// batchChunkEnd returns the end (exclusive) of the chunk of requests starting at 'start': a chunk holds at most maxBatchWriteItems
+// requests, and ends before a request targeting an item already targeted in the chunk (BatchWriteItem rejects such chunks).
+func batchChunkEnd(requests []*dynamodb.WriteRequest, start int, pkAttrs []string) int {
+	keys := make(map[string]bool)
+	end := start
+	for ; end < len(requests) && end-start < maxBatchWriteItems; end++ {
+		key := itemKey(requests[end].PutRequest.Item, pkAttrs)
+		if keys[key] {
+			break
+		}
+		keys[key] = true
+	}
+	return end
+}
+
+// batchSaveChunk saves a chunk of items using BatchWriteItem and stores per-BO results into 'results'.
+func (dao *GenericDaoDynamodb) batchSaveChunk(ctx aws.Context, table string, pkAttrs []string, boList []godal.IGenericBo, requests []*dynamodb.WriteRequest, indexes []int, results []godal.GdaoBulkResult) {
+	pending := make(map[string]int) // item key -> BO index
+	for j, req := range requests {
+		pending[itemKey(req.PutRequest.Item, pkAttrs)] = indexes[j]
+	}
+	backoff := batchWriteRetryBackoff
+	for retry := 0; len(requests) > 0; retry++ {
+		if retry > maxBatchWriteRetries {
+			for _, i := range pending {
+				results[i].Error = godal.WrapError(godal.ErrThrottled, errors.New(fmt.Sprintf("item was not processed by BatchWriteItem after %d retries", maxBatchWriteRetries)))
+			}
+			return
+		}
+		if retry > 0 {
+			select {
+			case <-ctx.Done():
+				for _, i := range pending {
+					results[i].Error = dao.TranslateError(ctx.Err())
+				}
+				return
+			case <-time.After(backoff):
+				backoff *= 2
+			}
+		}
+		output, err := dao.dynamodbConnect.GetDb().BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]*dynamodb.WriteRequest{table: requests},
+		})
+		if err != nil {
+			// fall back to saving pending items one by one
+			for _, i := range pending {
+				results[i].NumRows, results[i].Error = dao.saveItem(ctx, table, boList[i])
+			}
+			return
+		}
+		requests = output.UnprocessedItems[table]
+		unprocessed := make(map[string]int)
+		for _, req := range requests {
+			key := itemKey(req.PutRequest.Item, pkAttrs)
+			unprocessed[key] = pending[key]
+			delete(pending, key)
+		}
+		for _, i := range pending {
+			results[i].NumRows = 1
+		}
+		pending = unprocessed
+	}
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+GdaoDeleteCtx implements godal.IGenericDaoWithContext.GdaoDeleteCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoDeleteCtx(ctx context.Context, table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoDeleteWithContext(ctx, table, bo)
+}
+
+/*
+GdaoDeleteManyCtx implements godal.IGenericDaoWithContext.GdaoDeleteManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoDeleteManyCtx(ctx context.Context, table string, filter interface{}) (int, error) {
+	return dao.GdaoDeleteManyWithContext(ctx, table, filter)
+}
+
+/*
+GdaoFetchOneCtx implements godal.IGenericDaoWithContext.GdaoFetchOneCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchOneCtx(ctx context.Context, table string, filter interface{}) (godal.IGenericBo, error) {
+	return dao.GdaoFetchOneWithContext(ctx, table, filter)
+}
+
+/*
+GdaoFetchManyCtx implements godal.IGenericDaoWithContext.GdaoFetchManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchManyCtx(ctx context.Context, table string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
+	return dao.GdaoFetchManyWithContext(ctx, table, filter, sorting, startOffset, numItems)
+}
+
+/*
+GdaoFetchIteratorCtx implements godal.IGenericDaoWithContext.GdaoFetchIteratorCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchIteratorCtx(ctx context.Context, table string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithContext(ctx, table, filter, sorting)
+}
+
+/*
+GdaoFetchPageCtx implements godal.IGenericDaoWithContext.GdaoFetchPageCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoFetchPageCtx(ctx context.Context, table string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithContext(ctx, table, filter, sorting, pageToken, numItems)
+}
+
+/*
+GdaoCountCtx implements godal.IGenericDaoWithContext.GdaoCountCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCountCtx(ctx context.Context, table string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithContext(ctx, table, filter)
+}
+
+/*
+GdaoExistsCtx implements godal.IGenericDaoWithContext.GdaoExistsCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoExistsCtx(ctx context.Context, table string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithContext(ctx, table, filter)
+}
+
+/*
+GdaoCreateCtx implements godal.IGenericDaoWithContext.GdaoCreateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCreateCtx(ctx context.Context, table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithContext(ctx, table, bo)
+}
+
+/*
+GdaoCreateManyCtx implements godal.IGenericDaoWithContext.GdaoCreateManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoCreateManyCtx(ctx context.Context, table string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithContext(ctx, table, boList)
+}
+
+/*
+GdaoUpdateCtx implements godal.IGenericDaoWithContext.GdaoUpdateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdateCtx(ctx context.Context, table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithContext(ctx, table, bo)
+}
+
+/*
+GdaoPatchCtx implements godal.IGenericDaoWithContext.GdaoPatchCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoPatchCtx(ctx context.Context, table string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithContext(ctx, table, bo, patch)
+}
+
+/*
+GdaoUpdateDirtyCtx implements godal.IGenericDaoWithContext.GdaoUpdateDirtyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoUpdateDirtyCtx(ctx context.Context, table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithContext(ctx, table, bo)
+}
+
+/*
+GdaoSaveCtx implements godal.IGenericDaoWithContext.GdaoSaveCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoSaveCtx(ctx context.Context, table string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveWithContext(ctx, table, bo)
+}
+
+/*
+GdaoSaveManyCtx implements godal.IGenericDaoWithContext.GdaoSaveManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoSaveManyCtx(ctx context.Context, table string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyWithContext(ctx, table, boList)
+}
+
+/*----------------------------------------------------------------------*/
+
+type ctxKeyTx struct{}
+
+// dynamodbTx buffers write operations of a transaction, they are committed at once via TransactWriteItems.
+type dynamodbTx struct {
+	dao        *GenericDaoDynamodb
+	lock       sync.Mutex
+	ended      bool // txFunc has returned, no more write operation is accepted
+	writeItems []*dynamodb.TransactWriteItem
+	failErrors []error // error to return if the corresponding write item's condition check fails
+}
+
+// add appends a write item to the transaction buffer, returns (1, nil) if successful.
+// godal.ErrTxEnded is returned if the transaction has ended.
+func (tx *dynamodbTx) add(txItem *dynamodb.TransactWriteItem, errIfConditionFailed error, err error) (int, error) {
+	if err != nil {
+		return 0, err
+	}
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	if tx.ended {
+		return 0, godal.ErrTxEnded
+	}
+	tx.writeItems = append(tx.writeItems, txItem)
+	tx.failErrors = append(tx.failErrors, errIfConditionFailed)
+	return 1, nil
+}
+
+// end marks the transaction as ended, see add.
+func (tx *dynamodbTx) end() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.ended = true
+}
+
+// checkEnded returns godal.ErrTxEnded if the transaction has ended.
+func (tx *dynamodbTx) checkEnded() error {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	if tx.ended {
+		return godal.ErrTxEnded
+	}
+	return nil
+}
+
+// txFromContext returns the transaction of this dao carried by ctx, nil if ctx is not carrying any.
+func (dao *GenericDaoDynamodb) txFromContext(ctx context.Context) *dynamodbTx {
+	if ctx == nil {
+		return nil
+	}
+	if tx, ok := ctx.Value(ctxKeyTx{}).(*dynamodbTx); ok && tx.dao == dao {
+		return tx
+	}
+	return nil
+}
+
+// boundDao returns the DAO (the outer one if possible) bound to ctx.
+func (dao *GenericDaoDynamodb) boundDao(ctx context.Context) godal.IGenericDao {
+	if gdao, ok := dao.IGenericDao.(godal.IGenericDaoWithContext); ok {
+		return godal.NewContextBoundGenericDao(ctx, gdao)
+	}
+	return godal.NewContextBoundGenericDao(ctx, dao)
+}
+
+// txGetItem fetches an item using TransactGetItems.
+func (dao *GenericDaoDynamodb) txGetItem(ctx aws.Context, table string, keyFilter map[string]interface{}) (prom.AwsDynamodbItem, error) {
+	txItem, err := dao.dynamodbConnect.BuildTxGet(table, keyFilter)
+	if err != nil {
+		return nil, err
+	}
+	var dbResult *dynamodb.TransactGetItemsOutput
+	err = dao.retry(ctx, func() (err error) {
+		dbResult, err = dao.dynamodbConnect.ExecTxGetItems(ctx, &dynamodb.TransactGetItemsInput{TransactItems: []*dynamodb.TransactGetItem{txItem}})
+		return err
+	})
+	if err != nil || len(dbResult.Responses) == 0 || dbResult.Responses[0].Item == nil {
+		return nil, prom.AwsIgnoreErrorIfMatched(err, dynamodb.ErrCodeResourceNotFoundException)
+	}
+	item := prom.AwsDynamodbItem{}
+	return item, dynamodbattribute.UnmarshalMap(dbResult.Responses[0].Item, &item)
+}
+
+var reTxCancellationReasons = regexp.MustCompile(`\[([^\]]*)\]`)
+
+// txCommitError translates error returned by TransactWriteItems.
+//
+// If the transaction was cancelled because a condition check failed, the error associated with the failed write item is returned (if any).
+func (tx *dynamodbTx) txCommitError(err error) error {
+	if !prom.IsAwsError(err, dynamodb.ErrCodeTransactionCanceledException) {
+		return err
+	}
+	// cancellation reasons are listed in the error message, in the same order as write items: "...[None, ConditionalCheckFailed, ...]"
+	if matches := reTxCancellationReasons.FindStringSubmatch(err.Error()); matches != nil {
+		for i, reason := range strings.Split(matches[1], ",") {
+			if strings.TrimSpace(reason) == "ConditionalCheckFailed" && i < len(tx.failErrors) && tx.failErrors[i] != nil {
+				return tx.failErrors[i]
+			}
+		}
+	}
+	return err
+}
+
+/*
+GdaoWithTransaction implements godal.ITransactionalDao.GdaoWithTransaction.
+
+DynamoDB transactions are mapped onto TransactWriteItems/TransactGetItems:
+
+	- write operations (GdaoCreate, GdaoUpdate, GdaoPatch, GdaoUpdateDirty, GdaoSave, GdaoDelete, GdaoDeleteMany) made inside txFunc are buffered and return (1, nil) immediately;
+	  buffered writes are committed at once via TransactWriteItems after txFunc returns without error.
+	- GdaoFetchOne made inside txFunc uses TransactGetItems; it does not see writes buffered by the same transaction.
+	- GdaoFetchMany and the scan part of GdaoDeleteMany are not transactional.
+	- if a GdaoCreate's item already exists the whole transaction is cancelled and godal.GdaoErrorDuplicatedEntry is returned;
+	  if a GdaoUpdate's item does not exist the whole transaction is cancelled and the AWS error is returned.
+	- DynamoDB limits the number of items in a transaction, and an item can not be targeted by more than one operation in the same transaction.
+	- if the transaction fails with a retryable error (e.g. transaction conflict), a new transaction is started and txFunc is re-run
+	  according to the DAO's retry policy (see godal.AbstractGenericDao.SetRetryPolicy); txFunc should have no side effects outside the transaction.
+	- once txFunc returns, operations made with txCtx return godal.ErrTxEnded (instead of being buffered and never committed); contexts
+	  carrying a transaction of another DAO do not join it.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoDynamodb) GdaoWithTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao godal.IGenericDao) error) error {
+	if tx := dao.txFromContext(ctx); tx != nil {
+		if err := tx.checkEnded(); err != nil {
+			return err
+		}
+		return txFunc(ctx, dao.boundDao(ctx))
+	}
+	if ctx == nil {
+		ctx, _ = dao.dynamodbConnect.NewContext()
+	}
+	return dao.retry(ctx, func() error {
+		return dao.withTransaction(ctx, txFunc)
+	})
+}
+
+// withTransaction runs txFunc and commits the buffered writes, see GdaoWithTransaction.
+func (dao *GenericDaoDynamodb) withTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao godal.IGenericDao) error) error {
+	tx := &dynamodbTx{dao: dao}
+	txCtx := context.WithValue(ctx, ctxKeyTx{}, tx)
+	err := func() error {
+		defer tx.end()
+		return txFunc(txCtx, dao.boundDao(txCtx))
+	}()
+	if err != nil {
+		return err
+	}
+	if len(tx.writeItems) == 0 {
+		return nil
 	}
+	_, err = dao.dynamodbConnect.ExecTxWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: tx.writeItems})
+	return dao.TranslateError(tx.txCommitError(err))
 }
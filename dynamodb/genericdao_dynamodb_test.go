@@ -1,14 +1,18 @@
 package dynamodb
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/prom"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
@@ -567,6 +571,279 @@ func TestGenericDaoDynamodb_GdaoFetchManyWithPaging(t *testing.T) {
 	}
 }
 
+func TestGenericDaoDynamodb_GdaoFetchManyWithSorting(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoFetchManyWithSorting"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	time.Sleep(5 * time.Second) // sleep a few seconds due to eventually consistent
+
+	filter := godal.FilterAnd(godal.FilterEq(fieldActived, 1), godal.FilterGte(fieldVersion, 80))
+	sorting := godal.NewSortingOpt().Add(fieldVersion, godal.SortOrderDesc)
+	gboList, err := dao.GdaoFetchMany(dao.tableName+":"+indexName, filter, sorting, 5, 10)
+	if err != nil || gboList == nil || len(gboList) != 10 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(gboList), err)
+	}
+	for i, gbo := range gboList {
+		if bo := fromGbo(gbo); bo.Version != 94-i {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, 94-i, bo)
+		}
+	}
+
+	if _, err := dao.GdaoFetchMany(dao.tableName+":"+indexName, filter, godal.NewSortingOpt().Add(fieldUsername, godal.SortOrderAsc), 0, 0); err == nil {
+		t.Fatalf("%s failed - Expected error when sorting by non-sort-key attribute", name)
+	}
+	if _, err := dao.GdaoFetchMany(dao.tableName, nil, sorting, 0, 0); err == nil {
+		t.Fatalf("%s failed - Expected error when sorting a table without sort key", name)
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoFetchIterator(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoFetchIterator"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	time.Sleep(5 * time.Second) // sleep a few seconds due to eventually consistent
+
+	iter, err := dao.GdaoFetchIterator(dao.tableName, godal.FilterGte(fieldVersion, 80), nil)
+	if err != nil || iter == nil {
+		t.Fatalf("%s failed - Iterator: %v / Error: %e", name, iter, err)
+	}
+	defer func() { _ = iter.Close() }()
+	versions := make(map[int]bool)
+	for iter.Next() {
+		bo := fromGbo(iter.Bo())
+		if bo.Version < 80 || versions[bo.Version] {
+			t.Fatalf("%s failed - Unexpected BO: %v", name, bo)
+		}
+		versions[bo.Version] = true
+	}
+	if err := iter.Err(); err != nil || len(versions) != 20 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(versions), err)
+	}
+}
+
+func TestGenericDaoDynamodb_PageToken(t *testing.T) {
+	name := "TestGenericDaoDynamodb_PageToken"
+	if token, err := encodePageToken(nil); err != nil || token != "" {
+		t.Fatalf("%s failed - Token: %v / Error: %e", name, token, err)
+	}
+	key := map[string]*dynamodb.AttributeValue{
+		"id":      {S: aws.String("1")},
+		"version": {N: aws.String("2")},
+		"data":    {B: []byte("3")},
+	}
+	token, err := encodePageToken(key)
+	if err != nil || token == "" {
+		t.Fatalf("%s failed - Token: %v / Error: %e", name, token, err)
+	}
+	if decoded, err := decodePageToken(token); err != nil || !reflect.DeepEqual(decoded, key) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, key, decoded, err)
+	}
+	for _, token := range []string{"invalid-token!", "e30", "eyJpZCI6e319"} {
+		if _, err := decodePageToken(token); err != godal.GdaoErrorInvalidPageToken {
+			t.Fatalf("%s failed - Expected error %e / Received: %e", name, godal.GdaoErrorInvalidPageToken, err)
+		}
+	}
+	if _, err := encodePageToken(map[string]*dynamodb.AttributeValue{"id": {BOOL: aws.Bool(true)}}); err == nil {
+		t.Fatalf("%s failed - Expected error for unsupported key type", name)
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoFetchPage(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoFetchPage"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	time.Sleep(5 * time.Second) // sleep a few seconds due to eventually consistent
+
+	pageToken, versions := "", make(map[int]bool)
+	for numPages := 1; ; numPages++ {
+		boList, nextPageToken, err := dao.GdaoFetchPage(dao.tableName, godal.FilterGte(fieldVersion, 80), nil, pageToken, 7)
+		if err != nil || len(boList) > 7 || numPages > numItems/7+2 {
+			t.Fatalf("%s failed - Page: %v / NumItems: %v / Error: %e", name, numPages, len(boList), err)
+		}
+		for _, gbo := range boList {
+			bo := fromGbo(gbo)
+			if bo.Version < 80 || versions[bo.Version] {
+				t.Fatalf("%s failed - Unexpected BO: %v", name, bo)
+			}
+			versions[bo.Version] = true
+		}
+		if pageToken = nextPageToken; pageToken == "" {
+			break
+		}
+	}
+	if len(versions) != 20 {
+		t.Fatalf("%s failed - Expected %v items / Received: %v", name, 20, len(versions))
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoCountExists(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoCountExists"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	time.Sleep(5 * time.Second) // sleep a few seconds due to eventually consistent
+
+	if count, err := dao.GdaoCount(dao.tableName, nil); err != nil || count != int64(numItems) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, numItems, count, err)
+	}
+	if count, err := dao.GdaoCount(dao.tableName, godal.FilterGte(fieldVersion, 80)); err != nil || count != 20 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 20, count, err)
+	}
+	if exists, err := dao.GdaoExists(dao.tableName, map[string]interface{}{fieldId: "99"}); err != nil || !exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, true, exists, err)
+	}
+	if exists, err := dao.GdaoExists(dao.tableName, godal.FilterGte(fieldVersion, numItems)); err != nil || exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, false, exists, err)
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoCreateManySaveMany(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoCreateManySaveMany"
+	dao := initDao()
+	for i := 0; i < 4; i += 2 {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// BOs "0" and "2" already exist; more than 25 BOs so that they are written in several chunks
+	numItems := 30
+	boList := make([]godal.IGenericBo, numItems)
+	for i := range boList {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: "user" + strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		boList[i] = bo.ToGbo()
+	}
+	results, err := dao.GdaoCreateMany(dao.tableName, boList)
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if i < 4 && i%2 == 0 && (r.Error != godal.GdaoErrorDuplicatedEntry || r.NumRows != 0) {
+			t.Fatalf("%s failed - Item: %d / Expected error: %e / Result: %v", name, i, godal.GdaoErrorDuplicatedEntry, r)
+		}
+		if (i >= 4 || i%2 != 0) && (r.Error != nil || r.NumRows != 1) {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+
+	results, err = dao.GdaoSaveMany(dao.tableName, boList)
+	if err != nil || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if r.Error != nil || r.NumRows != 1 {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+
+	time.Sleep(5 * time.Second) // sleep a few seconds due to eventually consistent
+
+	if count, err := dao.GdaoCount(dao.tableName, nil); err != nil || count != int64(numItems) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, numItems, count, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "2"}); err != nil || gbo == nil || fromGbo(gbo).Username != "user2" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	// BOs targeting the same item are all saved, the last one wins
+	boList = []godal.IGenericBo{(&MyBo{Id: "1", Username: "first", Name: "BO - 1"}).ToGbo(), (&MyBo{Id: "3", Username: "user3", Name: "BO - 3"}).ToGbo(),
+		(&MyBo{Id: "1", Username: "last", Name: "BO - 1"}).ToGbo()}
+	results, err = dao.GdaoSaveMany(dao.tableName, boList)
+	if err != nil || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if r.Error != nil || r.NumRows != 1 {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || fromGbo(gbo).Username != "last" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoDynamodb_BatchChunkEnd(t *testing.T) {
+	name := "TestGenericDaoDynamodb_BatchChunkEnd"
+	pkAttrs := []string{"id"}
+	newRequests := func(ids ...int) []*dynamodb.WriteRequest {
+		requests := make([]*dynamodb.WriteRequest, len(ids))
+		for i, id := range ids {
+			item := map[string]*dynamodb.AttributeValue{"id": {S: aws.String(strconv.Itoa(id))}, "name": {S: aws.String(strconv.Itoa(i))}}
+			requests[i] = &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}}
+		}
+		return requests
+	}
+
+	// a chunk ends before a request targeting an item already in the chunk
+	requests := newRequests(1, 2, 1, 3, 1)
+	var chunks [][]int
+	for start := 0; start < len(requests); {
+		end := batchChunkEnd(requests, start, pkAttrs)
+		chunks = append(chunks, []int{start, end})
+		start = end
+	}
+	if expected := [][]int{{0, 2}, {2, 4}, {4, 5}}; !reflect.DeepEqual(chunks, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, chunks)
+	}
+
+	// a chunk holds at most maxBatchWriteItems requests
+	ids := make([]int, maxBatchWriteItems+5)
+	for i := range ids {
+		ids[i] = i
+	}
+	if end := batchChunkEnd(newRequests(ids...), 0, pkAttrs); end != maxBatchWriteItems {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, maxBatchWriteItems, end)
+	}
+}
+
 func TestGenericDaoDynamodb_GdaoUpdateNotExist(t *testing.T) {
 	name := "TestGenericDaoDynamodb_GdaoUpdateNotExist"
 	dao := initDao()
@@ -621,6 +898,451 @@ func TestGenericDaoDynamodb_GdaoUpdate(t *testing.T) {
 	}
 }
 
+func TestGenericDaoDynamodb_GdaoPatch(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoPatch"
+	dao := initDao()
+	for i := 1; i <= 2; i++ {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	bo := (&MyBo{Id: "1"}).ToGbo()
+
+	patch := godal.NewPatchOpt().Set("name", "patched").Increment("version", 2).Append("tags", "a")
+	if numRows, err := dao.GdaoPatch(dao.tableName, (&MyBo{Id: "9"}).ToGbo(), patch); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoPatch(dao.tableName, bo, patch); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if myBo := fromGbo(gbo); myBo.Name != "patched" || myBo.Version != 3 || myBo.Username != "1" {
+		t.Fatalf("%s failed - Received: %v", name, myBo)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || fmt.Sprint(tags) != "[a]" {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if numRows, err := dao.GdaoPatch(dao.tableName, bo, godal.NewPatchOpt().Unset("name").Append("tags", "b")); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, _ = dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"})
+	if v, err := gbo.GboGetAttr("name", nil); err != nil || v != nil {
+		t.Fatalf("%s failed - Name: %v / Error: %e", name, v, err)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || fmt.Sprint(tags) != "[a b]" {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if _, err := dao.GdaoPatch(dao.tableName, bo, godal.NewPatchOpt().Increment(fieldUsername, 1)); err == nil {
+		t.Fatalf("%s failed - Expected error for incrementing a string field", name)
+	}
+	if _, err := dao.GdaoPatch(dao.tableName, bo, godal.NewPatchOpt()); err == nil {
+		t.Fatalf("%s failed - Expected error for empty patch", name)
+	}
+}
+
+func TestGenericDaoDynamodb_GboDeleteAttr(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GboDeleteAttr"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || !gbo.GboHasAttr("name") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdate(dao.tableName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err = dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || gbo.GboHasAttr("name") || !gbo.GboHasAttr("username") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoUpdateDirty(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoUpdateDirty"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchGbo := func(id string) godal.IGenericBo {
+		gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil || gbo.GboIsDirty() {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return gbo
+	}
+	gbo1, gbo2 := fetchGbo("1"), fetchGbo("1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.tableName, gbo1); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// writers modifying different attributes do not overwrite each other's changes
+	gbo1.GboSetAttr("name", "changed")
+	gbo2.GboSetAttr(fieldUsername, "u2")
+	for _, gbo := range []godal.IGenericBo{gbo1, gbo2} {
+		if numRows, err := dao.GdaoUpdateDirty(dao.tableName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "changed" || bo.Username != "u2" || bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// removed attributes are removed from storage
+	gbo := fetchGbo("1")
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdateDirty(dao.tableName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo = fetchGbo("1"); gbo.GboHasAttr("name") || !gbo.GboHasAttr(fieldUsername) {
+		t.Fatalf("%s failed - Gbo: %v", name, gbo)
+	}
+
+	gbo = (&MyBo{Id: "9", Username: "9"}).ToGbo()
+	gbo.GboSetAttr("name", "changed")
+	if numRows, err := dao.GdaoUpdateDirty(dao.tableName, gbo); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// optimistic locking
+	dao.SetVersionField(dao.tableName, "version")
+	gbo1, gbo2 = fetchGbo("1"), fetchGbo("1")
+	gbo1.GboSetAttr("name", "v1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.tableName, gbo1); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo2.GboSetAttr(fieldUsername, "stale")
+	if _, err := dao.GdaoUpdateDirty(dao.tableName, gbo2); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "v1" || bo.Username != "u2" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+}
+
+func TestGenericDaoDynamodb_Validator(t *testing.T) {
+	name := "TestGenericDaoDynamodb_Validator"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.tableName, &godal.BoSchema{
+		Type:       godal.SchemaTypeObject,
+		Required:   []string{fieldId, fieldUsername},
+		Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}},
+	})
+	isValidationError := func(err error) bool {
+		_, ok := err.(*godal.ValidationError)
+		return ok
+	}
+	if numRows, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	invalidBo := (&MyBo{Id: "2", Username: "2"}).ToGbo()
+	if _, err := dao.GdaoCreate(dao.tableName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoSave(dao.tableName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.tableName, []godal.IGenericBo{(&MyBo{Id: "3", Username: "3", Name: "BO"}).ToGbo(), invalidBo})
+	if !isValidationError(err) || len(results) != 2 || results[0].Error != nil || results[0].NumRows != 1 || !isValidationError(results[1].Error) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if exists, err := dao.GdaoExists(dao.tableName, map[string]interface{}{fieldId: "2"}); err != nil || exists {
+		t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || fromGbo(gbo).Name != "BO" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	dao.SetValidator(dao.tableName, nil)
+	if numRows, err := dao.GdaoCreate(dao.tableName, invalidBo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoDynamodb_Hooks(t *testing.T) {
+	name := "TestGenericDaoDynamodb_Hooks"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.tableName, &godal.BoSchema{Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}}})
+	counters := make(map[godal.HookPoint]int)
+	errAbort := errors.New("abort")
+	for _, point := range []godal.HookPoint{godal.HookBeforeCreate, godal.HookAfterCreate, godal.HookBeforeUpdate, godal.HookAfterUpdate,
+		godal.HookBeforeSave, godal.HookAfterSave, godal.HookBeforeDelete, godal.HookAfterDelete, godal.HookAfterFetch} {
+		point := point
+		dao.AddHook(point, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+			if ctx == nil || storageId != dao.tableName {
+				return errors.New("invalid hook arguments")
+			}
+			counters[point]++
+			return nil
+		})
+	}
+	stamp := func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe("name", reddo.TypeString) == "" {
+			return bo.GboSetAttr("name", "stamped")
+		}
+		return nil
+	}
+	dao.AddHook(godal.HookBeforeCreate, stamp).AddHook(godal.HookBeforeUpdate, stamp).AddHook(godal.HookBeforeSave, stamp)
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return bo.GboSetAttr("fetched", true)
+	})
+	dao.AddHook(godal.HookBeforeDelete, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe(fieldId, reddo.TypeString) == "locked" {
+			return errAbort
+		}
+		return nil
+	})
+
+	// "before" hooks run before validation, changes made by hooks are written
+	if numRows, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || fromGbo(gbo).Name != "stamped" || gbo.GboGetAttrUnsafe("fetched", nil) != true || gbo.GboIsDirty() {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "0", Username: "0"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoSave(dao.tableName, (&MyBo{Id: "locked", Username: "locked"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.tableName, []godal.IGenericBo{(&MyBo{Id: "2", Username: "2"}).ToGbo(), (&MyBo{Id: "1", Username: "1"}).ToGbo()})
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != 2 || results[0].NumRows != 1 || results[1].Error != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.tableName, (&MyBo{Id: "locked"}).ToGbo()); err != errAbort || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.tableName, (&MyBo{Id: "2"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.tableName, nil, nil, 0, 0); err != nil || len(boList) != 2 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	expected := map[godal.HookPoint]int{godal.HookBeforeCreate: 3, godal.HookAfterCreate: 2, godal.HookBeforeUpdate: 2, godal.HookAfterUpdate: 1,
+		godal.HookBeforeSave: 1, godal.HookAfterSave: 1, godal.HookBeforeDelete: 2, godal.HookAfterDelete: 1, godal.HookAfterFetch: 3}
+	if !reflect.DeepEqual(counters, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, counters)
+	}
+
+	// hooks can abort the operation
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return errAbort
+	})
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"}); err != errAbort || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	dao.ClearHooks(godal.HookAfterFetch)
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || gbo.GboGetAttrUnsafe("fetched", nil) != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoDynamodb_RetryPolicy(t *testing.T) {
+	name := "TestGenericDaoDynamodb_RetryPolicy"
+	dao := initDao()
+	attempts := make([]godal.RetryAttempt, 0)
+	policy := godal.NewRetryPolicy(3)
+	policy.InitialBackoff = time.Millisecond
+	policy.OnAttempt = func(a godal.RetryAttempt) { attempts = append(attempts, a) }
+	dao.SetRetryPolicy(policy)
+
+	// the whole transaction is re-run on retryable errors
+	errConflict := awserr.New(dynamodb.ErrCodeTransactionConflictException, "Transaction is ongoing for the item", nil)
+	numCalls := 0
+	err := dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		if _, err := txDao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+			return err
+		}
+		if numCalls < 2 {
+			return errConflict
+		}
+		return nil
+	})
+	if err != nil || numCalls != 2 || len(attempts) != 2 || !attempts[0].Retry || attempts[1].Err != nil {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	// non-retryable errors are returned immediately
+	numCalls, attempts = 0, attempts[:0]
+	err = dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		_, err := txDao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo())
+		return err
+	})
+	if err != godal.GdaoErrorDuplicatedEntry || numCalls != 1 || len(attempts) != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+}
+
+type testInstrumentation struct {
+	lock     sync.Mutex
+	finished []*godal.DaoOperation
+}
+
+func (ti *testInstrumentation) OnStart(ctx context.Context, op *godal.DaoOperation) context.Context {
+	return ctx
+}
+
+func (ti *testInstrumentation) OnFinish(ctx context.Context, op *godal.DaoOperation) {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	ti.finished = append(ti.finished, op)
+}
+
+func TestGenericDaoDynamodb_Instrumentation(t *testing.T) {
+	name := "TestGenericDaoDynamodb_Instrumentation"
+	dao := initDao()
+	instrumentation := &testInstrumentation{}
+	dao.SetInstrumentation(instrumentation)
+	verify := func(operation string, numRows int, err error) {
+		if len(instrumentation.finished) == 0 {
+			t.Fatalf("%s failed - no operation reported", name)
+		}
+		op := instrumentation.finished[len(instrumentation.finished)-1]
+		if op.Backend != "dynamodb" || op.Operation != operation || op.StorageId != dao.tableName || op.NumRows != numRows || op.Err != err {
+			t.Fatalf("%s failed - expected %s/%d/%v but received %v", name, operation, numRows, err, op)
+		}
+	}
+
+	if _, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	verify("GdaoCreate", 1, nil)
+	_, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo())
+	verify("GdaoCreate", 0, err)
+	if err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.tableName, nil, nil, 0, 0); err != nil || len(boList) != 1 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	verify("GdaoFetchMany", 1, nil)
+
+	// nested calls are reported too
+	numOps := len(instrumentation.finished)
+	if gbo, err := dao.GdaoFetchOne(dao.tableName, godal.FilterEq(fieldId, "1")); err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	verify("GdaoFetchOne", 1, nil)
+	if len(instrumentation.finished) != numOps+2 || instrumentation.finished[numOps].Operation != "GdaoFetchMany" {
+		t.Fatalf("%s failed - Operations: %v", name, instrumentation.finished[numOps:])
+	}
+	if exists, err := dao.GdaoExists(dao.tableName, map[string]interface{}{fieldId: "0"}); err != nil || exists {
+		t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+	}
+	verify("GdaoExists", 0, nil)
+}
+
+func TestGenericDaoDynamodb_OptimisticLocking(t *testing.T) {
+	name := "TestGenericDaoDynamodb_OptimisticLocking"
+	dao := initDao()
+	dao.SetVersionField(dao.tableName, "version")
+	if numRows, err := dao.GdaoCreate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchBo := func(id string) *MyBo {
+		gbo, err := dao.GdaoFetchOne(dao.tableName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return fromGbo(gbo)
+	}
+
+	if numRows, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "v1", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	// stale version
+	if _, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if _, err := dao.GdaoSave(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoSave(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "v2", Version: 2}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// GdaoSave creates non-existing BO, GdaoUpdate does not
+	if numRows, err := dao.GdaoSave(dao.tableName, (&MyBo{Id: "2", Username: "2", Name: "new"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("2"); bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "3", Username: "3"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// the new version is written back to the BO: it can be updated again without being re-fetched
+	gbo := (&MyBo{Id: "2", Username: "2", Name: "new", Version: 1}).ToGbo()
+	for _, boName := range []string{"u1", "u2"} {
+		gbo.GboSetAttr("name", boName)
+		if numRows, err := dao.GdaoUpdate(dao.tableName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fetchBo("2"); bo.Name != "u2" || bo.Version != 3 || fromGbo(gbo).Version != 3 {
+		t.Fatalf("%s failed - Received: %v / BO: %v", name, bo, fromGbo(gbo))
+	}
+
+	// GdaoSaveMany checks the version of each BO
+	boList := []godal.IGenericBo{
+		(&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo(),
+		(&MyBo{Id: "2", Username: "2", Name: "v4", Version: 3}).ToGbo(),
+		(&MyBo{Id: "4", Username: "4", Name: "new"}).ToGbo(),
+	}
+	results, err := dao.GdaoSaveMany(dao.tableName, boList)
+	if err != godal.GdaoErrorConcurrentModification || results[0].Error != godal.GdaoErrorConcurrentModification || results[1].NumRows != 1 || results[2].NumRows != 1 {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("2"); bo.Name != "v4" || bo.Version != 4 || fromGbo(boList[1]).Version != 4 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("4"); bo.Version != 1 || fromGbo(boList[2]).Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// optimistic locking disabled
+	dao.SetVersionField(dao.tableName, "")
+	if numRows, err := dao.GdaoUpdate(dao.tableName, (&MyBo{Id: "1", Username: "1", Name: "v0", Version: 0}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
 func TestGenericDaoDynamodb_GdaoSave(t *testing.T) {
 	name := "TestGenericDaoDynamodb_GdaoSave"
 	dao := initDao()
@@ -655,3 +1377,205 @@ func TestGenericDaoDynamodb_GdaoSave(t *testing.T) {
 		t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
 	}
 }
+
+func TestGenericDaoDynamodb_IGenericDaoWithContext(t *testing.T) {
+	name := "TestGenericDaoDynamodb_IGenericDaoWithContext"
+	var dao interface{} = &GenericDaoDynamodb{}
+	if _, ok := dao.(godal.IGenericDaoWithContext); !ok {
+		t.Fatalf("%s failed: GenericDaoDynamodb does not implement godal.IGenericDaoWithContext", name)
+	}
+}
+
+func TestGenericDaoDynamodb_ITransactionalDao(t *testing.T) {
+	name := "TestGenericDaoDynamodb_ITransactionalDao"
+	var dao interface{} = &GenericDaoDynamodb{}
+	if _, ok := dao.(godal.ITransactionalDao); !ok {
+		t.Fatalf("%s failed: GenericDaoDynamodb does not implement godal.ITransactionalDao", name)
+	}
+}
+
+func TestDynamodbTx_CommitError(t *testing.T) {
+	name := "TestDynamodbTx_CommitError"
+	tx := &dynamodbTx{}
+	tx.add(&dynamodb.TransactWriteItem{}, nil, nil)
+	tx.add(&dynamodb.TransactWriteItem{}, godal.GdaoErrorDuplicatedEntry, nil)
+
+	err := awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled, please refer cancellation reasons for specific reasons [None, ConditionalCheckFailed]", nil)
+	if e := tx.txCommitError(err); e != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed: expected %e but received %e", name, godal.GdaoErrorDuplicatedEntry, e)
+	}
+	err = awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled, please refer cancellation reasons for specific reasons [ConditionalCheckFailed, None]", nil)
+	if e := tx.txCommitError(err); e != err {
+		t.Fatalf("%s failed: expected %e but received %e", name, err, e)
+	}
+	if e := tx.txCommitError(nil); e != nil {
+		t.Fatalf("%s failed: expected nil but received %e", name, e)
+	}
+}
+
+func TestGenericDaoDynamodb_GdaoWithTransaction_TxCtx(t *testing.T) {
+	name := "TestGenericDaoDynamodb_GdaoWithTransaction_TxCtx"
+	newDao := func() *GenericDaoDynamodb {
+		dao := NewGenericDaoDynamodb(&prom.AwsDynamodbConnect{}, godal.NewAbstractGenericDao(nil))
+		dao.SetRowMapper(&GenericRowMapperDynamodb{ColumnsListMap: map[string][]string{"tbl": {"id"}}})
+		return dao
+	}
+	dao, other := newDao(), newDao()
+	bo := godal.NewGenericBo()
+	bo.GboSetAttr("id", "1")
+
+	// nothing is written, hence nothing is committed
+	var staleCtx, otherCtx context.Context
+	err := dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		staleCtx = txCtx
+		if dao.txFromContext(txCtx) == nil || other.txFromContext(txCtx) != nil {
+			t.Fatalf("%s failed - transaction is expected to be joined by its own dao only", name)
+		}
+		return other.GdaoWithTransaction(txCtx, func(txCtx context.Context, txDao godal.IGenericDao) error {
+			otherCtx = txCtx
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if other.txFromContext(otherCtx) == nil || dao.txFromContext(otherCtx) != nil {
+		t.Fatalf("%s failed - another dao is expected to start its own transaction", name)
+	}
+
+	// txCtx is no longer usable once the transaction has ended: writes are not buffered
+	if numRows, err := dao.GdaoCreateWithContext(staleCtx, "tbl", bo); err != godal.ErrTxEnded || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoSaveWithContext(staleCtx, "tbl", bo); err != godal.ErrTxEnded || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err := dao.GdaoFetchOneWithContext(staleCtx, "tbl", map[string]interface{}{"id": "1"}); err != godal.ErrTxEnded || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	err = dao.GdaoWithTransaction(staleCtx, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		t.Fatalf("%s failed - txFunc is not expected to be called", name)
+		return nil
+	})
+	if err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if tx := dao.txFromContext(staleCtx); len(tx.writeItems) != 0 {
+		t.Fatalf("%s failed - WriteItems: %v", name, tx.writeItems)
+	}
+}
+
+func TestGenericDaoDynamodb_TranslateError(t *testing.T) {
+	name := "TestGenericDaoDynamodb_TranslateError"
+	dao := &GenericDaoDynamodb{}
+	testCases := []struct {
+		err      error
+		expected error
+	}{
+		{awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil), godal.ErrConflict},
+		{awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "", nil), godal.ErrThrottled},
+		{awserr.New(dynamodb.ErrCodeRequestLimitExceeded, "", nil), godal.ErrThrottled},
+		{awserr.New("ThrottlingException", "", nil), godal.ErrThrottled},
+		{awserr.New(dynamodb.ErrCodeTransactionConflictException, "", nil), godal.ErrConcurrentModification},
+		{awserr.New(dynamodb.ErrCodeResourceNotFoundException, "Requested resource not found", nil), godal.ErrNotFound},
+		{awserr.New("RequestCanceled", "request context canceled", context.DeadlineExceeded), godal.ErrTimeout},
+		{awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled, please refer cancellation reasons for specific reasons [None, TransactionConflict]", nil), godal.ErrConcurrentModification},
+		{awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled, please refer cancellation reasons for specific reasons [ThrottlingError, None]", nil), godal.ErrThrottled},
+		{context.DeadlineExceeded, godal.ErrTimeout},
+		{fmt.Errorf("wrapped: %w", awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil)), godal.ErrConflict},
+	}
+	for _, tc := range testCases {
+		err := dao.TranslateError(tc.err)
+		if !errors.Is(err, tc.expected) || !errors.Is(err, tc.err) {
+			t.Fatalf("%s failed - Expected kind: %e / Received: %e", name, tc.expected, err)
+		}
+		if dao.TranslateError(err) != err {
+			t.Fatalf("%s failed - translated error is expected to be returned as-is: %e", name, err)
+		}
+	}
+
+	for _, err := range []error{nil, errors.New("error"), awserr.New("ValidationException", "", nil),
+		awserr.New("RequestCanceled", "request context canceled", context.Canceled), godal.GdaoErrorDuplicatedEntry} {
+		if translated := dao.TranslateError(err); translated != err {
+			t.Fatalf("%s failed - Expected: %e / Received: %e", name, err, translated)
+		}
+	}
+}
+
+func TestGenericDaoDynamodb_IsRetryableError(t *testing.T) {
+	name := "TestGenericDaoDynamodb_IsRetryableError"
+	dao := &GenericDaoDynamodb{}
+	for _, err := range []error{awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "", nil),
+		awserr.New(dynamodb.ErrCodeTransactionConflictException, "", nil), awserr.New("Throttling", "", nil),
+		awserr.New(dynamodb.ErrCodeTransactionCanceledException, "Transaction cancelled, please refer cancellation reasons for specific reasons [None, TransactionConflict]", nil)} {
+		if !dao.IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be retryable", name, err)
+		}
+	}
+	for _, err := range []error{nil, errors.New("error"), awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "", nil),
+		awserr.New(dynamodb.ErrCodeResourceNotFoundException, "", nil), godal.GdaoErrorConcurrentModification} {
+		if dao.IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be non-retryable", name, err)
+		}
+	}
+}
+
+func TestGenericDaoDynamodb_FilterOpt(t *testing.T) {
+	name := "TestGenericDaoDynamodb_FilterOpt"
+	filter := godal.FilterAnd(
+		godal.FilterEq("a", 1),
+		godal.FilterOr(godal.FilterGt("b", 2), godal.FilterLte("c", 3)),
+		godal.FilterIn("d", "x", "y"),
+		godal.FilterBetween("e", 4, 5),
+		godal.FilterPrefix("f", "abc"),
+		godal.FilterNot(godal.FilterIsNull("g")),
+	)
+	cond, err := toConditionBuilder(filter)
+	if err != nil || cond == nil {
+		t.Fatalf("%s failed - Condition: %v / Error: %e", name, cond, err)
+	}
+	expr, err := expression.NewBuilder().WithCondition(*cond).Build()
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	expected := "(#0 = :0) AND ((#1 > :1) OR (#2 <= :2)) AND (#3 IN (:3, :4)) AND (#4 BETWEEN :5 AND :6) AND (begins_with (#5, :7)) AND (NOT ((attribute_not_exists (#6)) OR (attribute_type (#6, :8))))"
+	if *expr.Condition() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, *expr.Condition())
+	}
+
+	for _, invalid := range []*godal.FilterOpt{godal.FilterIn("a"), godal.FilterOr(), godal.FilterNot(godal.FilterAnd())} {
+		if _, err := toConditionBuilder(invalid); err == nil {
+			t.Fatalf("%s failed - Expected error for filter %v", name, invalid)
+		}
+	}
+}
+
+func TestGenericDaoDynamodb_BuildQueryConditions(t *testing.T) {
+	name := "TestGenericDaoDynamodb_BuildQueryConditions"
+	schema := &keySchema{hashKey: "pk", rangeKey: "sk"}
+	filter := godal.FilterAnd(godal.FilterGt("sk", 1), godal.FilterEq("status", 2), godal.FilterEq("pk", "x"))
+	keyCond, nonKeyCond, err := buildQueryConditions(filter, schema)
+	if err != nil || keyCond == nil || nonKeyCond == nil {
+		t.Fatalf("%s failed - KeyCond: %v / NonKeyCond: %v / Error: %e", name, keyCond, nonKeyCond, err)
+	}
+	expr, err := expression.NewBuilder().WithCondition(*keyCond).WithFilter(*nonKeyCond).Build()
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if expected := "(#0 = :0) AND (#1 > :1)"; *expr.Condition() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, *expr.Condition())
+	}
+	if expected := "#2 = :2"; *expr.Filter() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, *expr.Filter())
+	}
+
+	if keyCond, nonKeyCond, err := buildQueryConditions(map[string]interface{}{"pk": "x"}, schema); err != nil || keyCond == nil || nonKeyCond != nil {
+		t.Fatalf("%s failed - KeyCond: %v / NonKeyCond: %v / Error: %e", name, keyCond, nonKeyCond, err)
+	}
+	if _, _, err := buildQueryConditions(godal.FilterOr(godal.FilterEq("pk", "x"), godal.FilterEq("pk", "y")), schema); err == nil {
+		t.Fatalf("%s failed - Expected error when filter has no equal condition on partition key", name)
+	}
+	if _, _, err := buildQueryConditions(expression.Name("pk").Equal(expression.Value("x")), schema); err == nil {
+		t.Fatalf("%s failed - Expected error when filter is a expression.ConditionBuilder", name)
+	}
+}
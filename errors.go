@@ -0,0 +1,89 @@
+package godal
+
+import (
+	"errors"
+)
+
+/*
+Kinds of errors returned by DAO operations. Generic DAO implementations map native driver errors to these kinds, so that callers can
+handle errors independently of the storage:
+
+	if _, err := dao.GdaoCreate(storageId, bo); errors.Is(err, godal.ErrConflict) {
+		// the entry already exists
+	} else if errors.Is(err, godal.ErrDeadlock) || errors.Is(err, godal.ErrThrottled) {
+		// the operation can be retried
+	}
+
+Errors returned by DAOs are *DaoError values wrapping the native errors, which can still be retrieved using errors.As
+(e.g. *mysql.MySQLError, *pq.Error, awserr.Error, mongo.WriteException).
+
+Available since v0.3.0
+*/
+var (
+	// ErrNotFound indicates that the storage (table/collection) or the targeted entry does not exist.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict indicates that the write operation conflicts with existing data, e.g. duplicated entry/key.
+	ErrConflict = errors.New("conflict")
+
+	// ErrConstraintViolation indicates that the write operation violates a constraint other than uniqueness, e.g. foreign key,
+	// not-null or check constraint, or document validation rules.
+	ErrConstraintViolation = errors.New("constraint violation")
+
+	// ErrDeadlock indicates that the operation was chosen as deadlock victim; it can be retried.
+	ErrDeadlock = errors.New("deadlock")
+
+	// ErrThrottled indicates that the operation was rejected because of rate or capacity limits; it can be retried later.
+	ErrThrottled = errors.New("throttled")
+
+	// ErrTimeout indicates that the operation timed out or its context deadline was exceeded.
+	ErrTimeout = errors.New("timeout")
+
+	// ErrConcurrentModification indicates that the write operation failed because the data was modified concurrently, e.g. version
+	// mismatch (see AbstractGenericDao.SetVersionField), transaction write conflict or serialization failure.
+	ErrConcurrentModification = errors.New("concurrent modification")
+)
+
+/*
+DaoError is an error of a known kind (ErrNotFound, ErrConflict, etc), wrapping the underlying error.
+
+errors.Is(daoError, daoError.Kind) is true, and errors.Is/errors.As also look into the wrapped error.
+
+Available since v0.3.0
+*/
+type DaoError struct {
+	Kind error // kind of the error, one of ErrNotFound, ErrConflict, ErrConstraintViolation, ErrDeadlock, ErrThrottled, ErrTimeout, ErrConcurrentModification
+	Err  error // the underlying error, can be nil
+}
+
+// Error implements error.Error: the message of the underlying error (that of the kind if there is no underlying error), so that
+// wrapping errors does not change their messages.
+func (e *DaoError) Error() string {
+	if e.Err == nil {
+		return e.Kind.Error()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DaoError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the kind of the error.
+func (e *DaoError) Is(target error) bool {
+	return target == e.Kind
+}
+
+/*
+WrapError wraps err into a *DaoError of the given kind. nil is returned if err is nil; err is returned as-is if kind is nil or err is
+already of that kind.
+
+Available since v0.3.0
+*/
+func WrapError(kind, err error) error {
+	if err == nil || kind == nil || errors.Is(err, kind) {
+		return err
+	}
+	return &DaoError{Kind: kind, Err: err}
+}
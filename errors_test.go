@@ -0,0 +1,59 @@
+package godal
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDaoError(t *testing.T) {
+	name := "TestDaoError"
+	native := errors.New("native")
+	err := error(&DaoError{Kind: ErrDeadlock, Err: native})
+	if !errors.Is(err, ErrDeadlock) || !errors.Is(err, native) || errors.Is(err, ErrTimeout) {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if expected := "native"; err.Error() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, err.Error())
+	}
+	if expected := "timeout"; (&DaoError{Kind: ErrTimeout}).Error() != expected {
+		t.Fatalf("%s failed - Expected: %s", name, expected)
+	}
+	wrapped := fmt.Errorf("wrapped: %w", err)
+	var daoErr *DaoError
+	if !errors.As(wrapped, &daoErr) || daoErr.Kind != ErrDeadlock || !errors.Is(wrapped, ErrDeadlock) {
+		t.Fatalf("%s failed - Error: %e", name, wrapped)
+	}
+
+	// predefined errors are of known kinds, and can still be compared with ==
+	if !errors.Is(GdaoErrorDuplicatedEntry, ErrConflict) {
+		t.Fatalf("%s failed - %e is expected to be of kind %e", name, GdaoErrorDuplicatedEntry, ErrConflict)
+	}
+	if !errors.Is(GdaoErrorConcurrentModification, ErrConcurrentModification) {
+		t.Fatalf("%s failed - %e is expected to be of kind %e", name, GdaoErrorConcurrentModification, ErrConcurrentModification)
+	}
+	if expected := "data integrity violation: duplicated entry/key"; GdaoErrorDuplicatedEntry.Error() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, GdaoErrorDuplicatedEntry.Error())
+	}
+}
+
+func TestWrapError(t *testing.T) {
+	name := "TestWrapError"
+	if err := WrapError(ErrConflict, nil); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	native := errors.New("native")
+	if err := WrapError(nil, native); err != native {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	err := WrapError(ErrThrottled, native)
+	if !errors.Is(err, ErrThrottled) || !errors.Is(err, native) {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if err2 := WrapError(ErrThrottled, err); err2 != err {
+		t.Fatalf("%s failed - error of the same kind is expected to be returned as-is: %e", name, err2)
+	}
+	if err := WrapError(ErrConflict, GdaoErrorDuplicatedEntry); err != GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - error of the same kind is expected to be returned as-is: %e", name, err)
+	}
+}
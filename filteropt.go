@@ -0,0 +1,144 @@
+package godal
+
+/*
+FilterOperator identifies the operator of a FilterOpt node.
+
+Available since v0.3.0
+*/
+type FilterOperator int
+
+const (
+	// FilterOpEq matches items whose field equals to the value.
+	FilterOpEq FilterOperator = iota
+	// FilterOpNe matches items whose field does not equal to the value.
+	FilterOpNe
+	// FilterOpGt matches items whose field is greater than the value.
+	FilterOpGt
+	// FilterOpGte matches items whose field is greater than or equal to the value.
+	FilterOpGte
+	// FilterOpLt matches items whose field is less than the value.
+	FilterOpLt
+	// FilterOpLte matches items whose field is less than or equal to the value.
+	FilterOpLte
+	// FilterOpIn matches items whose field equals to one of the values.
+	FilterOpIn
+	// FilterOpBetween matches items whose field is between the lower and upper bounds (inclusive).
+	FilterOpBetween
+	// FilterOpPrefix matches items whose (string) field starts with the value.
+	FilterOpPrefix
+	// FilterOpIsNull matches items whose field is null or absent.
+	FilterOpIsNull
+	// FilterOpExists matches items whose field is present and not null (negation of FilterOpIsNull).
+	FilterOpExists
+	// FilterOpAnd matches items that match all sub-filters.
+	FilterOpAnd
+	// FilterOpOr matches items that match at least one sub-filter.
+	FilterOpOr
+	// FilterOpNot matches items that do not match the sub-filter.
+	FilterOpNot
+)
+
+/*
+FilterOpt is a node of the backend-neutral filter AST.
+
+Filters built from FilterOpt can be passed to GdaoFetchOne, GdaoFetchMany and GdaoDeleteMany of all generic DAO implementations;
+each implementation translates the AST to its native form (sql.IFilter, MongoDB query selector, DynamoDB expression.ConditionBuilder).
+
+FilterOpt should be built via helper functions FilterEq, FilterNe, FilterGt, FilterGte, FilterLt, FilterLte, FilterIn, FilterBetween,
+FilterPrefix, FilterIsNull, FilterExists, FilterAnd, FilterOr and FilterNot. Example:
+
+	// status = 1 AND (age >= 18 OR name starts with "adm")
+	filter := godal.FilterAnd(
+		godal.FilterEq("status", 1),
+		godal.FilterOr(godal.FilterGte("age", 18), godal.FilterPrefix("name", "adm")),
+	)
+	boList, err := dao.GdaoFetchMany(storageId, filter, nil, 0, 0)
+
+Notes:
+
+	- Field is the native field name: column name for SQL, document path for MongoDB, attribute path for DynamoDB.
+	- And, Or and In must have at least one element.
+	- Comparisons (Eq, Ne, Gt, Gte, Lt, Lte, In, Between and Prefix) never match null/absent fields; use FilterIsNull/FilterExists to test for them.
+	- Not on SQL follows three-valued logic: a comparison against NULL is neither true nor false, hence such rows match neither
+	  the filter nor its negation. Other backends treat the comparison as false, hence such items match the negation.
+	- Prefix is used instead of "like" because it is the only pattern matching supported by all backends.
+
+Available since v0.3.0
+*/
+type FilterOpt struct {
+	Operator FilterOperator
+	Field    string        // field to test, for field-level operators
+	Value    interface{}   // value to test against, for FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte and FilterOpPrefix
+	Values   []interface{} // list of values for FilterOpIn, lower and upper bounds for FilterOpBetween
+	Filters  []*FilterOpt  // sub-filters for FilterOpAnd and FilterOpOr, the negated filter for FilterOpNot
+}
+
+// FilterEq builds a filter "field = value".
+func FilterEq(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpEq, Field: field, Value: value}
+}
+
+// FilterNe builds a filter "field <> value".
+func FilterNe(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpNe, Field: field, Value: value}
+}
+
+// FilterGt builds a filter "field > value".
+func FilterGt(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpGt, Field: field, Value: value}
+}
+
+// FilterGte builds a filter "field >= value".
+func FilterGte(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpGte, Field: field, Value: value}
+}
+
+// FilterLt builds a filter "field < value".
+func FilterLt(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpLt, Field: field, Value: value}
+}
+
+// FilterLte builds a filter "field <= value".
+func FilterLte(field string, value interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpLte, Field: field, Value: value}
+}
+
+// FilterIn builds a filter "field IN (values...)".
+func FilterIn(field string, values ...interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpIn, Field: field, Values: values}
+}
+
+// FilterBetween builds a filter "field BETWEEN lower AND upper" (bounds are inclusive).
+func FilterBetween(field string, lower, upper interface{}) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpBetween, Field: field, Values: []interface{}{lower, upper}}
+}
+
+// FilterPrefix builds a filter "field starts with prefix".
+func FilterPrefix(field string, prefix string) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpPrefix, Field: field, Value: prefix}
+}
+
+// FilterIsNull builds a filter "field is null or absent".
+func FilterIsNull(field string) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpIsNull, Field: field}
+}
+
+// FilterExists builds a filter "field is present and not null".
+func FilterExists(field string) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpExists, Field: field}
+}
+
+// FilterAnd combines filters using "AND".
+func FilterAnd(filters ...*FilterOpt) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpAnd, Filters: filters}
+}
+
+// FilterOr combines filters using "OR".
+func FilterOr(filters ...*FilterOpt) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpOr, Filters: filters}
+}
+
+// FilterNot negates a filter.
+func FilterNot(filter *FilterOpt) *FilterOpt {
+	return &FilterOpt{Operator: FilterOpNot, Filters: []*FilterOpt{filter}}
+}
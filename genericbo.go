@@ -2,9 +2,14 @@ package godal
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/btnguyen2k/consu/checksum"
 	"github.com/btnguyen2k/consu/semita"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -64,6 +69,119 @@ type IGenericBo interface {
 	// GboImportViaJson imports bo's data from an external source using JSON transformation.
 	// Firstly, src is marshaled to JSON data. Then the JSON data is unmarshaled/imported to bo's attributes.
 	GboImportViaJson(src interface{}) error
+
+	// GboToMap returns a deep copy of bo's data as a map, same result as GboTransferViaJson to a map but without JSON marshalling.
+	//
+	// Available: since v0.3.0
+	GboToMap() (map[string]interface{}, error)
+
+	// GboFromMap imports bo's data from a map (deep copy), same result as GboImportViaJson but without JSON marshalling.
+	//
+	// Available: since v0.3.0
+	GboFromMap(src map[string]interface{}) error
+
+	// GboFromStruct imports bo's data from a struct (or pointer to struct), same result as GboImportViaJson but without JSON marshalling.
+	//
+	// Available: since v0.3.0
+	GboFromStruct(src interface{}) error
+
+	// GboClone returns a deep copy of the bo: changes made to the clone do not affect the original bo and vice versa.
+	//
+	// Available: since v0.3.0
+	GboClone() IGenericBo
+
+	// GboMerge merges attributes of another bo into this bo, conflicts are resolved using 'strategy'.
+	//
+	// Available: since v0.3.0
+	GboMerge(other IGenericBo, strategy MergeStrategy) error
+
+	// GboDiff compares this bo (old) with another bo (new) and returns the list of changed paths (see GboChange).
+	//
+	// Available: since v0.3.0
+	GboDiff(other IGenericBo) ([]GboChange, error)
+
+	// GboHasAttr checks if a bo's attribute exists, e.g. "options.workhour[0].value". Unlike GboGetAttr, it distinguishes
+	// "missing" from "present and nil".
+	//
+	// Available: since v0.3.0
+	GboHasAttr(path string) bool
+
+	// GboDeleteAttr removes a bo's attribute: a map entry is removed, a list element is removed and subsequent elements are shifted.
+	// Removing a non-existing attribute is a no-op.
+	//
+	// Available: since v0.3.0
+	GboDeleteAttr(path string) error
+
+	// GboDeletedAttrs returns the (sorted) top-level attributes removed via GboDeleteAttr since bo's data was last imported (or marked
+	// clean); an attribute set again is no longer reported. Row mappers/DAOs use it to unset the removed attributes in storage.
+	//
+	// Available: since v0.3.0
+	GboDeletedAttrs() []string
+
+	// GboIsDirty checks if the bo has been modified (attributes set, removed or merged) since its data was last imported or marked clean.
+	//
+	// Available: since v0.3.0
+	GboIsDirty() bool
+
+	// GboDirtyPaths returns the (sorted) paths modified since bo's data was last imported or marked clean, e.g. "options.workhour[0].value".
+	// A path is not reported if one of its ancestors is. Top-level attributes removed via GboDeleteAttr are reported by GboDeletedAttrs.
+	//
+	// Available: since v0.3.0
+	GboDirtyPaths() []string
+
+	// GboMarkClean clears the record of modified and removed attributes, e.g. after the bo has been written to storage.
+	//
+	// Available: since v0.3.0
+	GboMarkClean()
+}
+
+/*
+MergeStrategy specifies how GboMerge resolves conflicts: when an attribute exists in both BOs and at least one of the values is
+not a map (maps are always merged recursively).
+
+Available: since v0.3.0
+*/
+type MergeStrategy int
+
+const (
+	// MergeOverwrite: values of the other BO overwrite existing values.
+	MergeOverwrite MergeStrategy = iota
+	// MergeKeepExisting: existing values are kept, only attributes absent from the BO are added.
+	MergeKeepExisting
+	// MergeAppendLists: same as MergeOverwrite, except that if both values are lists, the other list is appended to the existing one.
+	MergeAppendLists
+)
+
+/*
+GboChangeType identifies the type of a GboChange.
+
+Available: since v0.3.0
+*/
+type GboChangeType int
+
+const (
+	// GboChangeAdded: the attribute is absent from the old BO.
+	GboChangeAdded GboChangeType = iota
+	// GboChangeRemoved: the attribute is absent from the new BO.
+	GboChangeRemoved
+	// GboChangeModified: the attribute's value differs.
+	GboChangeModified
+)
+
+/*
+GboChange is an entry of the result of GboDiff.
+
+	- Path uses the same syntax as GboGetAttr, e.g. "options.workhour[0].value"; changes of the root value have an empty path.
+	- OldValue/NewValue are in their JSON-equivalent form (see GboToMap); OldValue is nil for added attributes, NewValue is nil for
+	  removed ones.
+
+Available: since v0.3.0
+*/
+type GboChange struct {
+	Path     string
+	Type     GboChangeType
+	OldValue interface{}
+	NewValue interface{}
 }
 
 // NewGenericBo constructs a new 'IGenericBo' instance.
@@ -106,9 +224,11 @@ Sample usage:
 	name, err := gbbo.GboGetAttr("name", nil)                 // name is nil because GboImportViaJson clear existing data upon importing
 */
 type GenericBo struct {
-	data interface{}
-	s    *semita.Semita
-	m    sync.RWMutex
+	data    interface{}
+	s       *semita.Semita
+	m       sync.RWMutex
+	deleted map[string]bool // top-level attributes removed via GboDeleteAttr, see GboDeletedAttrs
+	dirty   map[string]bool // paths modified since data was last imported/marked clean, see GboDirtyPaths
 }
 
 /*
@@ -209,6 +329,7 @@ func (bo *GenericBo) GboGetTimeWithLayout(path, layout string) (time.Time, error
 GboSetAttr implements IGenericBo.GboSetAttr
 
 	- Intermediate nodes along the path are automatically created.
+	- The path is recorded as modified, see GboDirtyPaths.
 */
 func (bo *GenericBo) GboSetAttr(path string, value interface{}) error {
 	bo.m.Lock()
@@ -218,7 +339,12 @@ func (bo *GenericBo) GboSetAttr(path string, value interface{}) error {
 		bo.data = data
 		bo.s = semita.NewSemita(bo.data)
 	}
-	return bo.s.SetValue(path, value)
+	err := bo.s.SetValue(path, value)
+	if tokens := semita.SplitPath(path); err == nil && len(tokens) > 0 {
+		delete(bo.deleted, tokens[0])
+		bo.markDirty(tokens)
+	}
+	return err
 }
 
 /*
@@ -256,6 +382,7 @@ func (bo *GenericBo) GboFromJson(js []byte) error {
 	}
 	bo.data = data
 	bo.s = semita.NewSemita(bo.data)
+	bo.deleted, bo.dirty = nil, nil
 	return nil
 }
 
@@ -284,3 +411,577 @@ func (bo *GenericBo) GboImportViaJson(src interface{}) error {
 	}
 	return bo.GboFromJson(js)
 }
+
+/*
+GboToMap implements IGenericBo.GboToMap
+
+	- Values are converted to their JSON-equivalent form (numbers become float64, time.Time becomes string, etc).
+	- If bo's data is not a map, this function returns an error.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboToMap() (map[string]interface{}, error) {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	data, err := toJsonValue(bo.data)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return map[string]interface{}{}, nil
+	}
+	if m, ok := data.(map[string]interface{}); ok {
+		return m, nil
+	}
+	return nil, errors.New(fmt.Sprintf("cannot convert bo's data of type [%T] to map", bo.data))
+}
+
+// importJsonValue replaces bo's data with the JSON-equivalent form of src.
+func (bo *GenericBo) importJsonValue(src interface{}) error {
+	data, err := toJsonValue(src)
+	if err != nil {
+		return err
+	}
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	bo.m.Lock()
+	defer bo.m.Unlock()
+	bo.data = data
+	bo.s = semita.NewSemita(bo.data)
+	bo.deleted, bo.dirty = nil, nil
+	return nil
+}
+
+/*
+GboFromMap implements IGenericBo.GboFromMap
+
+	- Existing data is removed upon importing.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboFromMap(src map[string]interface{}) error {
+	return bo.importJsonValue(src)
+}
+
+/*
+GboFromStruct implements IGenericBo.GboFromStruct
+
+	- Existing data is removed upon importing.
+	- Struct fields are mapped to attributes following encoding/json rules (e.g. 'json' tags); field mappings are cached per struct type.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboFromStruct(src interface{}) error {
+	v := reflect.ValueOf(src)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return errors.New(fmt.Sprintf("expected struct or pointer to struct, got [%T]", src))
+	}
+	return bo.importJsonValue(src)
+}
+
+/*
+GboClone implements IGenericBo.GboClone
+
+	- Maps and slices are copied recursively; other values (e.g. pointers, structs set via GboSetAttr) are copied shallowly.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboClone() IGenericBo {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	clone := &GenericBo{data: deepCopy(bo.data)}
+	clone.s = semita.NewSemita(clone.data)
+	for attr := range bo.deleted {
+		clone.markDeleted(attr)
+	}
+	for path := range bo.dirty {
+		clone.markDirty(semita.SplitPath(path))
+	}
+	return clone
+}
+
+// deepCopy copies maps and slices recursively, other values are returned as-is.
+func deepCopy(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		if x == nil {
+			return x
+		}
+		result := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			result[k] = deepCopy(e)
+		}
+		return result
+	case []interface{}:
+		if x == nil {
+			return x
+		}
+		result := make([]interface{}, len(x))
+		for i, e := range x {
+			result[i] = deepCopy(e)
+		}
+		return result
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return v
+		}
+		result := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		for iter := rv.MapRange(); iter.Next(); {
+			result.SetMapIndex(iter.Key(), deepCopyValue(iter.Value(), rv.Type().Elem()))
+		}
+		return result.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return v
+		}
+		result := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			result.Index(i).Set(deepCopyValue(rv.Index(i), rv.Type().Elem()))
+		}
+		return result.Interface()
+	}
+	return v
+}
+
+// deepCopyValue is the reflect.Value counterpart of deepCopy, the result is assignable to 'typ'.
+func deepCopyValue(v reflect.Value, typ reflect.Type) reflect.Value {
+	if v.Kind() == reflect.Interface && v.IsNil() {
+		return reflect.Zero(typ)
+	}
+	c := reflect.ValueOf(deepCopy(v.Interface()))
+	if !c.IsValid() {
+		return reflect.Zero(typ)
+	}
+	return c
+}
+
+// dataOf returns a deep copy of a BO's data.
+func dataOf(bo IGenericBo) (interface{}, error) {
+	if gbo, ok := bo.(*GenericBo); ok {
+		gbo.m.RLock()
+		defer gbo.m.RUnlock()
+		return deepCopy(gbo.data), nil
+	}
+	return bo.GboToMap()
+}
+
+/*
+GboMerge implements IGenericBo.GboMerge
+
+	- Maps are merged recursively; values taken from 'other' are deep copies.
+	- If 'other' is nil, this function does nothing.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboMerge(other IGenericBo, strategy MergeStrategy) error {
+	if other == nil {
+		return nil
+	}
+	if strategy != MergeOverwrite && strategy != MergeKeepExisting && strategy != MergeAppendLists {
+		return errors.New(fmt.Sprintf("invalid merge strategy %d", strategy))
+	}
+	src, err := dataOf(other)
+	if err != nil {
+		return err
+	}
+	bo.m.Lock()
+	defer bo.m.Unlock()
+	bo.data = mergeValue(bo.data, src, strategy)
+	if bo.data == nil {
+		bo.data = map[string]interface{}{}
+	}
+	bo.s = semita.NewSemita(bo.data)
+	if m, ok := bo.data.(map[string]interface{}); ok {
+		for attr := range bo.deleted {
+			if _, ok := m[attr]; ok {
+				delete(bo.deleted, attr)
+			}
+		}
+	}
+	if m, ok := src.(map[string]interface{}); ok {
+		for attr := range m {
+			bo.markDirty([]string{attr})
+		}
+	}
+	return nil
+}
+
+// mergeValue merges src into dst and returns the merged value; dst's maps are modified in place.
+func mergeValue(dst, src interface{}, strategy MergeStrategy) interface{} {
+	if dstMap, ok := dst.(map[string]interface{}); ok {
+		if srcMap, ok := src.(map[string]interface{}); ok {
+			for k, v := range srcMap {
+				if existing, ok := dstMap[k]; ok {
+					dstMap[k] = mergeValue(existing, v, strategy)
+				} else {
+					dstMap[k] = v
+				}
+			}
+			return dstMap
+		}
+	}
+	switch strategy {
+	case MergeKeepExisting:
+		return dst
+	case MergeAppendLists:
+		if dstList, ok := dst.([]interface{}); ok {
+			if srcList, ok := src.([]interface{}); ok {
+				return append(dstList, srcList...)
+			}
+		}
+	}
+	return src
+}
+
+/*
+GboDiff implements IGenericBo.GboDiff
+
+	- BOs are compared in their JSON-equivalent form (see GboToMap), e.g. int(1) and float64(1) are equal.
+	- Maps are compared recursively by keys (in sorted order), lists are compared element by element by index.
+	- If 'other' is nil, it is treated as an empty BO.
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboDiff(other IGenericBo) ([]GboChange, error) {
+	var newData interface{} = map[string]interface{}{}
+	if other != nil {
+		data, err := dataOf(other)
+		if err != nil {
+			return nil, err
+		}
+		if newData, err = toJsonValue(data); err != nil {
+			return nil, err
+		}
+	}
+	bo.m.RLock()
+	oldData, err := toJsonValue(bo.data)
+	bo.m.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if oldData == nil {
+		oldData = map[string]interface{}{}
+	}
+	if newData == nil {
+		newData = map[string]interface{}{}
+	}
+	result := make([]GboChange, 0)
+	diffValue("", oldData, newData, &result)
+	return result, nil
+}
+
+// diffValue appends changes between two JSON-equivalent values to 'result'.
+func diffValue(path string, oldValue, newValue interface{}, result *[]GboChange) {
+	switch o := oldValue.(type) {
+	case map[string]interface{}:
+		if n, ok := newValue.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(o)+len(n))
+			for k := range o {
+				keys = append(keys, k)
+			}
+			for k := range n {
+				if _, ok := o[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				childPath := k
+				if path != "" {
+					childPath = path + "." + k
+				}
+				ov, inOld := o[k]
+				nv, inNew := n[k]
+				switch {
+				case !inOld:
+					*result = append(*result, GboChange{Path: childPath, Type: GboChangeAdded, NewValue: nv})
+				case !inNew:
+					*result = append(*result, GboChange{Path: childPath, Type: GboChangeRemoved, OldValue: ov})
+				default:
+					diffValue(childPath, ov, nv, result)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if n, ok := newValue.([]interface{}); ok {
+			for i := 0; i < len(o) || i < len(n); i++ {
+				childPath := path + "[" + strconv.Itoa(i) + "]"
+				switch {
+				case i >= len(o):
+					*result = append(*result, GboChange{Path: childPath, Type: GboChangeAdded, NewValue: n[i]})
+				case i >= len(n):
+					*result = append(*result, GboChange{Path: childPath, Type: GboChangeRemoved, OldValue: o[i]})
+				default:
+					diffValue(childPath, o[i], n[i], result)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(oldValue, newValue) {
+		*result = append(*result, GboChange{Path: path, Type: GboChangeModified, OldValue: oldValue, NewValue: newValue})
+	}
+}
+
+// markDeleted records a removed top-level attribute.
+func (bo *GenericBo) markDeleted(attr string) {
+	if bo.deleted == nil {
+		bo.deleted = make(map[string]bool)
+	}
+	bo.deleted[attr] = true
+}
+
+// parseIndexToken parses a path token of form "[<index>]".
+func parseIndexToken(token string) (int, bool) {
+	if !strings.HasPrefix(token, "[") || !strings.HasSuffix(token, "]") {
+		return -1, false
+	}
+	i, err := strconv.Atoi(token[1 : len(token)-1])
+	return i, err == nil
+}
+
+// childOf returns the child of node at 'token' (map key or slice index), and false if the child does not exist.
+func childOf(node reflect.Value, token string) (reflect.Value, bool) {
+	for node.Kind() == reflect.Interface || node.Kind() == reflect.Ptr {
+		if node.IsNil() {
+			return reflect.Value{}, false
+		}
+		node = node.Elem()
+	}
+	if i, isIndex := parseIndexToken(token); isIndex {
+		if (node.Kind() == reflect.Slice || node.Kind() == reflect.Array) && i >= 0 && i < node.Len() {
+			return node.Index(i), true
+		}
+		return reflect.Value{}, false
+	}
+	if node.Kind() == reflect.Map && node.Type().Key().Kind() == reflect.String {
+		child := node.MapIndex(reflect.ValueOf(token).Convert(node.Type().Key()))
+		return child, child.IsValid()
+	}
+	return reflect.Value{}, false
+}
+
+/*
+GboHasAttr implements IGenericBo.GboHasAttr
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboHasAttr(path string) bool {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	node := reflect.ValueOf(bo.data)
+	if !node.IsValid() {
+		return false
+	}
+	for _, token := range semita.SplitPath(path) {
+		var ok bool
+		if node, ok = childOf(node, token); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+/*
+GboDeleteAttr implements IGenericBo.GboDeleteAttr
+
+	- Attributes inside maps and slices are supported, e.g. "options.workhour[0].value" (struct fields are not).
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboDeleteAttr(path string) error {
+	tokens := semita.SplitPath(path)
+	if len(tokens) == 0 {
+		return errors.New("empty path")
+	}
+	bo.m.Lock()
+	defer bo.m.Unlock()
+	data, removed := removePath(reflect.ValueOf(bo.data), tokens)
+	if !removed {
+		return nil
+	}
+	bo.data = data.Interface()
+	bo.s = semita.NewSemita(bo.data)
+	if _, isIndex := parseIndexToken(tokens[0]); len(tokens) == 1 && !isIndex {
+		bo.markDeleted(tokens[0])
+		for path := range bo.dirty {
+			if isPathOrDescendant(path, tokens[0]) {
+				delete(bo.dirty, path)
+			}
+		}
+	} else if len(tokens) > 1 {
+		// the parent node has been modified
+		bo.markDirty(tokens[:len(tokens)-1])
+	}
+	return nil
+}
+
+// removePath removes the value at 'tokens' from node and returns the (possibly new, for slices) node.
+func removePath(node reflect.Value, tokens []string) (reflect.Value, bool) {
+	target := node
+	for target.Kind() == reflect.Interface || target.Kind() == reflect.Ptr {
+		if target.IsNil() {
+			return node, false
+		}
+		target = target.Elem()
+	}
+	child, ok := childOf(target, tokens[0])
+	if !ok {
+		return node, false
+	}
+	i, isIndex := parseIndexToken(tokens[0])
+	if len(tokens) > 1 {
+		newChild, removed := removePath(child, tokens[1:])
+		if !removed {
+			return node, false
+		}
+		if isIndex {
+			if !child.CanSet() {
+				return node, false
+			}
+			child.Set(newChild)
+		} else {
+			target.SetMapIndex(reflect.ValueOf(tokens[0]).Convert(target.Type().Key()), newChild)
+		}
+		return node, true
+	}
+	if !isIndex {
+		target.SetMapIndex(reflect.ValueOf(tokens[0]).Convert(target.Type().Key()), reflect.Value{})
+		return node, true
+	}
+	if target.Kind() != reflect.Slice {
+		// elements cannot be removed from arrays
+		return node, false
+	}
+	result := reflect.MakeSlice(target.Type(), 0, target.Len()-1)
+	result = reflect.AppendSlice(result, target.Slice(0, i))
+	result = reflect.AppendSlice(result, target.Slice(i+1, target.Len()))
+	if node.Kind() == reflect.Ptr {
+		// slice is referenced by a pointer: update in place
+		target.Set(result)
+		return node, true
+	}
+	return result, true
+}
+
+/*
+GboDeletedAttrs implements IGenericBo.GboDeletedAttrs
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboDeletedAttrs() []string {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	result := make([]string, 0, len(bo.deleted))
+	for attr := range bo.deleted {
+		result = append(result, attr)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// joinPath builds the normalized path from path tokens, e.g. ["a", "b", "[0]", "c"] -> "a.b[0].c".
+func joinPath(tokens []string) string {
+	var sb strings.Builder
+	for i, token := range tokens {
+		if _, isIndex := parseIndexToken(token); i > 0 && !isIndex {
+			sb.WriteString(".")
+		}
+		sb.WriteString(token)
+	}
+	return sb.String()
+}
+
+// isPathOrDescendant checks if path equals 'ancestor' or is a descendant of it.
+func isPathOrDescendant(path, ancestor string) bool {
+	return path == ancestor || strings.HasPrefix(path, ancestor+".") || strings.HasPrefix(path, ancestor+"[")
+}
+
+// markDirty records a modified path.
+func (bo *GenericBo) markDirty(tokens []string) {
+	if bo.dirty == nil {
+		bo.dirty = make(map[string]bool)
+	}
+	bo.dirty[joinPath(tokens)] = true
+}
+
+/*
+GboIsDirty implements IGenericBo.GboIsDirty
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboIsDirty() bool {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	return len(bo.dirty) > 0 || len(bo.deleted) > 0
+}
+
+/*
+GboDirtyPaths implements IGenericBo.GboDirtyPaths
+
+	- Paths are normalized, e.g. "a.b.[0]" is reported as "a.b[0]".
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboDirtyPaths() []string {
+	bo.m.RLock()
+	defer bo.m.RUnlock()
+	paths := make([]string, 0, len(bo.dirty))
+	for path := range bo.dirty {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	result := make([]string, 0, len(paths))
+	for _, path := range paths {
+		covered := false
+		for _, ancestor := range result {
+			if covered = isPathOrDescendant(path, ancestor); covered {
+				break
+			}
+		}
+		if !covered {
+			result = append(result, path)
+		}
+	}
+	return result
+}
+
+/*
+GboMarkClean implements IGenericBo.GboMarkClean
+
+Available: since v0.3.0
+*/
+func (bo *GenericBo) GboMarkClean() {
+	bo.m.Lock()
+	defer bo.m.Unlock()
+	bo.deleted, bo.dirty = nil, nil
+}
+
+/*
+DirtyAttrs returns the (sorted) top-level attributes containing paths modified since the BO was loaded (see IGenericBo.GboDirtyPaths).
+Top-level attributes removed from the BO are reported by IGenericBo.GboDeletedAttrs.
+
+Available: since v0.3.0
+*/
+func DirtyAttrs(bo IGenericBo) []string {
+	result := make([]string, 0)
+	found := make(map[string]bool)
+	for _, path := range bo.GboDirtyPaths() {
+		if tokens := semita.SplitPath(path); len(tokens) > 0 && !found[tokens[0]] {
+			found[tokens[0]] = true
+			result = append(result, tokens[0])
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"github.com/btnguyen2k/consu/checksum"
 	"github.com/btnguyen2k/consu/reddo"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 )
 
 var jsonData = []byte(`
@@ -243,3 +246,410 @@ func TestGenericBo_ImportViaJson(t *testing.T) {
 		t.Fatalf("%s failed - checksums mismatch [%s] vs [%s]", name, checksum1, checksum2)
 	}
 }
+
+func TestGenericBo_Clone(t *testing.T) {
+	name := "TestGenericBo_Clone"
+	bo := NewGenericBo()
+	bo.GboFromJson([]byte(`{"a":{"b":[1,{"c":"value"}]}}`))
+	bo.GboSetAttr("m", map[string][]int{"x": {1}})
+	clone := bo.GboClone()
+	if !reflect.DeepEqual(clone.(*GenericBo).data, bo.(*GenericBo).data) {
+		t.Fatalf("%s failed - Expected: %#v / Received: %#v", name, bo.(*GenericBo).data, clone.(*GenericBo).data)
+	}
+	clone.GboSetAttr("a.b[1].c", "changed")
+	clone.GboGetAttrUnsafe("m", nil).(map[string][]int)["x"][0] = 2
+	if v := bo.GboGetAttrUnsafe("a.b[1].c", nil); v != "value" {
+		t.Fatalf("%s failed - original bo is modified: %v", name, v)
+	}
+	if v := bo.GboGetAttrUnsafe("m", nil).(map[string][]int)["x"][0]; v != 1 {
+		t.Fatalf("%s failed - original bo is modified: %v", name, v)
+	}
+	if clone := NewGenericBo().GboClone(); clone == nil {
+		t.Fatalf("%s failed - nil clone", name)
+	} else if err := clone.GboSetAttr("a", 1); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+}
+
+func TestGenericBo_Merge(t *testing.T) {
+	name := "TestGenericBo_Merge"
+	testData := []struct {
+		strategy MergeStrategy
+		expected string
+	}{
+		{MergeOverwrite, `{"a":{"x":1,"y":20,"z":30},"b":2,"list":[3],"s":"new","t":true}`},
+		{MergeKeepExisting, `{"a":{"x":1,"y":2,"z":30},"b":2,"list":[1,2],"s":"old","t":true}`},
+		{MergeAppendLists, `{"a":{"x":1,"y":20,"z":30},"b":2,"list":[1,2,3],"s":"new","t":true}`},
+	}
+	for _, td := range testData {
+		bo := NewGenericBo()
+		bo.GboFromJson([]byte(`{"a":{"x":1,"y":2},"b":2,"list":[1,2],"s":"old"}`))
+		other := NewGenericBo()
+		other.GboFromJson([]byte(`{"a":{"y":20,"z":30},"list":[3],"s":"new","t":true}`))
+		if err := bo.GboMerge(other, td.strategy); err != nil {
+			t.Fatalf("%s failed - Strategy: %d / Error: %e", name, td.strategy, err)
+		}
+		if js := string(bo.GboToJsonUnsafe()); js != td.expected {
+			t.Fatalf("%s failed - Strategy: %d / Expected: %s / Received: %s", name, td.strategy, td.expected, js)
+		}
+		// merged values are copies
+		other.GboSetAttr("a.z", 0)
+		if v := bo.GboGetAttrUnsafe("a.z", nil); v != 30.0 {
+			t.Fatalf("%s failed - Strategy: %d / merged value is shared: %v", name, td.strategy, v)
+		}
+	}
+
+	bo := NewGenericBo()
+	if err := bo.GboMerge(nil, MergeOverwrite); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if err := bo.GboMerge(NewGenericBo(), MergeStrategy(99)); err == nil {
+		t.Fatalf("%s failed - expected error for invalid strategy", name)
+	}
+	bo.GboSetAttr("a", 1)
+	if err := bo.GboMerge(bo, MergeAppendLists); err != nil || string(bo.GboToJsonUnsafe()) != `{"a":1}` {
+		t.Fatalf("%s failed - Result: %s / Error: %e", name, bo.GboToJsonUnsafe(), err)
+	}
+}
+
+func TestGenericBo_Diff(t *testing.T) {
+	name := "TestGenericBo_Diff"
+	oldBo := NewGenericBo()
+	oldBo.GboFromJson([]byte(`{"a":{"b":[1,{"c":"old"},3]},"same":true,"removed":"x","type":{"k":1}}`))
+	newBo := NewGenericBo()
+	newBo.GboFromJson([]byte(`{"a":{"b":[1,{"c":"new"}]},"same":true,"added":null,"type":[1]}`))
+	expected := []GboChange{
+		{Path: "a.b[1].c", Type: GboChangeModified, OldValue: "old", NewValue: "new"},
+		{Path: "a.b[2]", Type: GboChangeRemoved, OldValue: 3.0},
+		{Path: "added", Type: GboChangeAdded, NewValue: nil},
+		{Path: "removed", Type: GboChangeRemoved, OldValue: "x"},
+		{Path: "type", Type: GboChangeModified, OldValue: map[string]interface{}{"k": 1.0}, NewValue: []interface{}{1.0}},
+	}
+	changes, err := oldBo.GboDiff(newBo)
+	if err != nil || !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, expected, changes, err)
+	}
+	for _, c := range changes {
+		if c.Type == GboChangeRemoved {
+			continue
+		}
+		if v := newBo.GboGetAttrUnsafe(c.Path, nil); !reflect.DeepEqual(v, c.NewValue) {
+			t.Fatalf("%s failed - Path: %s / Expected: %v / Received: %v", name, c.Path, c.NewValue, v)
+		}
+	}
+
+	// values are compared in JSON-equivalent form
+	oldBo = NewGenericBo()
+	oldBo.GboSetAttr("n", 1)
+	newBo = NewGenericBo()
+	newBo.GboSetAttr("n", 1.0)
+	if changes, err := oldBo.GboDiff(newBo); err != nil || len(changes) != 0 {
+		t.Fatalf("%s failed - Received: %v / Error: %e", name, changes, err)
+	}
+	if changes, err := oldBo.GboDiff(nil); err != nil || len(changes) != 1 || changes[0].Type != GboChangeRemoved {
+		t.Fatalf("%s failed - Received: %v / Error: %e", name, changes, err)
+	}
+}
+
+func TestGenericBo_Dirty(t *testing.T) {
+	name := "TestGenericBo_Dirty"
+	bo := NewGenericBo()
+	bo.GboFromJson([]byte(`{"a":{"b":[1,{"c":"value"},3]},"x":"y","z":1}`))
+	if bo.GboIsDirty() || len(bo.GboDirtyPaths()) != 0 {
+		t.Fatalf("%s failed - imported bo should be clean: %v", name, bo.GboDirtyPaths())
+	}
+
+	bo.GboSetAttr("a.b.[1].c", "new value")
+	bo.GboSetAttr("a.b[1].d", "new attr")
+	bo.GboSetAttr("x", "y")
+	if !bo.GboIsDirty() {
+		t.Fatalf("%s failed - bo should be dirty", name)
+	}
+	if paths, expected := bo.GboDirtyPaths(), []string{"a.b[1].c", "a.b[1].d", "x"}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paths)
+	}
+	// descendants of a dirty path are not reported
+	bo.GboSetAttr("a.b[1]", map[string]interface{}{})
+	if paths, expected := bo.GboDirtyPaths(), []string{"a.b[1]", "x"}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paths)
+	}
+	if paths, expected := bo.GboClone().GboDirtyPaths(), []string{"a.b[1]", "x"}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paths)
+	}
+	if attrs, expected := DirtyAttrs(bo), []string{"a", "x"}; !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, attrs)
+	}
+
+	// removing a nested attribute modifies its parent, removing a top-level attribute is reported by GboDeletedAttrs
+	bo.GboMarkClean()
+	if bo.GboIsDirty() || len(bo.GboDirtyPaths()) != 0 {
+		t.Fatalf("%s failed - bo should be clean: %v", name, bo.GboDirtyPaths())
+	}
+	bo.GboDeleteAttr("a.b[0]")
+	bo.GboSetAttr("z", 2)
+	bo.GboDeleteAttr("z")
+	if paths, expected := bo.GboDirtyPaths(), []string{"a.b"}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paths)
+	}
+	if attrs, expected := bo.GboDeletedAttrs(), []string{"z"}; !reflect.DeepEqual(attrs, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, attrs)
+	}
+	bo.GboMarkClean()
+	bo.GboDeleteAttr("x")
+	if !bo.GboIsDirty() || len(bo.GboDirtyPaths()) != 0 {
+		t.Fatalf("%s failed - bo should be dirty: %v", name, bo.GboDirtyPaths())
+	}
+
+	// merged attributes are dirty
+	bo.GboMarkClean()
+	other := NewGenericBo()
+	other.GboFromJson([]byte(`{"m":1}`))
+	bo.GboMerge(other, MergeOverwrite)
+	if paths, expected := bo.GboDirtyPaths(), []string{"m"}; !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paths)
+	}
+	bo.GboFromMap(map[string]interface{}{"k": "v"})
+	if bo.GboIsDirty() {
+		t.Fatalf("%s failed - imported bo should be clean: %v", name, bo.GboDirtyPaths())
+	}
+}
+
+func TestGenericBo_HasDeleteAttr(t *testing.T) {
+	name := "TestGenericBo_HasDeleteAttr"
+	bo := NewGenericBo()
+	bo.GboFromJson([]byte(`{"a":{"b":[1,{"c":"value"},3],"n":null},"x":"y"}`))
+	for path, expected := range map[string]bool{"a": true, "a.b": true, "a.b[1].c": true, "a.n": true, "x": true,
+		"a.b[3]": false, "a.b[-1]": false, "a.m": false, "a.b[1].d": false, "x.y": false, "z": false} {
+		if bo.GboHasAttr(path) != expected {
+			t.Fatalf("%s failed - Path: %s / Expected: %v", name, path, expected)
+		}
+	}
+
+	for _, path := range []string{"a.b[1].c", "a.b[0]", "a.n", "x", "not.exist", "a.b[9]"} {
+		if err := bo.GboDeleteAttr(path); err != nil {
+			t.Fatalf("%s failed - Path: %s / Error: %e", name, path, err)
+		}
+		if bo.GboHasAttr(path) && path != "a.b[0]" {
+			t.Fatalf("%s failed - Path [%s] still exists", name, path)
+		}
+	}
+	if js, expected := string(bo.GboToJsonUnsafe()), `{"a":{"b":[{},3]}}`; js != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, js)
+	}
+	if attrs := bo.GboDeletedAttrs(); !reflect.DeepEqual(attrs, []string{"x"}) {
+		t.Fatalf("%s failed - Received: %v", name, attrs)
+	}
+	if err := bo.GboDeleteAttr(""); err == nil {
+		t.Fatalf("%s failed - expected error for empty path", name)
+	}
+
+	// deleted attributes are tracked until set again or data is imported
+	bo.GboDeleteAttr("a")
+	if attrs := bo.GboDeletedAttrs(); !reflect.DeepEqual(attrs, []string{"a", "x"}) {
+		t.Fatalf("%s failed - Received: %v", name, attrs)
+	}
+	if attrs := bo.GboClone().GboDeletedAttrs(); !reflect.DeepEqual(attrs, []string{"a", "x"}) {
+		t.Fatalf("%s failed - Received: %v", name, attrs)
+	}
+	bo.GboSetAttr("x.y", 1)
+	if attrs := bo.GboDeletedAttrs(); !reflect.DeepEqual(attrs, []string{"a"}) {
+		t.Fatalf("%s failed - Received: %v", name, attrs)
+	}
+	bo.GboFromJson([]byte(`{}`))
+	if attrs := bo.GboDeletedAttrs(); len(attrs) != 0 {
+		t.Fatalf("%s failed - Received: %v", name, attrs)
+	}
+
+	// typed maps/slices set via GboSetAttr
+	bo.GboSetAttr("m", map[string][]int{"k": {1, 2, 3}})
+	if err := bo.GboDeleteAttr("m.k[1]"); err != nil || !reflect.DeepEqual(bo.GboGetAttrUnsafe("m.k", nil), []int{1, 3}) {
+		t.Fatalf("%s failed - Received: %v / Error: %e", name, bo.GboGetAttrUnsafe("m.k", nil), err)
+	}
+}
+
+type codecTestStruct struct {
+	Id       string           `json:"id"`
+	Count    int              `json:"count,omitempty"`
+	Ratio    float32          `json:"ratio"`
+	Tags     []string         `json:"tags"`
+	Props    map[string]int   `json:"props"`
+	Raw      []byte           `json:"raw"`
+	Fixed    [2]byte          `json:"fixed"`
+	When     time.Time        `json:"when"`
+	Ptr      *codecTestStruct `json:"ptr,omitempty"`
+	Num      json.Number      `json:"num"`
+	AsString int              `json:"as_string,string"`
+	Ignored  string           `json:"-"`
+	private  string
+	Any      interface{}       `json:"any"`
+	IntKeys  map[int]string    `json:"int_keys"`
+	Nested   map[string][]bool `json:"nested"`
+}
+
+type codecTestEmbedded struct {
+	codecTestStruct
+	Extra string
+}
+
+func TestGenericBo_ToMapFromMap(t *testing.T) {
+	name := "TestGenericBo_ToMapFromMap"
+	now := time.Now()
+	inner := codecTestStruct{Id: "inner", When: now}
+	testData := []map[string]interface{}{
+		{"a": float32(1.1), "b": "a string", "c": true, "d": nil, "e": []interface{}{1, "x", nil, uint8(2)}},
+		{"time": now, "bytes": []byte("bytes"), "invalid": "\xff", "int64": int64(1) << 60, "map": map[string]string{"k": "v"}},
+		{"struct": codecTestStruct{Id: "1", Count: 0, Ratio: 0.1, Tags: []string{"a"}, Props: map[string]int{"p": 1},
+			Raw: []byte{1, 2}, Fixed: [2]byte{3, 4}, When: now, Ptr: &inner, Num: "12.5", AsString: 7, Ignored: "ignored", private: "private",
+			Any: []int{1, 2}, IntKeys: map[int]string{1: "one"}, Nested: map[string][]bool{"n": {true}}}},
+		{"embedded": codecTestEmbedded{codecTestStruct: codecTestStruct{Id: "e"}, Extra: "extra"}, "ptr": &inner},
+		{"nil_slice": []string(nil), "nil_map": map[string]int(nil), "nil_ptr": (*codecTestStruct)(nil)},
+	}
+	for _, src := range testData {
+		expected := NewGenericBo()
+		if err := expected.GboImportViaJson(src); err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		bo := NewGenericBo()
+		if err := bo.GboFromMap(src); err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		if !reflect.DeepEqual(bo.(*GenericBo).data, expected.(*GenericBo).data) {
+			t.Fatalf("%s failed - Expected: %#v / Received: %#v", name, expected.(*GenericBo).data, bo.(*GenericBo).data)
+		}
+
+		var expectedMap map[string]interface{}
+		if err := expected.GboTransferViaJson(&expectedMap); err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		if m, err := bo.GboToMap(); err != nil || !reflect.DeepEqual(m, expectedMap) {
+			t.Fatalf("%s failed - Expected: %#v / Received: %#v / Error: %e", name, expectedMap, m, err)
+		}
+	}
+
+	// GboToMap returns a deep copy
+	bo := NewGenericBo()
+	bo.GboSetAttr("a.b", "value")
+	m, _ := bo.GboToMap()
+	bo.GboSetAttr("a.b", "changed")
+	if v := m["a"].(map[string]interface{})["b"]; v != "value" {
+		t.Fatalf("%s failed - result is not a deep copy: %v", name, m)
+	}
+	if m, err := NewGenericBo().GboToMap(); err != nil || m == nil || len(m) != 0 {
+		t.Fatalf("%s failed - Result: %#v / Error: %e", name, m, err)
+	}
+	bo.GboFromJson([]byte(`[1,2,3]`))
+	if _, err := bo.GboToMap(); err == nil {
+		t.Fatalf("%s failed - expected error for non-map data", name)
+	}
+	if err := bo.GboFromMap(map[string]interface{}{"f": math.NaN()}); err == nil {
+		t.Fatalf("%s failed - expected error for NaN", name)
+	}
+}
+
+func TestGenericBo_FromStruct(t *testing.T) {
+	name := "TestGenericBo_FromStruct"
+	src := codecTestStruct{Id: "1", Count: 2, Ratio: 0.3, When: time.Now(), AsString: 4}
+	for _, s := range []interface{}{src, &src, codecTestEmbedded{codecTestStruct: src}} {
+		expected := NewGenericBo()
+		expected.GboImportViaJson(s)
+		bo := NewGenericBo()
+		if err := bo.GboFromStruct(s); err != nil || !reflect.DeepEqual(bo.(*GenericBo).data, expected.(*GenericBo).data) {
+			t.Fatalf("%s failed - Expected: %#v / Received: %#v / Error: %e", name, expected.(*GenericBo).data, bo.(*GenericBo).data, err)
+		}
+	}
+	for _, s := range []interface{}{nil, 1, "string", map[string]interface{}{}, (*codecTestStruct)(nil)} {
+		if err := NewGenericBo().GboFromStruct(s); err == nil {
+			t.Fatalf("%s failed - expected error for input %#v", name, s)
+		}
+	}
+}
+
+// benchmarkDocument builds a large document: 'n' items, each is a map of mixed-type values.
+func benchmarkDocument(n int) map[string]interface{} {
+	doc := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		doc[fmt.Sprintf("field%d", i)] = map[string]interface{}{
+			"id": float64(i), "name": fmt.Sprintf("name %d", i), "active": i%2 == 0,
+			"tags": []interface{}{"a", "b", "c"}, "nested": map[string]interface{}{"x": 1.5, "y": "y"},
+		}
+	}
+	return doc
+}
+
+func BenchmarkGenericBo_GboToMap(b *testing.B) {
+	bo := NewGenericBo()
+	bo.GboFromMap(benchmarkDocument(1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bo.GboToMap()
+	}
+}
+
+func BenchmarkGenericBo_GboTransferViaJson(b *testing.B) {
+	bo := NewGenericBo()
+	bo.GboFromMap(benchmarkDocument(1000))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := make(map[string]interface{})
+		bo.GboTransferViaJson(&result)
+	}
+}
+
+func BenchmarkGenericBo_GboFromMap(b *testing.B) {
+	doc := benchmarkDocument(1000)
+	bo := NewGenericBo()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bo.GboFromMap(doc)
+	}
+}
+
+func BenchmarkGenericBo_GboImportViaJson(b *testing.B) {
+	doc := benchmarkDocument(1000)
+	bo := NewGenericBo()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bo.GboImportViaJson(doc)
+	}
+}
+
+type benchmarkItem struct {
+	Id     int               `json:"id"`
+	Name   string            `json:"name"`
+	Active bool              `json:"active"`
+	Tags   []string          `json:"tags"`
+	Props  map[string]string `json:"props,omitempty"`
+}
+
+type benchmarkDoc struct {
+	Id    string          `json:"id"`
+	Items []benchmarkItem `json:"items"`
+}
+
+// benchmarkStruct builds a large struct with 'n' items.
+func benchmarkStruct(n int) benchmarkDoc {
+	doc := benchmarkDoc{Id: "doc"}
+	for i := 0; i < n; i++ {
+		doc.Items = append(doc.Items, benchmarkItem{Id: i, Name: fmt.Sprintf("name %d", i), Active: i%2 == 0,
+			Tags: []string{"a", "b", "c"}, Props: map[string]string{"x": "y"}})
+	}
+	return doc
+}
+
+func BenchmarkGenericBo_GboFromStruct(b *testing.B) {
+	src := benchmarkStruct(100)
+	bo := NewGenericBo()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bo.GboFromStruct(src)
+	}
+}
+
+func BenchmarkGenericBo_GboImportViaJson_Struct(b *testing.B) {
+	src := benchmarkStruct(100)
+	bo := NewGenericBo()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bo.GboImportViaJson(src)
+	}
+}
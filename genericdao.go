@@ -1,6 +1,10 @@
 package godal
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"sync"
+)
 
 /*
 IRowMapper transforms a database row to IGenericBo and vice versa.
@@ -22,9 +26,64 @@ type IRowMapper interface {
 
 var (
 	// GdaoErrorDuplicatedEntry indicates that the write operation failed because of data integrity violation: entry/key duplicated.
-	GdaoErrorDuplicatedEntry = errors.New("data integrity violation: duplicated entry/key")
+	// Since v0.3.0, errors.Is(GdaoErrorDuplicatedEntry, ErrConflict) is true.
+	GdaoErrorDuplicatedEntry error = &DaoError{Kind: ErrConflict, Err: errors.New("data integrity violation: duplicated entry/key")}
+
+	// GdaoErrorInvalidPageToken indicates that the page token passed to GdaoFetchPage is malformed or does not match the query (available since v0.3.0).
+	GdaoErrorInvalidPageToken = errors.New("invalid page token")
+
+	// GdaoErrorConcurrentModification indicates that the write operation failed because the BO was modified by another writer:
+	// the stored version does not match the BO's version (see AbstractGenericDao.SetVersionField, available since v0.3.0).
+	// errors.Is(GdaoErrorConcurrentModification, ErrConcurrentModification) is true.
+	GdaoErrorConcurrentModification error = &DaoError{Kind: ErrConcurrentModification, Err: errors.New("concurrent modification: version mismatch")}
+
+	// ErrTxEnded indicates that the operation was made with the context of a transaction that has ended, i.e. the txCtx passed to
+	// txFunc is used after GdaoWithTransaction returned (see ITransactionalDao, available since v0.3.0).
+	ErrTxEnded = errors.New("transaction has ended")
 )
 
+/*
+IGenericBoIterator iterates over BOs fetched from database store.
+
+Sample usage:
+
+	iter, err := dao.GdaoFetchIterator(storageId, filter, sorting)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+	for iter.Next() {
+		bo := iter.Bo()
+		...
+	}
+	return iter.Err()
+
+Available since v0.3.0
+*/
+type IGenericBoIterator interface {
+	// Next advances the iterator to the next BO, returns false if there is no more BO or an error occurred (see Err).
+	Next() bool
+
+	// Bo returns the BO the iterator currently points to.
+	Bo() IGenericBo
+
+	// Err returns the error, if any, that was encountered during iteration.
+	Err() error
+
+	// Close releases resources held by the iterator. Close can be called multiple times.
+	Close() error
+}
+
+/*
+GdaoBulkResult is the result of writing one BO in a bulk write operation (see IGenericDao.GdaoCreateMany and IGenericDao.GdaoSaveMany).
+
+Available since v0.3.0
+*/
+type GdaoBulkResult struct {
+	NumRows int   // number of saved items, the same as the single-item operation would return
+	Error   error // error occurred while writing the BO (e.g. GdaoErrorDuplicatedEntry), nil if the BO was written successfully
+}
+
 /*
 IGenericDao defines API interface of a generic data-access-object.
 
@@ -54,24 +113,247 @@ type IGenericDao interface {
 	// startOffset (0-based) and numItems are for paging. numItems <= 0 means no limit. Be noted that some databases do not support startOffset nor paging at all.
 	GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]IGenericBo, error)
 
+	// GdaoFetchIterator fetches BOs from database store and returns an iterator over them (available since v0.3.0).
+	//
+	// Unlike GdaoFetchMany, BOs are fetched lazily while iterating, hence the whole result set is never held in memory.
+	// Caller must call Close() on the returned iterator when done.
+	GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (IGenericBoIterator, error)
+
+	// GdaoFetchPage fetches a page of BOs from database store and returns them along with the token to fetch the next page (available since v0.3.0).
+	//
+	// pageToken is the opaque token returned by the previous call, or "" to fetch the first page. numItems is the page size (numItems <= 0 means no limit).
+	// The returned token is "" if there is no more page. Unlike startOffset paging of GdaoFetchMany, the cost of fetching a page does not depend on its position.
+	// If pageToken is malformed or was built for another query, this function should return (nil, "", GdaoErrorInvalidPageToken)
+	GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]IGenericBo, string, error)
+
+	// GdaoCount returns the number of BOs matching the filter, without fetching them (available since v0.3.0).
+	//
+	// nil filter means "match all".
+	GdaoCount(storageId string, filter interface{}) (int64, error)
+
+	// GdaoExists returns true if at least one BO matches the filter (available since v0.3.0).
+	//
+	// This function is cheaper than GdaoCount(...) > 0 as it stops at the first matching BO.
+	GdaoExists(storageId string, filter interface{}) (bool, error)
+
 	// GdaoCreate persists one BO to database store and returns the number of saved items.
 	//
 	// If the BO already existed, this function does not modify the existing one and should return (0, GdaoErrorDuplicatedEntry)
 	GdaoCreate(storageId string, bo IGenericBo) (int, error)
 
+	// GdaoCreateMany persists many BOs to database store at once (available since v0.3.0).
+	//
+	// Each BO is created with the same semantics as GdaoCreate, and results are returned per BO, in the same order as boList.
+	// The returned error is the first error found in results (nil if all BOs were created). The operation is not atomic:
+	// some BOs may be persisted while others fail; use ITransactionalDao if atomicity is required.
+	GdaoCreateMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
+
 	// GdaoUpdate updates one existing BO and returns the number of updated items.
 	//
 	// If the BO does not exist, this function does not create new BO and should return (0, nil)
 	// If update causes data integrity violation, this function should return (0, GdaoErrorDuplicatedEntry)
+	// If optimistic locking is enabled and the stored BO has another version, this function should return (0, GdaoErrorConcurrentModification)
 	GdaoUpdate(storageId string, bo IGenericBo) (int, error)
 
+	// GdaoPatch partially updates one existing BO and returns the number of patched items (available since v0.3.0).
+	//
+	// The BO to patch is identified by bo (see GdaoCreateFilter), only fields listed in patch are modified.
+	// If the BO does not exist, this function does not create new BO and should return (0, nil)
+	// If data integrity violation occurs, this function should return (0, GdaoErrorDuplicatedEntry)
+	GdaoPatch(storageId string, bo IGenericBo, patch *PatchOpt) (int, error)
+
+	// GdaoUpdateDirty updates one existing BO, writing only attributes modified since the BO was loaded (see IGenericBo.GboDirtyPaths
+	// and IGenericBo.GboDeletedAttrs), and returns the number of updated items (available since v0.3.0).
+	// BOs returned by fetch operations are clean (see AbstractGenericDao.RowToBo).
+	//
+	// If the BO is not dirty, nothing is written and this function should return (0, nil)
+	// If the BO does not exist, this function does not create new BO and should return (0, nil)
+	// If update causes data integrity violation, this function should return (0, GdaoErrorDuplicatedEntry)
+	// If optimistic locking is enabled and the stored BO has another version, this function should return (0, GdaoErrorConcurrentModification)
+	// The BO is not marked clean, call IGenericBo.GboMarkClean once the write has succeeded.
+	GdaoUpdateDirty(storageId string, bo IGenericBo) (int, error)
+
 	// GdaoSave persists one BO to database store and returns the number of saved items.
 	//
 	// If the BO already existed, this function replace the existing one; otherwise new BO is created.
 	// If data integrity violation occurs, this function should return (0, GdaoErrorDuplicatedEntry)
+	// If optimistic locking is enabled and the stored BO has another version, this function should return (0, GdaoErrorConcurrentModification)
 	GdaoSave(storageId string, bo IGenericBo) (int, error)
+
+	// GdaoSaveMany persists many BOs to database store at once (available since v0.3.0).
+	//
+	// Each BO is saved with the same semantics as GdaoSave; results and the returned error: see GdaoCreateMany.
+	GdaoSaveMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
+}
+
+/*
+IGenericDaoWithContext extends IGenericDao with context-aware variants of the data-access operations.
+
+The context is passed down to the underlying database driver, hence deadlines and cancellation are honored.
+Each GdaoXxxCtx function has the same semantics as its IGenericDao.GdaoXxx counterpart. If ctx is nil, a default context is used.
+
+Available since v0.3.0
+*/
+type IGenericDaoWithContext interface {
+	IGenericDao
+
+	// GdaoDeleteCtx is context-aware variant of IGenericDao.GdaoDelete.
+	GdaoDeleteCtx(ctx context.Context, storageId string, bo IGenericBo) (int, error)
+
+	// GdaoDeleteManyCtx is context-aware variant of IGenericDao.GdaoDeleteMany.
+	GdaoDeleteManyCtx(ctx context.Context, storageId string, filter interface{}) (int, error)
+
+	// GdaoFetchOneCtx is context-aware variant of IGenericDao.GdaoFetchOne.
+	GdaoFetchOneCtx(ctx context.Context, storageId string, filter interface{}) (IGenericBo, error)
+
+	// GdaoFetchManyCtx is context-aware variant of IGenericDao.GdaoFetchMany.
+	GdaoFetchManyCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]IGenericBo, error)
+
+	// GdaoFetchIteratorCtx is context-aware variant of IGenericDao.GdaoFetchIterator.
+	//
+	// ctx is used for the whole iteration; if ctx is nil, a context without deadline is used.
+	GdaoFetchIteratorCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}) (IGenericBoIterator, error)
+
+	// GdaoFetchPageCtx is context-aware variant of IGenericDao.GdaoFetchPage.
+	GdaoFetchPageCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]IGenericBo, string, error)
+
+	// GdaoCountCtx is context-aware variant of IGenericDao.GdaoCount.
+	GdaoCountCtx(ctx context.Context, storageId string, filter interface{}) (int64, error)
+
+	// GdaoExistsCtx is context-aware variant of IGenericDao.GdaoExists.
+	GdaoExistsCtx(ctx context.Context, storageId string, filter interface{}) (bool, error)
+
+	// GdaoCreateCtx is context-aware variant of IGenericDao.GdaoCreate.
+	GdaoCreateCtx(ctx context.Context, storageId string, bo IGenericBo) (int, error)
+
+	// GdaoCreateManyCtx is context-aware variant of IGenericDao.GdaoCreateMany.
+	GdaoCreateManyCtx(ctx context.Context, storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
+
+	// GdaoUpdateCtx is context-aware variant of IGenericDao.GdaoUpdate.
+	GdaoUpdateCtx(ctx context.Context, storageId string, bo IGenericBo) (int, error)
+
+	// GdaoPatchCtx is context-aware variant of IGenericDao.GdaoPatch.
+	GdaoPatchCtx(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt) (int, error)
+
+	// GdaoUpdateDirtyCtx is context-aware variant of IGenericDao.GdaoUpdateDirty.
+	GdaoUpdateDirtyCtx(ctx context.Context, storageId string, bo IGenericBo) (int, error)
+
+	// GdaoSaveCtx is context-aware variant of IGenericDao.GdaoSave.
+	GdaoSaveCtx(ctx context.Context, storageId string, bo IGenericBo) (int, error)
+
+	// GdaoSaveManyCtx is context-aware variant of IGenericDao.GdaoSaveMany.
+	GdaoSaveManyCtx(ctx context.Context, storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
+}
+
+/*
+ITransactionalDao is implemented by DAOs that support backend-neutral transactions.
+
+GdaoWithTransaction starts a transaction and invokes txFunc. The transaction is carried in txCtx, and the 'dao' passed to txFunc
+is bound to txCtx: all of its Gdao* calls (and all GdaoXxxCtx calls made with txCtx) automatically join the transaction.
+If txFunc returns error the transaction is rolled back, otherwise it is committed.
+If ctx is already carrying a transaction of the same DAO, no new transaction is started: txFunc joins the existing one.
+Transactions of other DAOs carried by ctx are ignored. Once GdaoWithTransaction returns, operations made with txCtx return ErrTxEnded.
+
+Available since v0.3.0
+*/
+type ITransactionalDao interface {
+	GdaoWithTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao IGenericDao) error) error
+}
+
+/*
+NewContextBoundGenericDao wraps an IGenericDaoWithContext so that all IGenericDao's functions are executed with the specified context.
+
+For example, GdaoFetchOne(storageId, filter) of the returned DAO is equivalent to dao.GdaoFetchOneCtx(ctx, storageId, filter).
+GdaoXxxCtx functions of the returned DAO are passed through as-is.
+
+Available since v0.3.0
+*/
+func NewContextBoundGenericDao(ctx context.Context, dao IGenericDaoWithContext) IGenericDaoWithContext {
+	return &contextBoundGenericDao{IGenericDaoWithContext: dao, ctx: ctx}
+}
+
+type contextBoundGenericDao struct {
+	IGenericDaoWithContext
+	ctx context.Context
+}
+
+// GdaoDelete implements IGenericDao.GdaoDelete.
+func (dao *contextBoundGenericDao) GdaoDelete(storageId string, bo IGenericBo) (int, error) {
+	return dao.GdaoDeleteCtx(dao.ctx, storageId, bo)
+}
+
+// GdaoDeleteMany implements IGenericDao.GdaoDeleteMany.
+func (dao *contextBoundGenericDao) GdaoDeleteMany(storageId string, filter interface{}) (int, error) {
+	return dao.GdaoDeleteManyCtx(dao.ctx, storageId, filter)
+}
+
+// GdaoFetchOne implements IGenericDao.GdaoFetchOne.
+func (dao *contextBoundGenericDao) GdaoFetchOne(storageId string, filter interface{}) (IGenericBo, error) {
+	return dao.GdaoFetchOneCtx(dao.ctx, storageId, filter)
+}
+
+// GdaoFetchMany implements IGenericDao.GdaoFetchMany.
+func (dao *contextBoundGenericDao) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]IGenericBo, error) {
+	return dao.GdaoFetchManyCtx(dao.ctx, storageId, filter, sorting, startOffset, numItems)
+}
+
+// GdaoFetchIterator implements IGenericDao.GdaoFetchIterator.
+func (dao *contextBoundGenericDao) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorCtx(dao.ctx, storageId, filter, sorting)
 }
 
+// GdaoFetchPage implements IGenericDao.GdaoFetchPage.
+func (dao *contextBoundGenericDao) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]IGenericBo, string, error) {
+	return dao.GdaoFetchPageCtx(dao.ctx, storageId, filter, sorting, pageToken, numItems)
+}
+
+// GdaoCount implements IGenericDao.GdaoCount.
+func (dao *contextBoundGenericDao) GdaoCount(storageId string, filter interface{}) (int64, error) {
+	return dao.GdaoCountCtx(dao.ctx, storageId, filter)
+}
+
+// GdaoExists implements IGenericDao.GdaoExists.
+func (dao *contextBoundGenericDao) GdaoExists(storageId string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsCtx(dao.ctx, storageId, filter)
+}
+
+// GdaoCreate implements IGenericDao.GdaoCreate.
+func (dao *contextBoundGenericDao) GdaoCreate(storageId string, bo IGenericBo) (int, error) {
+	return dao.GdaoCreateCtx(dao.ctx, storageId, bo)
+}
+
+// GdaoCreateMany implements IGenericDao.GdaoCreateMany.
+func (dao *contextBoundGenericDao) GdaoCreateMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error) {
+	return dao.GdaoCreateManyCtx(dao.ctx, storageId, boList)
+}
+
+// GdaoUpdate implements IGenericDao.GdaoUpdate.
+func (dao *contextBoundGenericDao) GdaoUpdate(storageId string, bo IGenericBo) (int, error) {
+	return dao.GdaoUpdateCtx(dao.ctx, storageId, bo)
+}
+
+// GdaoPatch implements IGenericDao.GdaoPatch.
+func (dao *contextBoundGenericDao) GdaoPatch(storageId string, bo IGenericBo, patch *PatchOpt) (int, error) {
+	return dao.GdaoPatchCtx(dao.ctx, storageId, bo, patch)
+}
+
+// GdaoUpdateDirty implements IGenericDao.GdaoUpdateDirty.
+func (dao *contextBoundGenericDao) GdaoUpdateDirty(storageId string, bo IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyCtx(dao.ctx, storageId, bo)
+}
+
+// GdaoSave implements IGenericDao.GdaoSave.
+func (dao *contextBoundGenericDao) GdaoSave(storageId string, bo IGenericBo) (int, error) {
+	return dao.GdaoSaveCtx(dao.ctx, storageId, bo)
+}
+
+// GdaoSaveMany implements IGenericDao.GdaoSaveMany.
+func (dao *contextBoundGenericDao) GdaoSaveMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error) {
+	return dao.GdaoSaveManyCtx(dao.ctx, storageId, boList)
+}
+
+/*----------------------------------------------------------------------*/
+
 // NewAbstractGenericDao constructs a new 'AbstractGenericDao' instance.
 func NewAbstractGenericDao(gdao IGenericDao) *AbstractGenericDao {
 	return &AbstractGenericDao{IGenericDao: gdao}
@@ -87,13 +369,33 @@ Function implementations (n = No, y = Yes, i = inherited):
 	(n) GdaoDeleteMany(storageId string, filter interface{}) (int, error)
 	(n) GdaoFetchOne(storageId string, filter interface{}) (IGenericBo, error)
 	(n) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]IGenericBo, error)
+	(n) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (IGenericBoIterator, error)
+	(n) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]IGenericBo, string, error)
+	(n) GdaoCount(storageId string, filter interface{}) (int64, error)
+	(n) GdaoExists(storageId string, filter interface{}) (bool, error)
 	(n) GdaoCreate(storageId string, bo IGenericBo) (int, error)
+	(n) GdaoCreateMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
 	(n) GdaoUpdate(storageId string, bo IGenericBo) (int, error)
+	(n) GdaoPatch(storageId string, bo IGenericBo, patch *PatchOpt) (int, error)
+	(n) GdaoUpdateDirty(storageId string, bo IGenericBo) (int, error)
 	(n) GdaoSave(storageId string, bo IGenericBo) (int, error)
+	(n) GdaoSaveMany(storageId string, boList []IGenericBo) ([]GdaoBulkResult, error)
 */
 type AbstractGenericDao struct {
 	IGenericDao
-	rowMapper IRowMapper
+	rowMapper     IRowMapper
+	versionLock   sync.RWMutex
+	versionFields map[string]string // storageId -> version field, see SetVersionField
+	validatorLock sync.RWMutex
+	validators    map[string]IBoValidator // storageId -> validator, see SetValidator
+	hookLock      sync.RWMutex
+	hooks         map[HookPoint][]BoHook    // see AddHook
+	patchHooks    map[HookPoint][]PatchHook // see AddPatchHook
+	retryLock     sync.RWMutex
+	retryPolicy   *RetryPolicy // see SetRetryPolicy
+
+	instrumentationLock sync.RWMutex
+	instrumentation     IInstrumentation // see SetInstrumentation
 }
 
 /*
@@ -114,3 +416,21 @@ func (dao *AbstractGenericDao) SetRowMapper(rowMapper IRowMapper) *AbstractGener
 	dao.rowMapper = rowMapper
 	return dao
 }
+
+/*
+RowToBo transforms a row fetched from storage to IGenericBo using the DAO's IRowMapper, invokes HookAfterFetch hooks (see AddHook)
+and marks the BO clean (see IGenericBo.GboMarkClean) so that it reports only changes made after it has been fetched.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) RowToBo(ctx context.Context, storageId string, row interface{}) (IGenericBo, error) {
+	bo, err := dao.rowMapper.ToBo(storageId, row)
+	if err != nil || bo == nil {
+		return bo, err
+	}
+	if err := dao.RunHooks(ctx, HookAfterFetch, storageId, bo); err != nil {
+		return nil, err
+	}
+	bo.GboMarkClean()
+	return bo, nil
+}
@@ -0,0 +1,234 @@
+package godal
+
+import (
+	"context"
+)
+
+/*
+HookPoint identifies when a BoHook is invoked, see AbstractGenericDao.AddHook.
+
+Available since v0.3.0
+*/
+type HookPoint int
+
+const (
+	// HookBeforeCreate hooks are invoked before a BO is created (GdaoCreate, GdaoCreateMany).
+	HookBeforeCreate HookPoint = iota
+	// HookAfterCreate hooks are invoked after a BO has been created.
+	HookAfterCreate
+	// HookBeforeUpdate hooks are invoked before a BO is updated (GdaoUpdate, GdaoUpdateDirty).
+	HookBeforeUpdate
+	// HookAfterUpdate hooks are invoked after a BO has been updated.
+	HookAfterUpdate
+	// HookBeforeSave hooks are invoked before a BO is saved (GdaoSave, GdaoSaveMany).
+	HookBeforeSave
+	// HookAfterSave hooks are invoked after a BO has been saved.
+	HookAfterSave
+	// HookBeforeDelete hooks are invoked before a BO is deleted (GdaoDelete).
+	HookBeforeDelete
+	// HookAfterDelete hooks are invoked after a BO has been deleted.
+	HookAfterDelete
+	// HookAfterFetch hooks are invoked on each BO fetched from storage (GdaoFetchOne, GdaoFetchMany, GdaoFetchIterator, GdaoFetchPage).
+	HookAfterFetch
+	// HookBeforePatch hooks are invoked before a BO is patched (GdaoPatch), with the BO identifying the patched one; see also AddPatchHook.
+	HookBeforePatch
+	// HookAfterPatch hooks are invoked after a BO has been patched.
+	HookAfterPatch
+)
+
+/*
+BoHook is a function invoked by DAOs around operations on BOs, see AbstractGenericDao.AddHook. A hook can modify the BO, or abort the
+operation by returning an error.
+
+Available since v0.3.0
+*/
+type BoHook func(ctx context.Context, storageId string, bo IGenericBo) error
+
+/*
+PatchHook is a function invoked by DAOs around GdaoPatch, see AbstractGenericDao.AddPatchHook. A hook can modify the patch (e.g. add
+fields to set), or abort the operation by returning an error.
+
+Available since v0.3.0
+*/
+type PatchHook func(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt) error
+
+/*
+AddHook registers a hook to be invoked at a hook point, for all storages (use the 'storageId' parameter of the hook to filter storages).
+
+Hooks are invoked by all generic DAO implementations, in the order they are registered, with the context of the operation (a non-nil
+context, context.Background() if the operation has none):
+
+	- "before" hooks run before the BO is validated (see SetValidator) and written; an error aborts the operation and is returned as-is
+	  (or reported in the BO's GdaoBulkResult). Changes made to the BO are written.
+	- "after" hooks run after the BO has been written successfully (i.e. no error and at least one row affected); an error is returned
+	  to the caller, but the write is not undone (except when the operation runs inside a transaction that is then rolled back).
+	  For writes buffered by a DynamoDB transaction, "after" hooks run when the write is buffered.
+	- HookAfterFetch hooks run on each fetched BO before it is returned, and before it is marked clean (see RowToBo); an error fails
+	  the fetch.
+	- GdaoPatch invokes HookBeforePatch/HookAfterPatch hooks with the BO it is passed (which only identifies the patched BO): changes
+	  made to the BO are not written, use AddPatchHook to access the patch.
+	- GdaoDeleteMany does not take a BO and does not invoke hooks. GdaoUpdateDirty does not invoke hooks if the BO is not dirty.
+
+Example: stamp "updated_at" on every write
+
+	stamp := func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return bo.GboSetAttr("updated_at", time.Now())
+	}
+	dao.AddHook(godal.HookBeforeCreate, stamp).AddHook(godal.HookBeforeUpdate, stamp).AddHook(godal.HookBeforeSave, stamp)
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) AddHook(point HookPoint, hook BoHook) *AbstractGenericDao {
+	dao.hookLock.Lock()
+	defer dao.hookLock.Unlock()
+	if dao.hooks == nil {
+		dao.hooks = make(map[HookPoint][]BoHook)
+	}
+	dao.hooks[point] = append(dao.hooks[point], hook)
+	return dao
+}
+
+/*
+AddPatchHook registers a hook to be invoked by GdaoPatch at HookBeforePatch or HookAfterPatch, for all storages. Patch hooks are invoked
+after the hooks registered with AddHook at the same hook point, in the order they are registered:
+
+	- HookBeforePatch hooks run before the patch is validated (see IPatchValidator) and written; an error aborts the operation and is
+	  returned as-is. Changes made to the patch are written.
+	- HookAfterPatch hooks run after the BO has been patched successfully (i.e. no error and at least one row affected).
+
+Example: stamp "updated_at" on every patch
+
+	dao.AddPatchHook(godal.HookBeforePatch, func(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) error {
+		patch.Set("updated_at", time.Now())
+		return nil
+	})
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) AddPatchHook(point HookPoint, hook PatchHook) *AbstractGenericDao {
+	dao.hookLock.Lock()
+	defer dao.hookLock.Unlock()
+	if dao.patchHooks == nil {
+		dao.patchHooks = make(map[HookPoint][]PatchHook)
+	}
+	dao.patchHooks[point] = append(dao.patchHooks[point], hook)
+	return dao
+}
+
+/*
+ClearHooks removes all hooks (including patch hooks, see AddPatchHook) registered at a hook point.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) ClearHooks(point HookPoint) *AbstractGenericDao {
+	dao.hookLock.Lock()
+	defer dao.hookLock.Unlock()
+	delete(dao.hooks, point)
+	delete(dao.patchHooks, point)
+	return dao
+}
+
+/*
+RunHooks invokes hooks registered at a hook point, in the order they are registered; it stops at the first error.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) RunHooks(ctx context.Context, point HookPoint, storageId string, bo IGenericBo) error {
+	dao.hookLock.RLock()
+	hooks := dao.hooks[point]
+	dao.hookLock.RUnlock()
+	if len(hooks) == 0 || bo == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, hook := range hooks {
+		if err := hook(ctx, storageId, bo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+BeforeWrite is called by DAOs before a BO is written: it invokes the "before" hooks (see AddHook) then, except for HookBeforeDelete,
+validates the BO (see ValidateBo).
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) BeforeWrite(ctx context.Context, point HookPoint, storageId string, bo IGenericBo) error {
+	if err := dao.RunHooks(ctx, point, storageId, bo); err != nil {
+		return err
+	}
+	if point == HookBeforeDelete {
+		return nil
+	}
+	return dao.ValidateBo(storageId, bo)
+}
+
+/*
+AfterWrite is called by DAOs with the result of a write: if the write succeeded (no error and numRows > 0), it invokes the "after"
+hooks (see AddHook). The (possibly updated) result is returned.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) AfterWrite(ctx context.Context, point HookPoint, storageId string, bo IGenericBo, numRows int, err error) (int, error) {
+	if err == nil && numRows > 0 {
+		err = dao.RunHooks(ctx, point, storageId, bo)
+	}
+	return numRows, err
+}
+
+/*
+RunPatchHooks invokes the hooks registered at a hook point with AddHook (passed the BO), then those registered with AddPatchHook; it stops
+at the first error.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) RunPatchHooks(ctx context.Context, point HookPoint, storageId string, bo IGenericBo, patch *PatchOpt) error {
+	if err := dao.RunHooks(ctx, point, storageId, bo); err != nil {
+		return err
+	}
+	dao.hookLock.RLock()
+	hooks := dao.patchHooks[point]
+	dao.hookLock.RUnlock()
+	if len(hooks) == 0 {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for _, hook := range hooks {
+		if err := hook(ctx, storageId, bo, patch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+BeforePatch is called by DAOs before a BO is patched: it invokes the HookBeforePatch hooks (see RunPatchHooks) then validates the patch
+(see ValidatePatch).
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) BeforePatch(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt) error {
+	if err := dao.RunPatchHooks(ctx, HookBeforePatch, storageId, bo, patch); err != nil {
+		return err
+	}
+	return dao.ValidatePatch(storageId, bo, patch)
+}
+
+/*
+AfterPatch is called by DAOs with the result of GdaoPatch: if the patch succeeded (no error and numRows > 0), it invokes the
+HookAfterPatch hooks (see RunPatchHooks). The (possibly updated) result is returned.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) AfterPatch(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt, numRows int, err error) (int, error) {
+	if err == nil && numRows > 0 {
+		err = dao.RunPatchHooks(ctx, HookAfterPatch, storageId, bo, patch)
+	}
+	return numRows, err
+}
@@ -0,0 +1,127 @@
+package godal
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestAbstractGenericDao_Hooks(t *testing.T) {
+	name := "TestAbstractGenericDao_Hooks"
+	dao := NewAbstractGenericDao(nil)
+	bo := NewGenericBo()
+	if err := dao.RunHooks(nil, HookBeforeCreate, "table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+
+	calls := make([]string, 0)
+	errAbort := errors.New("abort")
+	newHook := func(id string, err error) BoHook {
+		return func(ctx context.Context, storageId string, bo IGenericBo) error {
+			if ctx == nil {
+				return errors.New("nil context")
+			}
+			calls = append(calls, id+":"+storageId)
+			return err
+		}
+	}
+	dao.AddHook(HookBeforeCreate, newHook("1", nil)).AddHook(HookBeforeCreate, newHook("2", errAbort)).AddHook(HookBeforeCreate, newHook("3", nil))
+	if err := dao.RunHooks(nil, HookBeforeCreate, "table", bo); err != errAbort {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, errAbort, err)
+	}
+	if expected := []string{"1:table", "2:table"}; !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, calls)
+	}
+	dao.ClearHooks(HookBeforeCreate)
+	if err := dao.RunHooks(context.Background(), HookBeforeCreate, "table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+
+	// "before" hooks run before validation
+	dao.SetValidator("table", BoValidatorFunc(func(storageId string, bo IGenericBo) error {
+		if bo.GboGetAttrUnsafe("stamped", nil) != true {
+			return errors.New("not stamped")
+		}
+		return nil
+	}))
+	if err := dao.BeforeWrite(nil, HookBeforeSave, "table", bo); err == nil {
+		t.Fatalf("%s failed - expected validation error", name)
+	}
+	if err := dao.BeforeWrite(nil, HookBeforeDelete, "table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	dao.AddHook(HookBeforeSave, func(ctx context.Context, storageId string, bo IGenericBo) error {
+		return bo.GboSetAttr("stamped", true)
+	})
+	if err := dao.BeforeWrite(nil, HookBeforeSave, "table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+
+	// "after" hooks run on success only
+	calls = calls[:0]
+	dao.AddHook(HookAfterSave, newHook("after", errAbort))
+	if numRows, err := dao.AfterWrite(nil, HookAfterSave, "table", bo, 0, nil); numRows != 0 || err != nil {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	errWrite := errors.New("write")
+	if numRows, err := dao.AfterWrite(nil, HookAfterSave, "table", bo, 1, errWrite); numRows != 1 || err != errWrite {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if len(calls) != 0 {
+		t.Fatalf("%s failed - unexpected calls: %v", name, calls)
+	}
+	if numRows, err := dao.AfterWrite(nil, HookAfterSave, "table", bo, 1, nil); numRows != 1 || err != errAbort {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestAbstractGenericDao_PatchHooks(t *testing.T) {
+	name := "TestAbstractGenericDao_PatchHooks"
+	dao := NewAbstractGenericDao(nil)
+	bo := NewGenericBo()
+	bo.GboSetAttr("id", "1")
+	patch := NewPatchOpt().Set("name", "x")
+
+	calls := make([]string, 0)
+	dao.AddHook(HookBeforePatch, func(ctx context.Context, storageId string, bo IGenericBo) error {
+		calls = append(calls, "bo:"+bo.GboGetAttrUnsafe("id", nil).(string))
+		return nil
+	})
+	dao.AddPatchHook(HookBeforePatch, func(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt) error {
+		calls = append(calls, "patch:"+storageId)
+		patch.Set("stamped", true)
+		return nil
+	})
+	if err := dao.BeforePatch(nil, "table", bo, patch); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if expected := []string{"bo:1", "patch:table"}; !reflect.DeepEqual(calls, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, calls)
+	}
+	if expected := NewPatchOpt().Set("name", "x").Set("stamped", true); !reflect.DeepEqual(patch, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, patch)
+	}
+
+	// HookBeforePatch hooks run before validation
+	dao.SetValidator("table", &BoSchema{Properties: map[string]*BoSchema{"stamped": {Type: SchemaTypeString}}})
+	if err := dao.BeforePatch(nil, "table", bo, NewPatchOpt()); err == nil {
+		t.Fatalf("%s failed - expected validation error", name)
+	}
+
+	// HookAfterPatch hooks run on success only
+	errAbort := errors.New("abort")
+	dao.AddPatchHook(HookAfterPatch, func(ctx context.Context, storageId string, bo IGenericBo, patch *PatchOpt) error {
+		return errAbort
+	})
+	if numRows, err := dao.AfterPatch(nil, "table", bo, patch, 0, nil); numRows != 0 || err != nil {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.AfterPatch(nil, "table", bo, patch, 1, nil); numRows != 1 || err != errAbort {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	dao.ClearHooks(HookAfterPatch)
+	if _, err := dao.AfterPatch(nil, "table", bo, patch, 1, nil); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+}
@@ -0,0 +1,243 @@
+package godal
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+/*
+DaoOperation describes a DAO call reported to IInstrumentation.
+
+Available since v0.3.0
+*/
+type DaoOperation struct {
+	Backend     string    // generic DAO implementation: "sql", "mongo", "dynamodb" or "memory"
+	Operation   string    // name of the DAO function, e.g. "GdaoCreate" or "SqlExecute"
+	StorageId   string    // the storage (table/collection) targeted by the call, empty if unknown (e.g. SqlExecute)
+	Statement   string    // final SQL statement (SqlExecute/SqlQuery)
+	NumBindings int       // number of values bound to the SQL statement (SqlExecute/SqlQuery)
+	StartTime   time.Time // time the call started
+
+	// set when the call finishes
+	NumRows  int           // number of rows affected (write operations) or returned (fetch operations)
+	Duration time.Duration // duration of the call
+	Err      error         // error returned by the call
+
+	instrumentation IInstrumentation
+	ctx             context.Context
+}
+
+/*
+Finish reports that the operation finished (see IInstrumentation.OnFinish); it does nothing if op is nil.
+
+Available since v0.3.0
+*/
+func (op *DaoOperation) Finish(numRows int, err error) {
+	if op == nil {
+		return
+	}
+	op.NumRows, op.Err, op.Duration = numRows, err, time.Since(op.StartTime)
+	op.instrumentation.OnFinish(op.ctx, op)
+}
+
+/*
+FinishBulk is the variant of Finish for bulk operations: the number of rows is the sum of NumRows of the results.
+
+Available since v0.3.0
+*/
+func (op *DaoOperation) FinishBulk(results []GdaoBulkResult, err error) {
+	if op == nil {
+		return
+	}
+	numRows := 0
+	for _, result := range results {
+		numRows += result.NumRows
+	}
+	op.Finish(numRows, err)
+}
+
+// String returns a short description of the operation, e.g. "sql.GdaoCreate(table)".
+func (op *DaoOperation) String() string {
+	s := op.Backend + "." + op.Operation + "(" + op.StorageId + ")"
+	if op.Statement != "" {
+		s += " [" + op.Statement + "] (" + strconv.Itoa(op.NumBindings) + " bindings)"
+	}
+	return s
+}
+
+/*
+IInstrumentation receives callbacks around DAO calls, see AbstractGenericDao.SetInstrumentation.
+
+	- OnStart is called when a call starts; the returned context is passed to OnFinish, and is used by the call if the caller supplied
+	  a context (e.g. GdaoXxxCtx), hence spans started by OnStart can be propagated to nested calls.
+	- OnFinish is called when the call finishes, with NumRows, Duration and Err set.
+
+Available since v0.3.0
+*/
+type IInstrumentation interface {
+	OnStart(ctx context.Context, op *DaoOperation) context.Context
+	OnFinish(ctx context.Context, op *DaoOperation)
+}
+
+/*
+SetInstrumentation sets the instrumentation receiving callbacks around DAO calls, nil to disable instrumentation.
+
+Generic DAO implementations report each Gdao* call (one operation per call, whatever its variant: GdaoXxx, GdaoXxxCtx,
+GdaoXxxWithTx/GdaoXxxWithContext). Gdao* calls made internally (e.g. GdaoSave calls made by GdaoSaveMany) are reported too, nested
+in the outer call; GenericDaoSql also reports each SqlExecute/SqlQuery call with its statement, nested in the Gdao* call.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) SetInstrumentation(instrumentation IInstrumentation) *AbstractGenericDao {
+	dao.instrumentationLock.Lock()
+	defer dao.instrumentationLock.Unlock()
+	dao.instrumentation = instrumentation
+	return dao
+}
+
+/*
+GetInstrumentation returns the instrumentation set by SetInstrumentation.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) GetInstrumentation() IInstrumentation {
+	dao.instrumentationLock.RLock()
+	defer dao.instrumentationLock.RUnlock()
+	return dao.instrumentation
+}
+
+/*
+StartOp is called by DAOs when a call starts. If an instrumentation is set, op is reported to IInstrumentation.OnStart and
+returned along with the context to use for the call; otherwise, (ctx, nil) is returned. Either way, DAOs call op.Finish when the
+call finishes.
+
+A nil ctx is returned as-is (and context.Background() is passed to OnStart), so that DAOs still apply their default context.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) StartOp(ctx context.Context, op *DaoOperation) (context.Context, *DaoOperation) {
+	instrumentation := dao.GetInstrumentation()
+	if instrumentation == nil {
+		return ctx, nil
+	}
+	op.instrumentation, op.StartTime = instrumentation, time.Now()
+	if ctx == nil {
+		op.ctx = instrumentation.OnStart(context.Background(), op)
+		return nil, op
+	}
+	op.ctx = instrumentation.OnStart(ctx, op)
+	return op.ctx, op
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+ISpan is a minimal tracing span, see ITracer.
+
+Available since v0.3.0
+*/
+type ISpan interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+/*
+ITracer is a minimal tracer, to be adapted to the tracing library in use (e.g. OpenTelemetry, OpenTracing).
+
+Available since v0.3.0
+*/
+type ITracer interface {
+	// StartSpan starts a new span, child of the span carried by ctx (if any), and returns the context carrying the new span.
+	StartSpan(ctx context.Context, name string) (context.Context, ISpan)
+}
+
+/*
+IMetrics is a minimal metrics recorder, to be adapted to the metrics library in use (e.g. Prometheus).
+
+Available since v0.3.0
+*/
+type IMetrics interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveHistogram(name string, labels map[string]string, value float64)
+}
+
+const (
+	// MetricOperationsTotal is the counter of DAO calls recorded by TelemetryInstrumentation,
+	// labels: "backend", "operation", "storage_id", "result" ("ok" or "error").
+	MetricOperationsTotal = "godal_dao_operations_total"
+
+	// MetricOperationDuration is the histogram of DAO call durations (in seconds) recorded by TelemetryInstrumentation,
+	// labels: "backend", "operation", "storage_id".
+	MetricOperationDuration = "godal_dao_operation_duration_seconds"
+)
+
+/*
+TelemetryInstrumentation is an IInstrumentation that records spans through an ITracer and counters/histograms through an IMetrics.
+
+	- a span named "godal.<backend>.<operation>" is started for each DAO call, with attributes "db.system", "godal.storage_id",
+	  "db.statement" and "godal.num_bindings" (if any), and "godal.num_rows" (when the call finishes); errors are recorded to the span.
+	- MetricOperationsTotal is incremented and MetricOperationDuration is observed for each DAO call.
+
+Example:
+
+	dao.SetInstrumentation(godal.NewTelemetryInstrumentation(myTracer, myMetrics))
+
+Available since v0.3.0
+*/
+type TelemetryInstrumentation struct {
+	tracer  ITracer
+	metrics IMetrics
+}
+
+/*
+NewTelemetryInstrumentation creates a new TelemetryInstrumentation; tracer or metrics can be nil.
+
+Available since v0.3.0
+*/
+func NewTelemetryInstrumentation(tracer ITracer, metrics IMetrics) *TelemetryInstrumentation {
+	return &TelemetryInstrumentation{tracer: tracer, metrics: metrics}
+}
+
+type ctxKeySpan struct{}
+
+// OnStart implements IInstrumentation.OnStart.
+func (ti *TelemetryInstrumentation) OnStart(ctx context.Context, op *DaoOperation) context.Context {
+	if ti.tracer == nil {
+		return ctx
+	}
+	ctx, span := ti.tracer.StartSpan(ctx, "godal."+op.Backend+"."+op.Operation)
+	span.SetAttribute("db.system", op.Backend)
+	if op.StorageId != "" {
+		span.SetAttribute("godal.storage_id", op.StorageId)
+	}
+	if op.Statement != "" {
+		span.SetAttribute("db.statement", op.Statement)
+		span.SetAttribute("godal.num_bindings", op.NumBindings)
+	}
+	return context.WithValue(ctx, ctxKeySpan{}, span)
+}
+
+// OnFinish implements IInstrumentation.OnFinish.
+func (ti *TelemetryInstrumentation) OnFinish(ctx context.Context, op *DaoOperation) {
+	if span, ok := ctx.Value(ctxKeySpan{}).(ISpan); ok && span != nil {
+		span.SetAttribute("godal.num_rows", op.NumRows)
+		if op.Err != nil {
+			span.RecordError(op.Err)
+		}
+		span.End()
+	}
+	if ti.metrics != nil {
+		result := "ok"
+		if op.Err != nil {
+			result = "error"
+		}
+		ti.metrics.IncCounter(MetricOperationsTotal, map[string]string{
+			"backend": op.Backend, "operation": op.Operation, "storage_id": op.StorageId, "result": result,
+		})
+		ti.metrics.ObserveHistogram(MetricOperationDuration, map[string]string{
+			"backend": op.Backend, "operation": op.Operation, "storage_id": op.StorageId,
+		}, op.Duration.Seconds())
+	}
+}
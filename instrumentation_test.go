@@ -0,0 +1,154 @@
+package godal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type ctxKeyTest struct{}
+
+type testInstrumentation struct {
+	started  []*DaoOperation
+	finished []*DaoOperation
+}
+
+func (ti *testInstrumentation) OnStart(ctx context.Context, op *DaoOperation) context.Context {
+	ti.started = append(ti.started, op)
+	return context.WithValue(ctx, ctxKeyTest{}, op.Operation)
+}
+
+func (ti *testInstrumentation) OnFinish(ctx context.Context, op *DaoOperation) {
+	if ctx.Value(ctxKeyTest{}) != op.Operation {
+		panic("context returned by OnStart is expected to be passed to OnFinish")
+	}
+	ti.finished = append(ti.finished, op)
+}
+
+func TestAbstractGenericDao_StartOp(t *testing.T) {
+	name := "TestAbstractGenericDao_StartOp"
+	dao := NewAbstractGenericDao(nil)
+	ctx := context.Background()
+	if opCtx, op := dao.StartOp(ctx, &DaoOperation{Operation: "GdaoCreate"}); opCtx != ctx || op != nil {
+		t.Fatalf("%s failed - no operation is expected to be reported without instrumentation", name)
+	} else {
+		op.Finish(1, nil)
+	}
+
+	instrumentation := &testInstrumentation{}
+	if dao.SetInstrumentation(instrumentation).GetInstrumentation() != instrumentation {
+		t.Fatalf("%s failed - unexpected instrumentation", name)
+	}
+	opCtx, op := dao.StartOp(ctx, &DaoOperation{Backend: "test", Operation: "GdaoCreate", StorageId: "table"})
+	if op == nil || opCtx.Value(ctxKeyTest{}) != "GdaoCreate" || len(instrumentation.started) != 1 || op.StartTime.IsZero() {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+	errTest := errors.New("test")
+	op.Finish(2, errTest)
+	if len(instrumentation.finished) != 1 || op.NumRows != 2 || op.Err != errTest || op.Duration < 0 {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+
+	// nil context is returned as-is
+	opCtx, op = dao.StartOp(nil, &DaoOperation{Backend: "test", Operation: "GdaoFetchMany", StorageId: "table"})
+	if opCtx != nil || op == nil {
+		t.Fatalf("%s failed - Context: %v / Operation: %v", name, opCtx, op)
+	}
+	op.FinishBulk([]GdaoBulkResult{{NumRows: 1}, {Error: errTest}, {NumRows: 1}}, errTest)
+	if len(instrumentation.finished) != 2 || op.NumRows != 2 {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+
+	if expected := "test.SqlExecute() [DELETE FROM t WHERE id=?] (1 bindings)"; (&DaoOperation{Backend: "test", Operation: "SqlExecute", Statement: "DELETE FROM t WHERE id=?", NumBindings: 1}).String() != expected {
+		t.Fatalf("%s failed - Expected: %s", name, expected)
+	}
+}
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (tr *testTracer) StartSpan(ctx context.Context, name string) (context.Context, ISpan) {
+	span := &testSpan{name: name, attributes: make(map[string]interface{})}
+	tr.spans = append(tr.spans, span)
+	return ctx, span
+}
+
+type testMetrics struct {
+	counters   map[string]int
+	histograms map[string][]float64
+}
+
+func (m *testMetrics) IncCounter(name string, labels map[string]string) {
+	m.counters[name+"/"+labels["operation"]+"/"+labels["result"]]++
+}
+
+func (m *testMetrics) ObserveHistogram(name string, labels map[string]string, value float64) {
+	key := name + "/" + labels["operation"]
+	m.histograms[key] = append(m.histograms[key], value)
+}
+
+func TestTelemetryInstrumentation(t *testing.T) {
+	name := "TestTelemetryInstrumentation"
+	tracer := &testTracer{}
+	metrics := &testMetrics{counters: make(map[string]int), histograms: make(map[string][]float64)}
+	dao := NewAbstractGenericDao(nil)
+	dao.SetInstrumentation(NewTelemetryInstrumentation(tracer, metrics))
+
+	_, op := dao.StartOp(nil, &DaoOperation{Backend: "sql", Operation: "SqlQuery", Statement: "SELECT * FROM t WHERE id=?", NumBindings: 1})
+	op.Finish(0, nil)
+	_, op = dao.StartOp(context.Background(), &DaoOperation{Backend: "sql", Operation: "GdaoCreate", StorageId: "t"})
+	errTest := errors.New("test")
+	op.Finish(0, errTest)
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("%s failed - expected 2 spans but received %d", name, len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "godal.sql.SqlQuery" || !span.ended || span.err != nil || span.attributes["db.system"] != "sql" ||
+		span.attributes["db.statement"] != "SELECT * FROM t WHERE id=?" || span.attributes["godal.num_bindings"] != 1 ||
+		span.attributes["godal.num_rows"] != 0 {
+		t.Fatalf("%s failed - Span: %v", name, span)
+	}
+	if _, ok := span.attributes["godal.storage_id"]; ok {
+		t.Fatalf("%s failed - Span: %v", name, span)
+	}
+	span = tracer.spans[1]
+	if span.name != "godal.sql.GdaoCreate" || !span.ended || span.err != errTest || span.attributes["godal.storage_id"] != "t" {
+		t.Fatalf("%s failed - Span: %v", name, span)
+	}
+	if _, ok := span.attributes["db.statement"]; ok {
+		t.Fatalf("%s failed - Span: %v", name, span)
+	}
+
+	if metrics.counters[MetricOperationsTotal+"/SqlQuery/ok"] != 1 || metrics.counters[MetricOperationsTotal+"/GdaoCreate/error"] != 1 {
+		t.Fatalf("%s failed - Counters: %v", name, metrics.counters)
+	}
+	if len(metrics.histograms[MetricOperationDuration+"/SqlQuery"]) != 1 || len(metrics.histograms[MetricOperationDuration+"/GdaoCreate"]) != 1 {
+		t.Fatalf("%s failed - Histograms: %v", name, metrics.histograms)
+	}
+
+	// tracer and metrics are optional
+	dao.SetInstrumentation(NewTelemetryInstrumentation(nil, nil))
+	_, op = dao.StartOp(nil, &DaoOperation{Backend: "sql", Operation: "GdaoCreate", StorageId: "t"})
+	op.Finish(1, nil)
+}
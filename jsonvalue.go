@@ -0,0 +1,275 @@
+package godal
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+var (
+	typeJsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	typeTextMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+/*
+toJsonValue returns the value json.Unmarshal would produce (into an interface{}) from the JSON encoding of v, i.e. a tree of nil, bool,
+float64, string, []interface{} and map[string]interface{} values. The result never shares maps or slices with v.
+
+Common types are converted directly, structs are converted using a cached per-type codec; values whose JSON encoding is customized
+(json.Marshaler, encoding.TextMarshaler, embedded structs, ",string" option, etc) fall back to JSON marshalling.
+*/
+func toJsonValue(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if !utf8.ValidString(x) {
+			return viaJson(x)
+		}
+		return x, nil
+	case bool:
+		return x, nil
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return viaJson(x)
+		}
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case json.Number:
+		return viaJson(x)
+	case map[string]interface{}:
+		if x == nil {
+			return nil, nil
+		}
+		result := make(map[string]interface{}, len(x))
+		for k, e := range x {
+			if !utf8.ValidString(k) {
+				return viaJson(x)
+			}
+			var err error
+			if result[k], err = toJsonValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case []interface{}:
+		if x == nil {
+			return nil, nil
+		}
+		result := make([]interface{}, len(x))
+		for i, e := range x {
+			var err error
+			if result[i], err = toJsonValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	return reflectJsonValue(reflect.ValueOf(v))
+}
+
+// viaJson converts v using a JSON round trip.
+func viaJson(v interface{}) (interface{}, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	return result, json.Unmarshal(js, &result)
+}
+
+// reflectJsonValue is the reflection-based part of toJsonValue.
+func reflectJsonValue(v reflect.Value) (interface{}, error) {
+	if !v.IsValid() {
+		return nil, nil
+	}
+	t := v.Type()
+	if t.Implements(typeJsonMarshaler) || t.Implements(typeTextMarshaler) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil, nil
+		}
+		return viaJson(v.Interface())
+	}
+	if v.CanAddr() && (reflect.PtrTo(t).Implements(typeJsonMarshaler) || reflect.PtrTo(t).Implements(typeTextMarshaler)) {
+		// same as encoding/json: pointer-receiver marshalers are used for addressable values only
+		return viaJson(v.Addr().Interface())
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return viaJson(v.Interface())
+		}
+		// float32 is encoded with 32-bit precision
+		return strconv.ParseFloat(strconv.FormatFloat(f, 'g', -1, 32), 64)
+	case reflect.Float64:
+		if f := v.Float(); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f, nil
+		}
+		return viaJson(v.Interface())
+	case reflect.String:
+		if !utf8.ValidString(v.String()) {
+			// invalid UTF-8 is replaced by encoding/json
+			return viaJson(v.Interface())
+		}
+		return v.String(), nil
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return reflectJsonValue(v.Elem())
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return viaJson(v.Interface())
+		}
+		if v.IsNil() {
+			return nil, nil
+		}
+		result := make(map[string]interface{}, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			if !utf8.ValidString(iter.Key().String()) {
+				return viaJson(v.Interface())
+			}
+			e, err := reflectJsonValue(iter.Value())
+			if err != nil {
+				return nil, err
+			}
+			result[iter.Key().String()] = e
+		}
+		return result, nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil, nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 && !reflect.PtrTo(t.Elem()).Implements(typeJsonMarshaler) &&
+			!reflect.PtrTo(t.Elem()).Implements(typeTextMarshaler) {
+			// []byte is encoded as base64 string
+			return base64.StdEncoding.EncodeToString(v.Bytes()), nil
+		}
+		fallthrough
+	case reflect.Array:
+		result := make([]interface{}, v.Len())
+		for i := range result {
+			var err error
+			if result[i], err = reflectJsonValue(v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	case reflect.Struct:
+		codec := structCodecOf(t)
+		if codec.viaJson {
+			return viaJson(v.Interface())
+		}
+		result := make(map[string]interface{}, len(codec.fields))
+		for _, f := range codec.fields {
+			fv := v.Field(f.index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			e, err := reflectJsonValue(fv)
+			if err != nil {
+				return nil, err
+			}
+			result[f.name] = e
+		}
+		return result, nil
+	}
+	// chan, func, complex, etc: let encoding/json report the error
+	return viaJson(v.Interface())
+}
+
+// structField is a struct field as seen by encoding/json.
+type structField struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+// structCodec caches how a struct type is encoded to JSON.
+type structCodec struct {
+	fields  []structField
+	viaJson bool // true if the struct must be converted via JSON marshalling
+}
+
+var structCodecs sync.Map // reflect.Type -> *structCodec
+
+// structCodecOf returns the (cached) codec of a struct type.
+func structCodecOf(t reflect.Type) *structCodec {
+	if codec, ok := structCodecs.Load(t); ok {
+		return codec.(*structCodec)
+	}
+	codec := &structCodec{}
+	names := make(map[string]bool)
+	for i, n := 0, t.NumField(); i < n && !codec.viaJson; i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous {
+			// embedded fields follow complex visibility rules
+			codec.viaJson = true
+			break
+		}
+		if sf.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		tokens := strings.Split(tag, ",")
+		f := structField{index: i, name: tokens[0]}
+		if f.name == "" {
+			f.name = sf.Name
+		}
+		for _, opt := range tokens[1:] {
+			switch opt {
+			case "omitempty":
+				f.omitEmpty = true
+			case "string":
+				codec.viaJson = true
+			}
+		}
+		if names[f.name] {
+			// duplicated names are resolved (dropped) by encoding/json
+			codec.viaJson = true
+		}
+		names[f.name] = true
+		codec.fields = append(codec.fields, f)
+	}
+	structCodecs.Store(t, codec)
+	return codec
+}
+
+// isEmptyValue reports if v is empty as defined by the "omitempty" option of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
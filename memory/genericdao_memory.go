@@ -0,0 +1,1572 @@
+/*
+Package memory provides a generic in-memory implementation of godal.IGenericDao.
+
+GenericDaoMemory keeps all data in memory, hence it is not meant for production use.
+It is intended to be used as a drop-in test double for the other generic DAO implementations, or for local development.
+
+General guideline:
+
+	- Dao must implement IGenericDao.GdaoCreateFilter(string, IGenericBo) interface{}.
+
+Guideline: Use GenericDaoMemory (and godal.IGenericBo) directly
+
+	- Define a dao struct that implements IGenericDao.GdaoCreateFilter(string, IGenericBo) interface{}.
+	- Optionally, create a helper function to create dao instances.
+
+	import (
+		"github.com/btnguyen2k/consu/reddo"
+		"github.com/btnguyen2k/godal"
+		"github.com/btnguyen2k/godal/memory"
+	)
+
+	type myGenericDaoMemory struct {
+		*memory.GenericDaoMemory
+	}
+
+	// GdaoCreateFilter implements godal.IGenericDao.GdaoCreateFilter.
+	func (dao *myGenericDaoMemory) GdaoCreateFilter(storageId string, bo godal.IGenericBo) interface{} {
+		id := bo.GboGetAttrUnsafe(fieldId, reddo.TypeString)
+		return map[string]interface{}{fieldId: id}
+	}
+
+	// newGenericDaoMemory is convenient method to create myGenericDaoMemory instances.
+	func newGenericDaoMemory() godal.IGenericDao {
+		dao := &myGenericDaoMemory{}
+		dao.GenericDaoMemory = memory.NewGenericDaoMemory(godal.NewAbstractGenericDao(dao))
+		dao.AddUniqueIndex("users", "email")
+		return dao
+	}
+
+	Storage ids are names of in-memory collections; collections are created on demand.
+	GenericRowMapperMemory (the default row-mapper) deep-copies data between BOs and the in-memory store.
+
+Guideline: Use GenericDaoMemory as a test double
+
+	GenericDaoMemory honors the same contract as GenericDaoSql, GenericDaoMongo and GenericDaoDynamodb:
+
+	- GdaoCreate returns (0, godal.GdaoErrorDuplicatedEntry) if a record matching GdaoCreateFilter already exists, or if a unique index is violated.
+	- GdaoUpdate returns (0, nil) if the record does not exist, and (0, godal.GdaoErrorDuplicatedEntry) if a unique index is violated.
+	- GdaoSave returns (0, godal.GdaoErrorDuplicatedEntry) if a unique index is violated.
+	- GdaoFetchMany supports map filters, sorting maps and startOffset/numItems paging.
+
+See more examples in 'examples' directory on project's GitHub: https://github.com/btnguyen2k/godal/tree/master/examples
+*/
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/consu/semita"
+	"github.com/btnguyen2k/godal"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+GenericRowMapperMemory is a generic implementation of godal.IRowMapper for the in-memory store.
+
+Implementation rules:
+
+	- ToRow: transform godal.IGenericBo to map[string]interface{} via godal.IGenericBo.GboToMap (the result is a deep copy of BO's data).
+	- ToBo: expects input is a map[string]interface{}, or JSON data (string or array/slice of bytes), transforms input to godal.IGenericBo (the result is a deep copy of input).
+	- ColumnsList: return []string{"*"} (the in-memory store is schema-free, hence column-list is not used).
+*/
+type GenericRowMapperMemory struct {
+}
+
+/*
+ToRow implements godal.IRowMapper.ToRow.
+This function transforms godal.IGenericBo to map[string]interface{}. Field names are kept intact.
+*/
+func (mapper *GenericRowMapperMemory) ToRow(storageId string, bo godal.IGenericBo) (interface{}, error) {
+	if bo == nil {
+		return nil, nil
+	}
+	return bo.GboToMap()
+}
+
+/*
+ToBo implements godal.IRowMapper.ToBo.
+This function expects input to be a map[string]interface{}, or JSON data (string or array/slice of bytes), transforms it to godal.IGenericBo via godal.IGenericBo.GboFromMap or JSON transformation. Field names are kept intact.
+*/
+func (mapper *GenericRowMapperMemory) ToBo(storageId string, row interface{}) (godal.IGenericBo, error) {
+	if row == nil {
+		return nil, nil
+	}
+	switch row.(type) {
+	case map[string]interface{}:
+		if row.(map[string]interface{}) == nil {
+			return nil, nil
+		}
+		bo := godal.NewGenericBo()
+		return bo, bo.GboFromMap(row.(map[string]interface{}))
+	case string:
+		bo := godal.NewGenericBo()
+		return bo, bo.GboFromJson([]byte(row.(string)))
+	case []byte:
+		if row.([]byte) == nil {
+			return nil, nil
+		}
+		bo := godal.NewGenericBo()
+		return bo, bo.GboFromJson(row.([]byte))
+	}
+
+	v := reflect.ValueOf(row)
+	for ; v.Kind() == reflect.Ptr; v = v.Elem() {
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		bo := godal.NewGenericBo()
+		return bo, bo.GboImportViaJson(v.Interface())
+	case reflect.Invalid:
+		return nil, nil
+	}
+	return nil, errors.New(fmt.Sprintf("cannot construct godal.IGenericBo from input %v", row))
+}
+
+/*
+ColumnsList implements godal.IRowMapper.ColumnsList.
+This function returns []string{"*"} since the in-memory store is schema-free (hence column-list is not used).
+*/
+func (mapper *GenericRowMapperMemory) ColumnsList(storageId string) []string {
+	return []string{"*"}
+}
+
+var (
+	// GenericRowMapperMemoryInstance is a pre-created instance of GenericRowMapperMemory that is ready to use.
+	GenericRowMapperMemoryInstance godal.IRowMapper = &GenericRowMapperMemory{}
+)
+
+/*--------------------------------------------------------------------------------*/
+
+/*
+NewGenericDaoMemory constructs a new in-memory implementation of 'godal.IGenericDao'.
+*/
+func NewGenericDaoMemory(agdao *godal.AbstractGenericDao) *GenericDaoMemory {
+	dao := &GenericDaoMemory{
+		AbstractGenericDao: agdao,
+		storages:           make(map[string][]map[string]interface{}),
+		versions:           make(map[string]int64),
+		uniqueIndexes:      make(map[string][][]string),
+	}
+	if dao.GetRowMapper() == nil {
+		dao.SetRowMapper(GenericRowMapperMemoryInstance)
+	}
+	return dao
+}
+
+/*
+GenericDaoMemory is in-memory implementation of godal.IGenericDao.
+
+Function implementations (n = No, y = Yes, i = inherited):
+
+	(n) GdaoCreateFilter(storageId string, bo godal.IGenericBo) interface{}
+	(y) GdaoDelete(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoDeleteMany(storageId string, filter interface{}) (int, error)
+	(y) GdaoFetchOne(storageId string, filter interface{}) (godal.IGenericBo, error)
+	(y) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error)
+	(y) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error)
+	(y) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error)
+	(y) GdaoCount(storageId string, filter interface{}) (int64, error)
+	(y) GdaoExists(storageId string, filter interface{}) (bool, error)
+	(y) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
+	(y) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error)
+	(y) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoSave(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
+
+GenericDaoMemory also implements godal.IGenericDaoWithContext and godal.ITransactionalDao. Since data is in memory, operations are not interruptible:
+the context is checked before each operation starts, and the operation fails with ctx.Err() if the context is already done (an exceeded
+deadline is reported as godal.ErrTimeout, since v0.3.0).
+
+GenericDaoMemory is safe for concurrent use.
+*/
+type GenericDaoMemory struct {
+	*godal.AbstractGenericDao
+	lock          sync.RWMutex
+	storages      map[string][]map[string]interface{}
+	versions      map[string]int64 // incremented each time a storage is written, to detect conflicting transactions
+	uniqueIndexes map[string][][]string
+}
+
+/*
+AddUniqueIndex declares a unique index on a storage: no two records of the storage can have the same values for all 'fields'.
+
+Write operations violating a unique index return godal.GdaoErrorDuplicatedEntry.
+Field names are paths, the same syntax that IGenericBo.GboGetAttr uses.
+Records having any of the fields nil or missing are not checked against the index (similar to NULLs in SQL unique indexes).
+
+On-going transactions having written to the storage fail to commit (see GdaoWithTransaction).
+*/
+func (dao *GenericDaoMemory) AddUniqueIndex(storageId string, fields ...string) *GenericDaoMemory {
+	if len(fields) > 0 {
+		dao.lock.Lock()
+		defer dao.lock.Unlock()
+		index := make([]string, len(fields))
+		copy(index, fields)
+		dao.uniqueIndexes[storageId] = append(dao.uniqueIndexes[storageId], index)
+		dao.versions[storageId]++
+	}
+	return dao
+}
+
+/*
+Truncate removes all records from a storage.
+
+Truncate does not join transactions: on-going transactions having written to the storage fail to commit (see GdaoWithTransaction).
+*/
+func (dao *GenericDaoMemory) Truncate(storageId string) *GenericDaoMemory {
+	dao.lock.Lock()
+	defer dao.lock.Unlock()
+	delete(dao.storages, storageId)
+	dao.versions[storageId]++
+	return dao
+}
+
+/*----------------------------------------------------------------------*/
+
+func toMap(input interface{}) (map[string]interface{}, error) {
+	if input == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		// expect input to be a map in JSON
+		result := make(map[string]interface{})
+		err := json.Unmarshal([]byte(v.Interface().(string)), &result)
+		return result, err
+	case reflect.Array, reflect.Slice:
+		// expect input to be a map in JSON
+		t, err := reddo.ToSlice(v.Interface(), reflect.TypeOf(byte(0)))
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]interface{})
+		err = json.Unmarshal(t.([]byte), &result)
+		return result, err
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		result, err := reddo.ToMap(v.Interface(), reflect.TypeOf(map[string]interface{}{}))
+		if err != nil {
+			return nil, err
+		}
+		return result.(map[string]interface{}), nil
+	}
+	return nil, errors.New(fmt.Sprintf("cannot convert %v to map[string]interface{}", input))
+}
+
+func toSortingMap(input interface{}) (map[string]int, error) {
+	if input == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(input)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		// expect input to be a map in JSON
+		result := make(map[string]int)
+		err := json.Unmarshal([]byte(v.Interface().(string)), &result)
+		return result, err
+	case reflect.Array, reflect.Slice:
+		// expect input to be a map in JSON
+		t, err := reddo.ToSlice(v.Interface(), reflect.TypeOf(byte(0)))
+		if err != nil {
+			return nil, err
+		}
+		result := make(map[string]int)
+		err = json.Unmarshal(t.([]byte), &result)
+		return result, err
+	case reflect.Map:
+		if v.IsNil() {
+			return nil, nil
+		}
+		result, err := reddo.ToMap(v.Interface(), reflect.TypeOf(map[string]int{}))
+		if err != nil {
+			return nil, err
+		}
+		return result.(map[string]int), nil
+	}
+	return nil, errors.New(fmt.Sprintf("cannot convert %v to map[string]int", input))
+}
+
+// normalize converts a value to its JSON-equivalent form so that values of different Go types (e.g. int vs float64) can be compared.
+func normalize(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, bool, string, float64, map[string]interface{}, []interface{}:
+		return v
+	}
+	if js, err := json.Marshal(v); err == nil {
+		var result interface{}
+		if json.Unmarshal(js, &result) == nil {
+			return result
+		}
+	}
+	return v
+}
+
+// typeRank orders values of different types: nil < bool < number < string < others.
+func typeRank(v interface{}) int {
+	switch v.(type) {
+	case nil:
+		return 0
+	case bool:
+		return 1
+	case float64:
+		return 2
+	case string:
+		return 3
+	}
+	return 4
+}
+
+// compareValues compares two values, returns -1 if a<b, 0 if a==b and 1 if a>b.
+func compareValues(a, b interface{}) int {
+	a, b = normalize(a), normalize(b)
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	switch ra {
+	case 0:
+		return 0
+	case 1:
+		if a.(bool) == b.(bool) {
+			return 0
+		} else if !a.(bool) {
+			return -1
+		}
+		return 1
+	case 2:
+		if a.(float64) < b.(float64) {
+			return -1
+		} else if a.(float64) > b.(float64) {
+			return 1
+		}
+		return 0
+	case 3:
+		return strings.Compare(a.(string), b.(string))
+	}
+	if reflect.DeepEqual(a, b) {
+		return 0
+	}
+	jsA, _ := json.Marshal(a)
+	jsB, _ := json.Marshal(b)
+	return strings.Compare(string(jsA), string(jsB))
+}
+
+type ctxKeyTx struct{}
+
+// memoryTx is a transaction started by GdaoWithTransaction, carried by the context passed to txFunc.
+// The transaction works on private copies of the storages it accesses, installed into the dao when it commits.
+type memoryTx struct {
+	dao      *GenericDaoMemory
+	lock     sync.Mutex // operations made with the transaction's context are serialized by this one
+	ended    bool
+	storages map[string][]map[string]interface{} // copies of the storages accessed by the transaction
+	versions map[string]int64                    // versions of the storages when they were copied
+	written  map[string]bool                     // storages written by the transaction
+}
+
+// end marks the transaction as ended, once on-going operations made with the transaction's context complete.
+func (tx *memoryTx) end() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.ended = true
+}
+
+// rows returns the transaction's copy of a storage, copying it if needed. Caller must hold tx.lock and the dao's read lock.
+func (tx *memoryTx) rows(storageId string) []map[string]interface{} {
+	if _, ok := tx.storages[storageId]; !ok {
+		rows := tx.dao.storages[storageId]
+		// rows are never modified in place, copying the slice is enough
+		tx.storages[storageId] = append(make([]map[string]interface{}, 0, len(rows)), rows...)
+		tx.versions[storageId] = tx.dao.versions[storageId]
+	}
+	return tx.storages[storageId]
+}
+
+// commit installs the storages written by the transaction into the dao. It returns a godal.ErrConcurrentModification error, installing
+// nothing, if any of them has been written outside of the transaction since the transaction copied it.
+func (tx *memoryTx) commit() error {
+	dao := tx.dao
+	dao.lock.Lock()
+	defer dao.lock.Unlock()
+	for storageId := range tx.written {
+		if dao.versions[storageId] != tx.versions[storageId] {
+			return godal.WrapError(godal.ErrConcurrentModification, fmt.Errorf("storage %s was modified outside of the transaction", storageId))
+		}
+	}
+	for storageId := range tx.written {
+		dao.storages[storageId] = tx.storages[storageId]
+		dao.versions[storageId]++
+	}
+	return nil
+}
+
+// txFromContext returns the transaction of this dao carried by ctx, nil if ctx is not carrying any.
+func (dao *GenericDaoMemory) txFromContext(ctx context.Context) *memoryTx {
+	if ctx == nil {
+		return nil
+	}
+	if tx, ok := ctx.Value(ctxKeyTx{}).(*memoryTx); ok && tx.dao == dao {
+		return tx
+	}
+	return nil
+}
+
+// wlock acquires the write lock and returns the storages to write to and the function to release the lock. If ctx is carrying a
+// transaction of the dao, operations made with the transaction's context are serialized and work on the transaction's copy of the
+// storage; godal.ErrTxEnded is returned if the transaction has ended.
+func (dao *GenericDaoMemory) wlock(ctx context.Context, storageId string) (map[string][]map[string]interface{}, func(), error) {
+	if tx := dao.txFromContext(ctx); tx != nil {
+		storages, unlock, err := dao.txLock(tx, storageId)
+		if err == nil {
+			tx.written[storageId] = true
+		}
+		return storages, unlock, err
+	}
+	dao.lock.Lock()
+	return dao.storages, func() {
+		dao.versions[storageId]++
+		dao.lock.Unlock()
+	}, nil
+}
+
+// rlock acquires the read lock and returns the storages to read from and the function to release the lock, see wlock.
+func (dao *GenericDaoMemory) rlock(ctx context.Context, storageId string) (map[string][]map[string]interface{}, func(), error) {
+	if tx := dao.txFromContext(ctx); tx != nil {
+		return dao.txLock(tx, storageId)
+	}
+	dao.lock.RLock()
+	return dao.storages, dao.lock.RUnlock, nil
+}
+
+// txLock acquires the transaction's lock, then the dao's read lock (the transaction's copy of the storage is made from the dao's one).
+func (dao *GenericDaoMemory) txLock(tx *memoryTx, storageId string) (map[string][]map[string]interface{}, func(), error) {
+	tx.lock.Lock()
+	if tx.ended {
+		tx.lock.Unlock()
+		return nil, nil, godal.ErrTxEnded
+	}
+	dao.lock.RLock()
+	tx.rows(storageId)
+	return tx.storages, func() {
+		dao.lock.RUnlock()
+		tx.lock.Unlock()
+	}, nil
+}
+
+// startOp reports the start of a Gdao* call to the DAO's instrumentation, see godal.AbstractGenericDao.StartOp.
+func (dao *GenericDaoMemory) startOp(ctx context.Context, operation, storageId string) (context.Context, *godal.DaoOperation) {
+	return dao.StartOp(ctx, &godal.DaoOperation{Backend: "memory", Operation: operation, StorageId: storageId})
+}
+
+// checkContext returns ctx's error if ctx is already cancelled or its deadline exceeded (nil ctx is ok). Exceeded deadline is reported
+// as godal.ErrTimeout.
+func checkContext(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	if err := ctx.Err(); err == context.DeadlineExceeded {
+		return godal.WrapError(godal.ErrTimeout, err)
+	} else {
+		return err
+	}
+}
+
+func getValue(row map[string]interface{}, path string) interface{} {
+	v, err := semita.NewSemita(row).GetValue(path)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+// toFilterOpt converts the input to a godal.FilterOpt; a map filter is converted to an "and" of "equal" conditions.
+func toFilterOpt(input interface{}) (*godal.FilterOpt, error) {
+	switch f := input.(type) {
+	case *godal.FilterOpt:
+		if f == nil {
+			return nil, nil
+		}
+		return f, validateFilterOpt(f)
+	case godal.FilterOpt:
+		return &f, validateFilterOpt(&f)
+	}
+	m, err := toMap(input)
+	if err != nil || len(m) == 0 {
+		return nil, err
+	}
+	// map has no stable key order, fields are sorted by name to keep the result deterministic
+	fields := make([]string, 0, len(m))
+	for k := range m {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	result := godal.FilterAnd()
+	for _, field := range fields {
+		result.Filters = append(result.Filters, godal.FilterEq(field, m[field]))
+	}
+	return result, nil
+}
+
+func validateFilterOpt(f *godal.FilterOpt) error {
+	if f == nil {
+		return errors.New("nil filter")
+	}
+	switch f.Operator {
+	case godal.FilterOpEq, godal.FilterOpNe, godal.FilterOpGt, godal.FilterOpGte, godal.FilterOpLt, godal.FilterOpLte,
+		godal.FilterOpIsNull, godal.FilterOpExists:
+		return nil
+	case godal.FilterOpPrefix:
+		if _, ok := f.Value.(string); !ok {
+			return errors.New(fmt.Sprintf("filter PREFIX requires a string value, got %v", f.Value))
+		}
+		return nil
+	case godal.FilterOpIn:
+		if len(f.Values) == 0 {
+			return errors.New("filter IN requires at least one value")
+		}
+		return nil
+	case godal.FilterOpBetween:
+		if len(f.Values) != 2 {
+			return errors.New("filter BETWEEN requires exactly two values")
+		}
+		return nil
+	case godal.FilterOpAnd, godal.FilterOpOr, godal.FilterOpNot:
+		if len(f.Filters) == 0 {
+			return errors.New("filter AND/OR/NOT requires at least one sub-filter")
+		}
+		if f.Operator == godal.FilterOpNot && len(f.Filters) != 1 {
+			return errors.New("filter NOT requires exactly one sub-filter")
+		}
+		for _, sub := range f.Filters {
+			if err := validateFilterOpt(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return errors.New(fmt.Sprintf("unsupported filter operator %d", f.Operator))
+}
+
+// compareNonNull compares two values of the same type, returns false if either is nil or they are of different types.
+func compareNonNull(a, b interface{}, test func(int) bool) bool {
+	a, b = normalize(a), normalize(b)
+	if a == nil || b == nil || typeRank(a) != typeRank(b) {
+		return false
+	}
+	return test(compareValues(a, b))
+}
+
+// matchFilter checks if a row matches a filter (nil filter matches all).
+// Comparisons never match null/absent fields, the same way SQL databases and DynamoDB behave.
+func (dao *GenericDaoMemory) matchFilter(row map[string]interface{}, filter *godal.FilterOpt) bool {
+	if filter == nil {
+		return true
+	}
+	switch filter.Operator {
+	case godal.FilterOpEq:
+		return compareNonNull(getValue(row, filter.Field), filter.Value, func(c int) bool { return c == 0 })
+	case godal.FilterOpNe:
+		v := normalize(getValue(row, filter.Field))
+		return v != nil && filter.Value != nil && compareValues(v, filter.Value) != 0
+	case godal.FilterOpGt:
+		return compareNonNull(getValue(row, filter.Field), filter.Value, func(c int) bool { return c > 0 })
+	case godal.FilterOpGte:
+		return compareNonNull(getValue(row, filter.Field), filter.Value, func(c int) bool { return c >= 0 })
+	case godal.FilterOpLt:
+		return compareNonNull(getValue(row, filter.Field), filter.Value, func(c int) bool { return c < 0 })
+	case godal.FilterOpLte:
+		return compareNonNull(getValue(row, filter.Field), filter.Value, func(c int) bool { return c <= 0 })
+	case godal.FilterOpIn:
+		v := getValue(row, filter.Field)
+		for _, value := range filter.Values {
+			if compareNonNull(v, value, func(c int) bool { return c == 0 }) {
+				return true
+			}
+		}
+		return false
+	case godal.FilterOpBetween:
+		v := getValue(row, filter.Field)
+		return compareNonNull(v, filter.Values[0], func(c int) bool { return c >= 0 }) &&
+			compareNonNull(v, filter.Values[1], func(c int) bool { return c <= 0 })
+	case godal.FilterOpPrefix:
+		v, ok := getValue(row, filter.Field).(string)
+		return ok && strings.HasPrefix(v, filter.Value.(string))
+	case godal.FilterOpIsNull:
+		return getValue(row, filter.Field) == nil
+	case godal.FilterOpExists:
+		return getValue(row, filter.Field) != nil
+	case godal.FilterOpAnd:
+		for _, sub := range filter.Filters {
+			if !dao.matchFilter(row, sub) {
+				return false
+			}
+		}
+		return true
+	case godal.FilterOpOr:
+		for _, sub := range filter.Filters {
+			if dao.matchFilter(row, sub) {
+				return true
+			}
+		}
+		return false
+	case godal.FilterOpNot:
+		return !dao.matchFilter(row, filter.Filters[0])
+	}
+	return false
+}
+
+// findIndex returns index of the first row matching the filter, -1 if not found. Caller must hold the lock.
+func (dao *GenericDaoMemory) findIndex(rows []map[string]interface{}, filter *godal.FilterOpt) int {
+	for i, row := range rows {
+		if dao.matchFilter(row, filter) {
+			return i
+		}
+	}
+	return -1
+}
+
+// violateUniqueIndexes checks if a row violates any unique index, ignoring the row at position 'ignoreIndex'. Caller must hold the lock.
+// Indexes having a field that is nil/missing in the row are not checked (as nulls are distinct in SQL unique indexes).
+func (dao *GenericDaoMemory) violateUniqueIndexes(storageId string, rows []map[string]interface{}, row map[string]interface{}, ignoreIndex int) bool {
+	for _, index := range dao.uniqueIndexes[storageId] {
+		filter := godal.FilterAnd()
+		for _, field := range index {
+			value := getValue(row, field)
+			if value == nil {
+				filter = nil
+				break
+			}
+			filter.Filters = append(filter.Filters, godal.FilterEq(field, value))
+		}
+		if filter == nil {
+			continue
+		}
+		for i, r := range rows {
+			if i != ignoreIndex && dao.matchFilter(r, filter) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (dao *GenericDaoMemory) toRow(storageId string, bo godal.IGenericBo) (map[string]interface{}, error) {
+	row, err := dao.GetRowMapper().ToRow(storageId, bo)
+	if err != nil {
+		return nil, err
+	}
+	return toMap(row)
+}
+
+// toSortingOpt converts the input to a godal.SortingOpt; fields of a map sorting are sorted by name.
+func toSortingOpt(input interface{}) (*godal.SortingOpt, error) {
+	switch o := input.(type) {
+	case *godal.SortingOpt:
+		return o, nil
+	case godal.SortingOpt:
+		return &o, nil
+	}
+	m, err := toSortingMap(input)
+	if err != nil || len(m) == 0 {
+		return nil, err
+	}
+	// map has no stable key order, fields are sorted by name to keep the result deterministic
+	fields := make([]string, 0, len(m))
+	for k := range m {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	result := godal.NewSortingOpt()
+	for _, field := range fields {
+		result.Add(field, godal.SortingOrder(m[field]))
+	}
+	return result, nil
+}
+
+func (dao *GenericDaoMemory) sortRows(rows []map[string]interface{}, sorting *godal.SortingOpt) {
+	if sorting == nil || len(sorting.Fields) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, field := range sorting.Fields {
+			if c := compareValues(getValue(rows[i], field.Field), getValue(rows[j], field.Field)); c != 0 {
+				if field.Descending() {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+GdaoDelete implements godal.IGenericDao.GdaoDelete.
+*/
+func (dao *GenericDaoMemory) GdaoDelete(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoDeleteCtx(nil, storageId, bo)
+}
+
+/*
+GdaoDeleteCtx implements godal.IGenericDaoWithContext.GdaoDeleteCtx.
+*/
+func (dao *GenericDaoMemory) GdaoDeleteCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDelete", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeDelete, storageId, bo); err != nil {
+		return 0, err
+	}
+	filter := dao.GdaoCreateFilter(storageId, bo)
+	numRows, err = dao.GdaoDeleteManyCtx(ctx, storageId, filter)
+	return dao.AfterWrite(ctx, godal.HookAfterDelete, storageId, bo, numRows, err)
+}
+
+/*
+GdaoDeleteMany implements godal.IGenericDao.GdaoDeleteMany.
+*/
+func (dao *GenericDaoMemory) GdaoDeleteMany(storageId string, filter interface{}) (int, error) {
+	return dao.GdaoDeleteManyCtx(nil, storageId, filter)
+}
+
+/*
+GdaoDeleteManyCtx implements godal.IGenericDaoWithContext.GdaoDeleteManyCtx.
+
+	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- map filter's keys are paths (the same syntax that IGenericBo.GboGetAttr uses), entries are combined using "and" operation and each entry is an "equal" condition
+	- filter can also be a godal.FilterOpt, whose field names are paths
+	- nil filter means "match all"
+*/
+func (dao *GenericDaoMemory) GdaoDeleteManyCtx(ctx context.Context, storageId string, filter interface{}) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDeleteMany", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	f, err := toFilterOpt(filter)
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	rows := storages[storageId]
+	remaining := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if !dao.matchFilter(row, f) {
+			remaining = append(remaining, row)
+		}
+	}
+	storages[storageId] = remaining
+	return len(rows) - len(remaining), nil
+}
+
+/*
+GdaoFetchOne implements godal.IGenericDao.GdaoFetchOne.
+*/
+func (dao *GenericDaoMemory) GdaoFetchOne(storageId string, filter interface{}) (godal.IGenericBo, error) {
+	return dao.GdaoFetchOneCtx(nil, storageId, filter)
+}
+
+/*
+GdaoFetchOneCtx implements godal.IGenericDaoWithContext.GdaoFetchOneCtx.
+
+	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- map filter's keys are paths (the same syntax that IGenericBo.GboGetAttr uses), entries are combined using "and" operation and each entry is an "equal" condition
+	- filter can also be a godal.FilterOpt, whose field names are paths
+*/
+func (dao *GenericDaoMemory) GdaoFetchOneCtx(ctx context.Context, storageId string, filter interface{}) (bo godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchOne", storageId)
+	defer func() {
+		if bo != nil {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	f, err := toFilterOpt(filter)
+	if err != nil {
+		return nil, err
+	}
+	storages, unlock, err := dao.rlock(ctx, storageId)
+	if err != nil {
+		return nil, err
+	}
+	var row map[string]interface{}
+	if i := dao.findIndex(storages[storageId], f); i >= 0 {
+		row = storages[storageId][i]
+	}
+	unlock()
+	if row == nil {
+		return nil, nil
+	}
+	// hooks are invoked outside of the lock, so that they can call the DAO
+	return dao.RowToBo(ctx, storageId, row)
+}
+
+/*
+GdaoFetchMany implements godal.IGenericDao.GdaoFetchMany.
+*/
+func (dao *GenericDaoMemory) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
+	return dao.GdaoFetchManyCtx(nil, storageId, filter, sorting, startOffset, numItems)
+}
+
+// fetchRows returns rows matching the filter, sorted.
+func (dao *GenericDaoMemory) fetchRows(ctx context.Context, storageId string, filter interface{}, sorting interface{}) ([]map[string]interface{}, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	f, err := toFilterOpt(filter)
+	if err != nil {
+		return nil, err
+	}
+	s, err := toSortingOpt(sorting)
+	if err != nil {
+		return nil, err
+	}
+	storages, unlock, err := dao.rlock(ctx, storageId)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]interface{}, 0)
+	for _, row := range storages[storageId] {
+		if dao.matchFilter(row, f) {
+			rows = append(rows, row)
+		}
+	}
+	unlock()
+	dao.sortRows(rows, s)
+	return rows, nil
+}
+
+/*
+GdaoFetchManyCtx implements godal.IGenericDaoWithContext.GdaoFetchManyCtx.
+
+	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- map filter's keys are paths (the same syntax that IGenericBo.GboGetAttr uses), entries are combined using "and" operation and each entry is an "equal" condition
+	- filter can also be a godal.FilterOpt, whose field names are paths
+	- nil filter means "match all"
+	- sorting should be a map[string]int, or it can be a string/[]byte representing map[string]int in JSON, then it is unmarshalled to map[string]int
+	- sorting map's keys are paths, values are ordering specification (1 for ASC, -1 for DESC). Sorting fields are applied in alphabetical order of their names.
+	- sorting can also be a godal.SortingOpt, whose fields are paths and are applied in order
+*/
+func (dao *GenericDaoMemory) GdaoFetchManyCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) (boList []godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchMany", storageId)
+	defer func() { op.Finish(len(boList), err) }()
+	rows, err := dao.fetchRows(ctx, storageId, filter, sorting)
+	if err != nil {
+		return nil, err
+	}
+	if startOffset < 0 {
+		startOffset = 0
+	}
+	if startOffset > len(rows) {
+		startOffset = len(rows)
+	}
+	rows = rows[startOffset:]
+	if numItems > 0 && numItems < len(rows) {
+		rows = rows[:numItems]
+	}
+	result := make([]godal.IGenericBo, 0, len(rows))
+	for _, row := range rows {
+		bo, err := dao.RowToBo(ctx, storageId, row)
+		if err != nil {
+			return result, err
+		}
+		result = append(result, bo)
+	}
+	return result, nil
+}
+
+/*
+GdaoFetchIterator implements godal.IGenericDao.GdaoFetchIterator.
+*/
+func (dao *GenericDaoMemory) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorCtx(nil, storageId, filter, sorting)
+}
+
+/*
+GdaoFetchIteratorCtx implements godal.IGenericDaoWithContext.GdaoFetchIteratorCtx.
+
+	- filter and sorting: see GdaoFetchManyCtx.
+	- matching rows are selected (and sorted) when this function is called, later changes to the storage are not visible to the iterator;
+	  rows are transformed to BOs lazily while iterating.
+	- iteration stops with ctx's error if ctx is cancelled.
+*/
+func (dao *GenericDaoMemory) GdaoFetchIteratorCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}) (it godal.IGenericBoIterator, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchIterator", storageId)
+	defer func() { op.Finish(0, err) }()
+	rows, err := dao.fetchRows(ctx, storageId, filter, sorting)
+	if err != nil {
+		return nil, err
+	}
+	return &memoryBoIterator{ctx: ctx, dao: dao, storageId: storageId, rows: rows}, nil
+}
+
+// memoryBoIterator implements godal.IGenericBoIterator.
+type memoryBoIterator struct {
+	ctx       context.Context
+	dao       *GenericDaoMemory
+	storageId string
+	rows      []map[string]interface{}
+	bo        godal.IGenericBo
+	err       error
+}
+
+// Next implements godal.IGenericBoIterator.Next.
+func (it *memoryBoIterator) Next() bool {
+	it.bo = nil
+	if it.err != nil || len(it.rows) == 0 {
+		return false
+	}
+	if it.err = checkContext(it.ctx); it.err != nil {
+		return false
+	}
+	row := it.rows[0]
+	it.rows = it.rows[1:]
+	it.bo, it.err = it.dao.RowToBo(it.ctx, it.storageId, row)
+	return it.err == nil
+}
+
+// Bo implements godal.IGenericBoIterator.Bo.
+func (it *memoryBoIterator) Bo() godal.IGenericBo {
+	return it.bo
+}
+
+// Err implements godal.IGenericBoIterator.Err.
+func (it *memoryBoIterator) Err() error {
+	return it.err
+}
+
+// Close implements godal.IGenericBoIterator.Close.
+func (it *memoryBoIterator) Close() error {
+	it.rows = nil
+	return nil
+}
+
+/*
+GdaoFetchPage implements godal.IGenericDao.GdaoFetchPage.
+*/
+func (dao *GenericDaoMemory) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageCtx(nil, storageId, filter, sorting, pageToken, numItems)
+}
+
+/*
+GdaoFetchPageCtx implements godal.IGenericDaoWithContext.GdaoFetchPageCtx.
+
+	- filter: see GdaoFetchManyCtx.
+	- keyset pagination is used (see godal.KeysetPaging), the same way other generic DAOs do: sorting must be a godal.SortingOpt whose fields
+	  combined are unique, and the page token carries the values of these fields of the last row of the previous page.
+*/
+func (dao *GenericDaoMemory) GdaoFetchPageCtx(ctx context.Context, storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) (boList []godal.IGenericBo, nextPageToken string, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchPage", storageId)
+	defer func() { op.Finish(len(boList), err) }()
+	paging, err := godal.NewKeysetPaging(sorting, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := toFilterOpt(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if kf := paging.Filter(); kf != nil {
+		if f == nil {
+			f = kf
+		} else {
+			f = godal.FilterAnd(f, kf)
+		}
+	}
+	rows, err := dao.fetchRows(ctx, storageId, f, paging.Sorting)
+	if err != nil {
+		return nil, "", err
+	}
+	if numItems > 0 && numItems < len(rows) {
+		rows = rows[:numItems]
+		lastValues := make(map[string]interface{})
+		for _, field := range paging.Sorting.Fields {
+			lastValues[field.Field] = getValue(rows[numItems-1], field.Field)
+		}
+		if nextPageToken, err = paging.NextPageToken(lastValues); err != nil {
+			return nil, "", err
+		}
+	}
+	result := make([]godal.IGenericBo, 0, len(rows))
+	for _, row := range rows {
+		bo, err := dao.RowToBo(ctx, storageId, row)
+		if err != nil {
+			return nil, "", err
+		}
+		result = append(result, bo)
+	}
+	return result, nextPageToken, nil
+}
+
+/*
+GdaoCount implements godal.IGenericDao.GdaoCount.
+*/
+func (dao *GenericDaoMemory) GdaoCount(storageId string, filter interface{}) (int64, error) {
+	return dao.GdaoCountCtx(nil, storageId, filter)
+}
+
+/*
+GdaoCountCtx implements godal.IGenericDaoWithContext.GdaoCountCtx.
+
+	- filter: see GdaoFetchManyCtx.
+*/
+func (dao *GenericDaoMemory) GdaoCountCtx(ctx context.Context, storageId string, filter interface{}) (count int64, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCount", storageId)
+	defer func() { op.Finish(int(count), err) }()
+	rows, err := dao.fetchRows(ctx, storageId, filter, nil)
+	return int64(len(rows)), err
+}
+
+/*
+GdaoExists implements godal.IGenericDao.GdaoExists.
+*/
+func (dao *GenericDaoMemory) GdaoExists(storageId string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsCtx(nil, storageId, filter)
+}
+
+/*
+GdaoExistsCtx implements godal.IGenericDaoWithContext.GdaoExistsCtx.
+
+	- filter: see GdaoFetchManyCtx.
+*/
+func (dao *GenericDaoMemory) GdaoExistsCtx(ctx context.Context, storageId string, filter interface{}) (exists bool, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoExists", storageId)
+	defer func() {
+		if exists {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	if err := checkContext(ctx); err != nil {
+		return false, err
+	}
+	f, err := toFilterOpt(filter)
+	if err != nil {
+		return false, err
+	}
+	storages, unlock, err := dao.rlock(ctx, storageId)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+	return dao.findIndex(storages[storageId], f) >= 0, nil
+}
+
+/*
+GdaoCreate implements godal.IGenericDao.GdaoCreate.
+*/
+func (dao *GenericDaoMemory) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateCtx(nil, storageId, bo)
+}
+
+/*
+GdaoCreateCtx implements godal.IGenericDaoWithContext.GdaoCreateCtx.
+*/
+func (dao *GenericDaoMemory) GdaoCreateCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreate", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.create(ctx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterCreate, storageId, bo, numRows, err)
+}
+
+// create inserts a new record, see GdaoCreateCtx.
+func (dao *GenericDaoMemory) create(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	filter, err := toFilterOpt(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	row, err := dao.toRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	if dao.findIndex(storages[storageId], filter) >= 0 || dao.violateUniqueIndexes(storageId, storages[storageId], row, -1) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	}
+	storages[storageId] = append(storages[storageId], row)
+	return 1, nil
+}
+
+/*
+GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+*/
+func (dao *GenericDaoMemory) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateCtx(nil, storageId, bo)
+}
+
+/*
+GdaoUpdateCtx implements godal.IGenericDaoWithContext.GdaoUpdateCtx.
+*/
+func (dao *GenericDaoMemory) GdaoUpdateCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdate", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.update(ctx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, storageId, bo, numRows, err)
+}
+
+// update replaces an existing record, see GdaoUpdateCtx.
+func (dao *GenericDaoMemory) update(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	filter, err := toFilterOpt(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	row, err := dao.toRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	i := dao.findIndex(storages[storageId], filter)
+	if i < 0 {
+		return 0, nil
+	}
+	if err := dao.bumpVersion(storageId, storages[storageId], row, i); err != nil {
+		return 0, err
+	}
+	if dao.violateUniqueIndexes(storageId, storages[storageId], row, i) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	}
+	storages[storageId][i] = row
+	return 1, dao.setWrittenVersion(storageId, bo, row)
+}
+
+// bumpVersion increments the version of row if optimistic locking is enabled on the storage (see godal.AbstractGenericDao.SetVersionField).
+// It returns godal.GdaoErrorConcurrentModification if the stored record at index i (if any) has another version.
+func (dao *GenericDaoMemory) bumpVersion(storageId string, rows []map[string]interface{}, row map[string]interface{}, i int) error {
+	field := dao.GetVersionField(storageId)
+	if field == "" {
+		return nil
+	}
+	current, next, err := godal.NextVersion(row, field)
+	if err != nil {
+		return err
+	}
+	if i >= 0 {
+		if stored, err := reddo.ToInt(rows[i][field]); err != nil || stored != current {
+			return godal.GdaoErrorConcurrentModification
+		}
+	}
+	row[field] = float64(next) // records are stored in JSON-equivalent form
+	return nil
+}
+
+// setWrittenVersion sets the version of the stored row (see bumpVersion) to bo, if optimistic locking is enabled on the storage.
+func (dao *GenericDaoMemory) setWrittenVersion(storageId string, bo godal.IGenericBo, row map[string]interface{}) error {
+	field := dao.GetVersionField(storageId)
+	if field == "" {
+		return nil
+	}
+	version, err := reddo.ToInt(row[field])
+	if err != nil {
+		return err
+	}
+	return dao.SetBoVersion(storageId, bo, version)
+}
+
+/*
+GdaoPatch implements godal.IGenericDao.GdaoPatch.
+*/
+func (dao *GenericDaoMemory) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchCtx(nil, storageId, bo, patch)
+}
+
+/*
+GdaoPatchCtx implements godal.IGenericDaoWithContext.GdaoPatchCtx.
+
+Patched fields are top-level fields of the stored record.
+*/
+func (dao *GenericDaoMemory) GdaoPatchCtx(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoPatch", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforePatch(ctx, storageId, bo, patch); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.patch(ctx, storageId, bo, patch)
+	return dao.AfterPatch(ctx, storageId, bo, patch, numRows, err)
+}
+
+// patch patches an existing record, see GdaoPatchCtx.
+func (dao *GenericDaoMemory) patch(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	if patch == nil || len(patch.Fields) == 0 {
+		return 0, errors.New("patch must have at least one field")
+	}
+	filter, err := toFilterOpt(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	i := dao.findIndex(storages[storageId], filter)
+	if i < 0 {
+		return 0, nil
+	}
+	row, err := applyPatch(storages[storageId][i], patch)
+	if err != nil {
+		return 0, err
+	}
+	if dao.violateUniqueIndexes(storageId, storages[storageId], row, i) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	}
+	storages[storageId][i] = row
+	return 1, nil
+}
+
+/*
+GdaoUpdateDirty implements godal.IGenericDao.GdaoUpdateDirty.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMemory) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyCtx(nil, storageId, bo)
+}
+
+/*
+GdaoUpdateDirtyCtx implements godal.IGenericDaoWithContext.GdaoUpdateDirtyCtx.
+
+Dirty attributes (see godal.DirtyAttrs) are top-level fields of the stored record, other fields of the stored record are left untouched.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMemory) GdaoUpdateDirtyCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdateDirty", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if !bo.GboIsDirty() {
+		return 0, nil
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.updateDirty(ctx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, storageId, bo, numRows, err)
+}
+
+// updateDirty updates dirty attributes of an existing record, see GdaoUpdateDirtyCtx.
+func (dao *GenericDaoMemory) updateDirty(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	filter, err := toFilterOpt(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	row, err := dao.toRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	i := dao.findIndex(storages[storageId], filter)
+	if i < 0 {
+		return 0, nil
+	}
+	if err := dao.bumpVersion(storageId, storages[storageId], row, i); err != nil {
+		return 0, err
+	}
+	stored := storages[storageId][i]
+	result := make(map[string]interface{}, len(stored))
+	for k, v := range stored {
+		result[k] = v
+	}
+	for _, attr := range godal.DirtyAttrs(bo) {
+		if v, ok := row[attr]; ok {
+			result[attr] = v
+		}
+	}
+	for _, attr := range bo.GboDeletedAttrs() {
+		delete(result, attr)
+	}
+	if field := dao.GetVersionField(storageId); field != "" {
+		result[field] = row[field]
+	}
+	if dao.violateUniqueIndexes(storageId, storages[storageId], result, i) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	}
+	storages[storageId][i] = result
+	return 1, dao.setWrittenVersion(storageId, bo, result)
+}
+
+// toJsonValue converts a value to its JSON-equivalent form, the form records are stored in.
+func toJsonValue(v interface{}) (interface{}, error) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var result interface{}
+	return result, json.Unmarshal(js, &result)
+}
+
+// applyPatch returns a patched copy of row, row itself is not modified.
+func applyPatch(row map[string]interface{}, patch *godal.PatchOpt) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(row))
+	for k, v := range row {
+		result[k] = v
+	}
+	for _, f := range patch.Fields {
+		switch f.Operator {
+		case godal.PatchOpSet, godal.PatchOpAppend:
+			v, err := toJsonValue(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			if f.Operator == godal.PatchOpSet {
+				result[f.Field] = v
+				break
+			}
+			list, ok := result[f.Field].([]interface{})
+			if !ok && result[f.Field] != nil {
+				return nil, errors.New(fmt.Sprintf("cannot append to field [%s]: not a list", f.Field))
+			}
+			result[f.Field] = append(append(make([]interface{}, 0, len(list)+1), list...), v)
+		case godal.PatchOpUnset:
+			delete(result, f.Field)
+		case godal.PatchOpIncrement:
+			delta, ok := normalize(f.Value).(float64)
+			if !ok {
+				return nil, errors.New(fmt.Sprintf("cannot increment field [%s]: delta %v is not a number", f.Field, f.Value))
+			}
+			current, ok := result[f.Field].(float64)
+			if !ok && result[f.Field] != nil {
+				return nil, errors.New(fmt.Sprintf("cannot increment field [%s]: not a number", f.Field))
+			}
+			result[f.Field] = current + delta
+		default:
+			return nil, errors.New(fmt.Sprintf("unsupported patch operator %d", f.Operator))
+		}
+	}
+	return result, nil
+}
+
+/*
+GdaoSave implements godal.IGenericDao.GdaoSave.
+*/
+func (dao *GenericDaoMemory) GdaoSave(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveCtx(nil, storageId, bo)
+}
+
+/*
+GdaoSaveCtx implements godal.IGenericDaoWithContext.GdaoSaveCtx.
+*/
+func (dao *GenericDaoMemory) GdaoSaveCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSave", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := checkContext(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeSave, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.save(ctx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterSave, storageId, bo, numRows, err)
+}
+
+// save replaces an existing record or inserts a new one, see GdaoSaveCtx.
+func (dao *GenericDaoMemory) save(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	filter, err := toFilterOpt(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	row, err := dao.toRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	storages, unlock, err := dao.wlock(ctx, storageId)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	i := dao.findIndex(storages[storageId], filter)
+	if err := dao.bumpVersion(storageId, storages[storageId], row, i); err != nil {
+		return 0, err
+	}
+	if dao.violateUniqueIndexes(storageId, storages[storageId], row, i) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	}
+	if i < 0 {
+		storages[storageId] = append(storages[storageId], row)
+	} else {
+		storages[storageId][i] = row
+	}
+	return 1, dao.setWrittenVersion(storageId, bo, row)
+}
+
+/*
+GdaoCreateMany implements godal.IGenericDao.GdaoCreateMany.
+*/
+func (dao *GenericDaoMemory) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyCtx(nil, storageId, boList)
+}
+
+/*
+GdaoCreateManyCtx implements godal.IGenericDaoWithContext.GdaoCreateManyCtx.
+
+BOs are created one by one using GdaoCreateCtx.
+*/
+func (dao *GenericDaoMemory) GdaoCreateManyCtx(ctx context.Context, storageId string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreateMany", storageId)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	for i, bo := range boList {
+		results[i].NumRows, results[i].Error = dao.GdaoCreateCtx(ctx, storageId, bo)
+	}
+	return results, bulkError(results)
+}
+
+/*
+GdaoSaveMany implements godal.IGenericDao.GdaoSaveMany.
+*/
+func (dao *GenericDaoMemory) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyCtx(nil, storageId, boList)
+}
+
+/*
+GdaoSaveManyCtx implements godal.IGenericDaoWithContext.GdaoSaveManyCtx.
+
+BOs are saved one by one using GdaoSaveCtx.
+*/
+func (dao *GenericDaoMemory) GdaoSaveManyCtx(ctx context.Context, storageId string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSaveMany", storageId)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	for i, bo := range boList {
+		results[i].NumRows, results[i].Error = dao.GdaoSaveCtx(ctx, storageId, bo)
+	}
+	return results, bulkError(results)
+}
+
+// bulkError returns the first error of the bulk results.
+func bulkError(results []godal.GdaoBulkResult) error {
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+	}
+	return nil
+}
+
+// boundDao returns the DAO (the outer one if possible) bound to ctx.
+func (dao *GenericDaoMemory) boundDao(ctx context.Context) godal.IGenericDao {
+	if gdao, ok := dao.IGenericDao.(godal.IGenericDaoWithContext); ok {
+		return godal.NewContextBoundGenericDao(ctx, gdao)
+	}
+	return godal.NewContextBoundGenericDao(ctx, dao)
+}
+
+/*
+GdaoWithTransaction implements godal.ITransactionalDao.GdaoWithTransaction.
+
+	- transactions work on snapshots of the storages they access, which are installed when txFunc returns. Operations made outside the
+	  transaction (including calls made without txCtx from txFunc) do not wait for it and do not see its changes until it commits.
+	- if txFunc returns error or panics, all changes made inside the transaction are discarded.
+	- the commit fails with a godal.ErrConcurrentModification error if a storage written by the transaction has been written outside of
+	  it in the meantime (or truncated, or given a new unique index). Such errors are retryable: the whole txFunc is re-run under the
+	  DAO's retry policy (see godal.AbstractGenericDao.SetRetryPolicy).
+	- operations made with txCtx (or the dao passed to txFunc) are serialized, txCtx can be shared by several goroutines. Once txFunc returns,
+	  the transaction waits for on-going operations made with txCtx; operations made with txCtx afterwards return godal.ErrTxEnded.
+	- if ctx is carrying an on-going transaction of the dao, txFunc joins it (godal.ErrTxEnded is returned if the transaction has ended).
+*/
+func (dao *GenericDaoMemory) GdaoWithTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao godal.IGenericDao) error) error {
+	if tx := dao.txFromContext(ctx); tx != nil {
+		tx.lock.Lock()
+		ended := tx.ended
+		tx.lock.Unlock()
+		if ended {
+			return godal.ErrTxEnded
+		}
+		return txFunc(ctx, dao.boundDao(ctx))
+	}
+	if err := checkContext(ctx); err != nil {
+		return err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return dao.WithRetry(ctx, godal.IsRetryableError, func() error {
+		tx := &memoryTx{
+			dao:      dao,
+			storages: make(map[string][]map[string]interface{}),
+			versions: make(map[string]int64),
+			written:  make(map[string]bool),
+		}
+		txCtx := context.WithValue(ctx, ctxKeyTx{}, tx)
+		// changes are made to the transaction's copies only: if txFunc panics, they are simply discarded
+		err := func() error {
+			defer tx.end()
+			return txFunc(txCtx, dao.boundDao(txCtx))
+		}()
+		if err != nil {
+			return err
+		}
+		return tx.commit()
+	})
+}
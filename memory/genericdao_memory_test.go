@@ -0,0 +1,1544 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/godal"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+type MyBo struct {
+	Id       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Version  int    `json:"version"`
+}
+
+func (bo *MyBo) ToGbo() godal.IGenericBo {
+	gbo := godal.NewGenericBo()
+	if err := gbo.GboImportViaJson(bo); err != nil {
+		panic(err)
+	}
+	return gbo
+}
+
+func fromGbo(gbo godal.IGenericBo) *MyBo {
+	bo := MyBo{}
+	gbo.GboTransferViaJson(&bo)
+	return &bo
+}
+
+const (
+	collectionName = "test"
+	fieldId        = "id"
+	fieldUsername  = "username"
+)
+
+type MyDaoMemory struct {
+	*GenericDaoMemory
+	collectionName string
+}
+
+// GdaoCreateFilter implements godal.IGenericDao.GdaoCreateFilter.
+func (dao *MyDaoMemory) GdaoCreateFilter(storageId string, bo godal.IGenericBo) interface{} {
+	return map[string]interface{}{fieldId: bo.GboGetAttrUnsafe(fieldId, reddo.TypeString)}
+}
+
+/*----------------------------------------------------------------------*/
+func initDao() *MyDaoMemory {
+	dao := &MyDaoMemory{collectionName: collectionName}
+	dao.GenericDaoMemory = NewGenericDaoMemory(godal.NewAbstractGenericDao(dao))
+	dao.AddUniqueIndex(collectionName, fieldUsername)
+	return dao
+}
+
+func TestGenericRowMapperMemory_ColumnsList(t *testing.T) {
+	name := "TestGenericRowMapperMemory_ColumnsList"
+	table := "table"
+	rowmapper := &GenericRowMapperMemory{}
+
+	colList := rowmapper.ColumnsList(table)
+	if len(colList) != 1 || colList[0] != "*" {
+		t.Fatalf("%s failed: %v", name, colList)
+	}
+}
+
+func TestGenericRowMapperMemory_ToBo(t *testing.T) {
+	name := "TestGenericRowMapperMemory_ToBo"
+	table := "table"
+	colA, colB, col1 := "cola", "ColB", "Col1"
+	valA, valB, val1 := "a", "B", int64(1)
+	rowmapper := &GenericRowMapperMemory{}
+
+	for _, row := range []interface{}{
+		map[string]interface{}{colA: valA, colB: valB, col1: val1},
+		`{"cola":"a","ColB":"B","Col1":1}`,
+		[]byte(`{"cola":"a","ColB":"B","Col1":1}`),
+	} {
+		bo, err := rowmapper.ToBo(table, row)
+		if err != nil || bo == nil {
+			t.Fatalf("%s failed: %e / %v", name, err, bo)
+		}
+		if bo.GboGetAttrUnsafe(colA, reddo.TypeString) != valA ||
+			bo.GboGetAttrUnsafe(colB, reddo.TypeString) != valB ||
+			bo.GboGetAttrUnsafe(col1, reddo.TypeInt).(int64) != val1 {
+			t.Fatalf("%s failed, Row: %v - Bo: %v", name, row, bo)
+		}
+	}
+
+	if bo, err := rowmapper.ToBo(table, nil); err != nil || bo != nil {
+		t.Fatalf("%s failed: %e / %v", name, err, bo)
+	}
+	if bo, err := rowmapper.ToBo(table, 1); err == nil || bo != nil {
+		t.Fatalf("%s failed: %e / %v", name, err, bo)
+	}
+}
+
+func TestGenericRowMapperMemory_ToRow(t *testing.T) {
+	name := "TestGenericRowMapperMemory_ToRow"
+	table := "table"
+	rowmapper := &GenericRowMapperMemory{}
+
+	bo := godal.NewGenericBo()
+	bo.GboSetAttr("a.b", "value")
+	row, err := rowmapper.ToRow(table, bo)
+	if err != nil || row == nil {
+		t.Fatalf("%s failed: %e / %v", name, err, row)
+	}
+	bo.GboSetAttr("a.b", "changed")
+	if v := row.(map[string]interface{})["a"].(map[string]interface{})["b"]; v != "value" {
+		t.Fatalf("%s failed, row is not a deep copy of bo: %v", name, row)
+	}
+}
+
+func TestGenericDaoMemory_Empty(t *testing.T) {
+	name := "TestGenericDaoMemory_Empty"
+	dao := initDao()
+
+	boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0)
+	if err != nil {
+		t.Fatalf("%s failed, has error: %e", name, err)
+	}
+	if boList == nil {
+		t.Fatalf("%s failed, nil result", name)
+	}
+	if len(boList) != 0 {
+		t.Fatalf("%s failed, non-empty result: %v", name, boList)
+	}
+
+	bo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "any"})
+	if err != nil {
+		t.Fatalf("%s failed, has error: %e", name, err)
+	}
+	if bo != nil {
+		t.Fatalf("%s failed, should have nill result, but received: %v", name, bo)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateDuplicated(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateDuplicated"
+	dao := initDao()
+	bo1 := &MyBo{
+		Id:       "1",
+		Username: "1",
+		Name:     "BO - 1",
+		Version:  1,
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo1.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	bo2 := &MyBo{
+		Id:       "2",
+		Username: "1",
+		Name:     "BO - 2",
+		Version:  2,
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo2.ToGbo()); err != godal.GdaoErrorDuplicatedEntry || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// records having a unique-indexed field nil or missing are not checked against the index
+	for _, id := range []string{"3", "4"} {
+		gbo := godal.NewGenericBo()
+		gbo.GboSetAttr(fieldId, id)
+		if numRows, err := dao.GdaoCreate(dao.collectionName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	gbo := godal.NewGenericBo()
+	gbo.GboSetAttr(fieldId, "5")
+	gbo.GboSetAttr(fieldUsername, nil)
+	if numRows, err := dao.GdaoCreate(dao.collectionName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateTwiceGet(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateTwiceGet"
+	dao := initDao()
+	bo := &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 3",
+		Version:  4,
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	bo.Version = bo.Version + 1
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	bo.Version = bo.Version - 1
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if myBo := fromGbo(gbo); myBo == nil || *myBo != *bo {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateMultiThreadsGet(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateMultiThreadsGet"
+	dao := initDao()
+	numThreads := 4
+	numLoopsPerThread := 10
+	var wg sync.WaitGroup
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func(threadNum int, bo *MyBo) {
+			defer wg.Done()
+			for j := 0; j < numLoopsPerThread; j++ {
+				if _, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil && err != godal.GdaoErrorDuplicatedEntry {
+					t.Errorf("%s failed - Thread: %v / Error: %e", name, threadNum, err)
+				}
+				bo.Version = bo.Version + 1
+			}
+		}(i, &MyBo{
+			Id:       "1",
+			Username: "2",
+			Name:     "BO - " + strconv.Itoa(i+1),
+			Version:  3,
+		})
+	}
+	wg.Wait()
+
+	boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0)
+	if err != nil || len(boList) != 1 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(boList), err)
+	}
+	if myBo := fromGbo(boList[0]); myBo.Id != "1" || myBo.Username != "2" || myBo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, myBo)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateDelete(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateDelete"
+	dao := initDao()
+	bo := &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 3",
+		Version:  4,
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, bo.ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil {
+		t.Fatalf("%s failed, has error: %e", name, err)
+	}
+	if gbo != nil {
+		t.Fatalf("%s failed, should have nill result, but received: %v", name, gbo)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateDeleteMany(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateDeleteMany"
+	dao := initDao()
+	totalRows := 10
+	for i := 0; i < totalRows; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i + 1),
+			Name:     "BO - " + strconv.Itoa(i%2),
+			Version:  i % 2,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	if numRows, err := dao.GdaoDeleteMany(dao.collectionName, `{"version":1}`); err != nil || numRows != totalRows/2 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDeleteMany(dao.collectionName, nil); err != nil || numRows != totalRows/2 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoFetchAllWithSorting(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoFetchAllWithSorting"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	gboList, err := dao.GdaoFetchMany(dao.collectionName, nil, map[string]int{"version": -1}, 0, 0)
+	if err != nil || gboList == nil || len(gboList) != numItems {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(gboList), err)
+	}
+
+	for i, gbo := range gboList {
+		if bo := fromGbo(gbo); bo.Id != strconv.Itoa(numItems-i-1) {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, numItems-i-1, bo)
+		}
+	}
+}
+
+func TestGenericDaoMemory_GdaoFetchAllWithSortingOpt(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoFetchAllWithSortingOpt"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i%3),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// "version" is sorted before "name" by a map sorting, SortingOpt keeps the order of fields
+	sorting := godal.NewSortingOpt().Add("name", godal.SortOrderDesc).Add("version", godal.SortOrderAsc)
+	gboList, err := dao.GdaoFetchMany(dao.collectionName, nil, sorting, 0, 0)
+	if err != nil || gboList == nil || len(gboList) != numItems {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(gboList), err)
+	}
+	var prev *MyBo
+	for _, gbo := range gboList {
+		bo := fromGbo(gbo)
+		if prev != nil && (bo.Name > prev.Name || (bo.Name == prev.Name && bo.Version <= prev.Version)) {
+			t.Fatalf("%s failed - %v is not expected to be after %v", name, bo, prev)
+		}
+		prev = bo
+	}
+	if first := fromGbo(gboList[0]); first.Name != "BO - 2" || first.Version != 2 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, "BO - 2/2", first)
+	}
+}
+
+func TestGenericDaoMemory_GdaoFetchManyWithPaging(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoFetchManyWithPaging"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i%2),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	filter := map[string]interface{}{"name": "BO - 0"}
+	gboList, err := dao.GdaoFetchMany(dao.collectionName, filter, map[string]int{"version": 1}, 40, 20)
+	if err != nil || gboList == nil || len(gboList) != 10 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(gboList), err)
+	}
+
+	for i, gbo := range gboList {
+		if bo := fromGbo(gbo); bo.Version != (40+i)*2 {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, (40+i)*2, bo)
+		}
+	}
+}
+
+func TestGenericDaoMemory_FilterOpt(t *testing.T) {
+	name := "TestGenericDaoMemory_FilterOpt"
+	dao := initDao()
+	for i := 0; i < 10; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: "user" + strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i%2),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	testCases := []struct {
+		filter   *godal.FilterOpt
+		expected int
+	}{
+		{godal.FilterAnd(godal.FilterGte("version", 5), godal.FilterEq("name", "BO - 0")), 2},
+		{godal.FilterIn("version", 1, 3, 99), 2},
+		{godal.FilterBetween("version", 2, 4), 3},
+		{godal.FilterPrefix("username", "user1"), 1},
+		{godal.FilterNot(godal.FilterLt("version", 8)), 2},
+		{godal.FilterNe("name", "BO - 0"), 5},
+		{godal.FilterOr(godal.FilterEq("id", "0"), godal.FilterEq("id", "9")), 2},
+		{godal.FilterIsNull("email"), 10},
+		{godal.FilterExists("email"), 0},
+		{godal.FilterNe("email", "x"), 0},
+		{godal.FilterGt("name", 1), 0},
+	}
+	for i, testCase := range testCases {
+		gboList, err := dao.GdaoFetchMany(dao.collectionName, testCase.filter, nil, 0, 0)
+		if err != nil || len(gboList) != testCase.expected {
+			t.Fatalf("%s failed at case #%d - Expected: %v / Received: %v / Error: %e", name, i, testCase.expected, len(gboList), err)
+		}
+	}
+
+	if _, err := dao.GdaoFetchMany(dao.collectionName, godal.FilterIn("version"), nil, 0, 0); err == nil {
+		t.Fatalf("%s failed - Expected error for empty IN filter", name)
+	}
+
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, godal.FilterEq("username", "user7")); err != nil || gbo == nil || fromGbo(gbo).Id != "7" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoDeleteMany(dao.collectionName, godal.FilterLt("version", 3)); err != nil || numRows != 3 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoFetchIterator(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoFetchIterator"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	iter, err := dao.GdaoFetchIterator(dao.collectionName, godal.FilterGte("version", 80), godal.NewSortingOpt().Add("version", godal.SortOrderDesc))
+	if err != nil || iter == nil {
+		t.Fatalf("%s failed - Iterator: %v / Error: %e", name, iter, err)
+	}
+	defer func() { _ = iter.Close() }()
+	// changes made after the iterator is created are not visible to it
+	if numRows, err := dao.GdaoDeleteMany(dao.collectionName, nil); err != nil || numRows != numItems {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	count := 0
+	for ; iter.Next(); count++ {
+		if bo := fromGbo(iter.Bo()); bo.Version != numItems-count-1 {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, numItems-count-1, bo)
+		}
+	}
+	if err := iter.Err(); err != nil || count != 20 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, count, err)
+	}
+
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	iter, err = dao.GdaoFetchIteratorCtx(ctx, dao.collectionName, nil, nil)
+	if err != nil || iter == nil {
+		t.Fatalf("%s failed - Iterator: %v / Error: %e", name, iter, err)
+	}
+	cancel()
+	if iter.Next() || iter.Err() != context.Canceled {
+		t.Fatalf("%s failed - Expected error %e / Received: %e", name, context.Canceled, iter.Err())
+	}
+}
+
+func TestGenericDaoMemory_GdaoFetchPage(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoFetchPage"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(1000 + i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i%10),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	filter := godal.FilterGte("version", 20)
+	sorting := godal.NewSortingOpt().Add("name", godal.SortOrderDesc).Add("version", godal.SortOrderAsc)
+	pageToken, fetched := "", make([]*MyBo, 0)
+	for numPages := 1; ; numPages++ {
+		boList, nextPageToken, err := dao.GdaoFetchPage(dao.collectionName, filter, sorting, pageToken, 7)
+		if err != nil || len(boList) > 7 || numPages > 12 {
+			t.Fatalf("%s failed - Page: %v / NumItems: %v / Error: %e", name, numPages, len(boList), err)
+		}
+		for _, gbo := range boList {
+			fetched = append(fetched, fromGbo(gbo))
+		}
+		if pageToken = nextPageToken; pageToken == "" {
+			break
+		}
+		// items inserted before the current position must not shift the next page
+		bo := &MyBo{Id: "0" + strconv.Itoa(numPages), Username: "new" + strconv.Itoa(numPages), Name: "BO - 9", Version: 20 + numPages}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if len(fetched) != 80 {
+		t.Fatalf("%s failed - Expected %v items / Received: %v", name, 80, len(fetched))
+	}
+	for i := 1; i < len(fetched); i++ {
+		prev, cur := fetched[i-1], fetched[i]
+		if prev.Name < cur.Name || (prev.Name == cur.Name && prev.Version >= cur.Version) {
+			t.Fatalf("%s failed - Items out of order: %v / %v", name, prev, cur)
+		}
+	}
+
+	if _, _, err := dao.GdaoFetchPage(dao.collectionName, filter, sorting, "invalid-token", 7); err != godal.GdaoErrorInvalidPageToken {
+		t.Fatalf("%s failed - Expected error %e / Received: %e", name, godal.GdaoErrorInvalidPageToken, err)
+	}
+	otherSorting := godal.NewSortingOpt().Add("name", godal.SortOrderAsc).Add("version", godal.SortOrderAsc)
+	if _, token, _ := dao.GdaoFetchPage(dao.collectionName, filter, sorting, "", 7); token == "" {
+		t.Fatalf("%s failed - Expected non-empty page token", name)
+	} else if _, _, err := dao.GdaoFetchPage(dao.collectionName, filter, otherSorting, token, 7); err != godal.GdaoErrorInvalidPageToken {
+		t.Fatalf("%s failed - Expected error %e / Received: %e", name, godal.GdaoErrorInvalidPageToken, err)
+	}
+	if _, _, err := dao.GdaoFetchPage(dao.collectionName, filter, map[string]int{"name": 1}, "", 7); err == nil {
+		t.Fatalf("%s failed - Expected error for non-SortingOpt sorting", name)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCountExists(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCountExists"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != int64(numItems) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, numItems, count, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, godal.FilterGte("version", 80)); err != nil || count != 20 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 20, count, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, map[string]interface{}{fieldId: "99"}); err != nil || !exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, true, exists, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, godal.FilterGte("version", numItems)); err != nil || exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, false, exists, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoCreateManySaveMany(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoCreateManySaveMany"
+	dao := initDao()
+	for i := 0; i < 4; i += 2 {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// BOs "0" and "2" already exist
+	boList := make([]godal.IGenericBo, 5)
+	for i := range boList {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: "user" + strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		boList[i] = bo.ToGbo()
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, boList)
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if i < 4 && i%2 == 0 && (r.Error != godal.GdaoErrorDuplicatedEntry || r.NumRows != 0) {
+			t.Fatalf("%s failed - Item: %d / Expected error: %e / Result: %v", name, i, godal.GdaoErrorDuplicatedEntry, r)
+		}
+		if (i >= 4 || i%2 != 0) && (r.Error != nil || r.NumRows != 1) {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+
+	results, err = dao.GdaoSaveMany(dao.collectionName, boList)
+	if err != nil || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if r.Error != nil || r.NumRows != 1 {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "2"}); err != nil || gbo == nil || fromGbo(gbo).Username != "user2" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoUpdateNotExist(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoUpdateNotExist"
+	dao := initDao()
+	bo := &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 3",
+		Version:  4,
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoUpdateDuplicated(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoUpdateDuplicated"
+	dao := initDao()
+	for i := 0; i < 2; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  1,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	bo := &MyBo{
+		Id:       "0",
+		Username: "1",
+		Name:     "BO - 0",
+		Version:  2,
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoUpdate(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoUpdate"
+	dao := initDao()
+	for i := 0; i < 3; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	bo := &MyBo{
+		Id:       "0",
+		Username: "100",
+		Name:     "BO",
+		Version:  100,
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	for i := 0; i < 3; i++ {
+		gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: strconv.Itoa(i)})
+		if err != nil || gbo == nil {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		if myBo := fromGbo(gbo); i == 0 && *myBo != *bo {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
+		} else if i != 0 && myBo.Version != i {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, i, myBo.Version)
+		}
+	}
+}
+
+func TestGenericDaoMemory_GdaoPatch(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoPatch"
+	dao := initDao()
+	for i := 1; i <= 2; i++ {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	bo := (&MyBo{Id: "1"}).ToGbo()
+
+	patch := godal.NewPatchOpt().Set("name", "patched").Increment("version", 2).Append("tags", "a")
+	if numRows, err := dao.GdaoPatch(dao.collectionName, (&MyBo{Id: "9"}).ToGbo(), patch); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, patch); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if myBo := fromGbo(gbo); myBo.Name != "patched" || myBo.Version != 3 || myBo.Username != "1" {
+		t.Fatalf("%s failed - Received: %v", name, myBo)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || !reflect.DeepEqual(tags, []interface{}{"a"}) {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Unset("name").Append("tags", "b")); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, _ = dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if v, err := gbo.GboGetAttr("name", nil); err != nil || v != nil {
+		t.Fatalf("%s failed - Name: %v / Error: %e", name, v, err)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || !reflect.DeepEqual(tags, []interface{}{"a", "b"}) {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Set(fieldUsername, "2")); err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Increment(fieldUsername, 1)); err == nil {
+		t.Fatalf("%s failed - Expected error for incrementing a string field", name)
+	}
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt()); err == nil {
+		t.Fatalf("%s failed - Expected error for empty patch", name)
+	}
+
+	// patch hooks run around the patch, "before" hooks can extend the patch, patches are validated
+	counters := make(map[godal.HookPoint]int)
+	for _, point := range []godal.HookPoint{godal.HookBeforePatch, godal.HookAfterPatch} {
+		point := point
+		dao.AddPatchHook(point, func(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) error {
+			if ctx == nil || storageId != dao.collectionName || patch == nil {
+				return errors.New("invalid hook arguments")
+			}
+			counters[point]++
+			return nil
+		})
+	}
+	dao.AddPatchHook(godal.HookBeforePatch, func(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) error {
+		patch.Set("stamp", "patched")
+		return nil
+	})
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Set("name", "hooked")); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, _ = dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if myBo := fromGbo(gbo); myBo.Name != "hooked" || gbo.GboGetAttrUnsafe("stamp", nil) != "patched" {
+		t.Fatalf("%s failed - Gbo: %v", name, gbo)
+	}
+	minLen := 1
+	dao.SetValidator(dao.collectionName, &godal.BoSchema{Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}}})
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Set("name", "")); err == nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if expected := map[godal.HookPoint]int{godal.HookBeforePatch: 2, godal.HookAfterPatch: 1}; !reflect.DeepEqual(counters, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, counters)
+	}
+}
+
+func TestGenericDaoMemory_GdaoSaveDuplicated(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoSaveDuplicated"
+	dao := initDao()
+	for i := 1; i <= 3; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoSave(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// save new one with duplicated key
+	bo := &MyBo{
+		Id:       "0",
+		Username: "1",
+		Name:     "BO - 0",
+		Version:  0,
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// save existing one with duplicated key
+	bo = &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 1",
+		Version:  1,
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoSave(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoSave"
+	dao := initDao()
+	bo := &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 3",
+		Version:  4,
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	bo.Name = "BO"
+	bo.Version = 10
+	if numRows, err := dao.GdaoSave(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if myBo := fromGbo(gbo); *myBo != *bo {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
+	}
+}
+
+func TestGenericDaoMemory_GboDeleteAttr(t *testing.T) {
+	name := "TestGenericDaoMemory_GboDeleteAttr"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || !gbo.GboHasAttr("name") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err = dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || gbo.GboHasAttr("name") || !gbo.GboHasAttr("username") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoUpdateDirty(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoUpdateDirty"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchGbo := func(id string) godal.IGenericBo {
+		gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil || gbo.GboIsDirty() {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return gbo
+	}
+	gbo1, gbo2 := fetchGbo("1"), fetchGbo("1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo1); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// writers modifying different attributes do not overwrite each other's changes
+	gbo1.GboSetAttr("name", "changed")
+	gbo2.GboSetAttr(fieldUsername, "u2")
+	for _, gbo := range []godal.IGenericBo{gbo1, gbo2} {
+		if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "changed" || bo.Username != "u2" || bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// removed attributes are removed from storage
+	gbo := fetchGbo("1")
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo = fetchGbo("1"); gbo.GboHasAttr("name") || !gbo.GboHasAttr(fieldUsername) {
+		t.Fatalf("%s failed - Gbo: %v", name, gbo)
+	}
+
+	gbo = (&MyBo{Id: "9", Username: "9"}).ToGbo()
+	gbo.GboSetAttr("name", "changed")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// optimistic locking
+	dao.SetVersionField(dao.collectionName, "version")
+	gbo1, gbo2 = fetchGbo("1"), fetchGbo("1")
+	gbo1.GboSetAttr("name", "v1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo1); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo2.GboSetAttr(fieldUsername, "stale")
+	if _, err := dao.GdaoUpdateDirty(dao.collectionName, gbo2); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "v1" || bo.Username != "u2" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+}
+
+func TestGenericDaoMemory_Validator(t *testing.T) {
+	name := "TestGenericDaoMemory_Validator"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.collectionName, &godal.BoSchema{
+		Type:       godal.SchemaTypeObject,
+		Required:   []string{fieldId, fieldUsername},
+		Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}},
+	})
+	isValidationError := func(err error) bool {
+		_, ok := err.(*godal.ValidationError)
+		return ok
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	invalidBo := (&MyBo{Id: "2", Username: "2"}).ToGbo()
+	if _, err := dao.GdaoCreate(dao.collectionName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoSave(dao.collectionName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, []godal.IGenericBo{(&MyBo{Id: "3", Username: "3", Name: "BO"}).ToGbo(), invalidBo})
+	if !isValidationError(err) || len(results) != 2 || results[0].Error != nil || results[0].NumRows != 1 || !isValidationError(results[1].Error) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, map[string]interface{}{fieldId: "2"}); err != nil || exists {
+		t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || fromGbo(gbo).Name != "BO" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	dao.SetValidator(dao.collectionName, nil)
+	if numRows, err := dao.GdaoCreate(dao.collectionName, invalidBo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_Hooks(t *testing.T) {
+	name := "TestGenericDaoMemory_Hooks"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.collectionName, &godal.BoSchema{Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}}})
+	counters := make(map[godal.HookPoint]int)
+	errAbort := errors.New("abort")
+	for _, point := range []godal.HookPoint{godal.HookBeforeCreate, godal.HookAfterCreate, godal.HookBeforeUpdate, godal.HookAfterUpdate,
+		godal.HookBeforeSave, godal.HookAfterSave, godal.HookBeforeDelete, godal.HookAfterDelete, godal.HookAfterFetch} {
+		point := point
+		dao.AddHook(point, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+			if ctx == nil || storageId != dao.collectionName {
+				return errors.New("invalid hook arguments")
+			}
+			counters[point]++
+			return nil
+		})
+	}
+	stamp := func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe("name", reddo.TypeString) == "" {
+			return bo.GboSetAttr("name", "stamped")
+		}
+		return nil
+	}
+	dao.AddHook(godal.HookBeforeCreate, stamp).AddHook(godal.HookBeforeUpdate, stamp).AddHook(godal.HookBeforeSave, stamp)
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return bo.GboSetAttr("fetched", true)
+	})
+	dao.AddHook(godal.HookBeforeDelete, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe(fieldId, reddo.TypeString) == "locked" {
+			return errAbort
+		}
+		return nil
+	})
+
+	// "before" hooks run before validation, changes made by hooks are written
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || fromGbo(gbo).Name != "stamped" || gbo.GboGetAttrUnsafe("fetched", nil) != true || gbo.GboIsDirty() {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "0", Username: "0"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "locked", Username: "locked"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, []godal.IGenericBo{(&MyBo{Id: "2", Username: "2"}).ToGbo(), (&MyBo{Id: "1", Username: "1"}).ToGbo()})
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != 2 || results[0].NumRows != 1 || results[1].Error != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, (&MyBo{Id: "locked"}).ToGbo()); err != errAbort || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, (&MyBo{Id: "2"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0); err != nil || len(boList) != 2 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	expected := map[godal.HookPoint]int{godal.HookBeforeCreate: 3, godal.HookAfterCreate: 2, godal.HookBeforeUpdate: 2, godal.HookAfterUpdate: 1,
+		godal.HookBeforeSave: 1, godal.HookAfterSave: 1, godal.HookBeforeDelete: 2, godal.HookAfterDelete: 1, godal.HookAfterFetch: 3}
+	if !reflect.DeepEqual(counters, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, counters)
+	}
+
+	// hooks can abort the operation
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return errAbort
+	})
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != errAbort || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	dao.ClearHooks(godal.HookAfterFetch)
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || gbo.GboGetAttrUnsafe("fetched", nil) != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+type testInstrumentation struct {
+	lock     sync.Mutex
+	finished []*godal.DaoOperation
+}
+
+func (ti *testInstrumentation) OnStart(ctx context.Context, op *godal.DaoOperation) context.Context {
+	return ctx
+}
+
+func (ti *testInstrumentation) OnFinish(ctx context.Context, op *godal.DaoOperation) {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	ti.finished = append(ti.finished, op)
+}
+
+func (ti *testInstrumentation) last() *godal.DaoOperation {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	if len(ti.finished) == 0 {
+		return nil
+	}
+	return ti.finished[len(ti.finished)-1]
+}
+
+func TestGenericDaoMemory_Instrumentation(t *testing.T) {
+	name := "TestGenericDaoMemory_Instrumentation"
+	dao := initDao()
+	instrumentation := &testInstrumentation{}
+	dao.SetInstrumentation(instrumentation)
+	verify := func(operation string, numRows int, err error) {
+		op := instrumentation.last()
+		if op == nil || op.Backend != "memory" || op.Operation != operation || op.StorageId != dao.collectionName || op.NumRows != numRows || op.Err != err {
+			t.Fatalf("%s failed - expected %s/%d/%v but received %v", name, operation, numRows, err, op)
+		}
+	}
+
+	if _, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	verify("GdaoCreate", 1, nil)
+	_, err := dao.GdaoCreateCtx(context.Background(), dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo())
+	verify("GdaoCreate", 0, err)
+	if err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	dao.GdaoCreateMany(dao.collectionName, []godal.IGenericBo{(&MyBo{Id: "2", Username: "2"}).ToGbo(), (&MyBo{Id: "3", Username: "3"}).ToGbo()})
+	verify("GdaoCreateMany", 2, nil)
+	dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0)
+	verify("GdaoFetchMany", 3, nil)
+	dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "0"})
+	verify("GdaoFetchOne", 0, nil)
+	dao.GdaoCount(dao.collectionName, nil)
+	verify("GdaoCount", 3, nil)
+	dao.GdaoExists(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	verify("GdaoExists", 1, nil)
+
+	// nested calls are reported too
+	numOps := len(instrumentation.finished)
+	dao.GdaoDelete(dao.collectionName, (&MyBo{Id: "1"}).ToGbo())
+	if len(instrumentation.finished) != numOps+2 || instrumentation.finished[numOps].Operation != "GdaoDeleteMany" {
+		t.Fatalf("%s failed - Operations: %v", name, instrumentation.finished[numOps:])
+	}
+	verify("GdaoDelete", 1, nil)
+
+	// cancelled context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = dao.GdaoUpdateCtx(ctx, dao.collectionName, (&MyBo{Id: "2", Username: "2"}).ToGbo())
+	verify("GdaoUpdate", 0, err)
+	if err == nil {
+		t.Fatalf("%s failed - error is expected", name)
+	}
+
+	// disable instrumentation
+	numOps = len(instrumentation.finished)
+	dao.SetInstrumentation(nil)
+	dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0)
+	if len(instrumentation.finished) != numOps {
+		t.Fatalf("%s failed - no operation is expected to be reported", name)
+	}
+}
+
+func TestGenericDaoMemory_OptimisticLocking(t *testing.T) {
+	name := "TestGenericDaoMemory_OptimisticLocking"
+	dao := initDao()
+	dao.SetVersionField(dao.collectionName, "version")
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchBo := func(id string) *MyBo {
+		gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return fromGbo(gbo)
+	}
+
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v1", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	// stale version
+	if _, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if _, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v2", Version: 2}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// GdaoSave creates non-existing BO, GdaoUpdate does not
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "2", Username: "2", Name: "new"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("2"); bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "3", Username: "3"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// the new version is written back to the BO: it can be updated again without being re-fetched
+	gbo := (&MyBo{Id: "2", Username: "2", Name: "new", Version: 1}).ToGbo()
+	for _, boName := range []string{"u1", "u2"} {
+		gbo.GboSetAttr("name", boName)
+		if numRows, err := dao.GdaoUpdate(dao.collectionName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fetchBo("2"); bo.Name != "u2" || bo.Version != 3 || fromGbo(gbo).Version != 3 {
+		t.Fatalf("%s failed - Received: %v / BO: %v", name, bo, fromGbo(gbo))
+	}
+
+	// GdaoSaveMany checks the version of each BO
+	boList := []godal.IGenericBo{
+		(&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo(),
+		(&MyBo{Id: "2", Username: "2", Name: "v4", Version: 3}).ToGbo(),
+		(&MyBo{Id: "4", Username: "4", Name: "new"}).ToGbo(),
+	}
+	results, err := dao.GdaoSaveMany(dao.collectionName, boList)
+	if err != godal.GdaoErrorConcurrentModification || results[0].Error != godal.GdaoErrorConcurrentModification || results[1].NumRows != 1 || results[2].NumRows != 1 {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("2"); bo.Name != "v4" || bo.Version != 4 || fromGbo(boList[1]).Version != 4 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("4"); bo.Version != 1 || fromGbo(boList[2]).Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// optimistic locking disabled
+	dao.SetVersionField(dao.collectionName, "")
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v0", Version: 0}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+type myRepoBo struct {
+	Id       string `godal:"id,key"`
+	Username string `godal:"username"`
+	Name     string `godal:"name"`
+	Version  int    `godal:"version"`
+}
+
+func TestGenericDaoMemory_Repository(t *testing.T) {
+	name := "TestGenericDaoMemory_Repository"
+	dao := initDao()
+	repo, err := godal.NewRepository(dao, dao.collectionName, myRepoBo{})
+	if err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	for i := 1; i <= 3; i++ {
+		bo := &myRepoBo{Id: strconv.Itoa(i), Username: "user" + strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := repo.Create(bo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if _, err := repo.Create(&myRepoBo{Id: "1"}); err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+
+	if obj, err := repo.Get("2"); err != nil || obj == nil {
+		t.Fatalf("%s failed - Object: %v / Error: %e", name, obj, err)
+	} else if bo := obj.(*myRepoBo); *bo != (myRepoBo{Id: "2", Username: "user2", Name: "BO - 2", Version: 2}) {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if obj, err := repo.Get("9"); err != nil || obj != nil {
+		t.Fatalf("%s failed - Object: %v / Error: %e", name, obj, err)
+	}
+
+	if numRows, err := repo.Update(&myRepoBo{Id: "2", Username: "user2", Name: "updated"}); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := repo.Save(&myRepoBo{Id: "4", Username: "user4", Name: "saved"}); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := repo.Delete(&myRepoBo{Id: "1"}); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	result, err := repo.Find(nil, godal.NewSortingOpt().Add("id", godal.SortOrderAsc), 0, 0)
+	if err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	boList := result.([]*myRepoBo)
+	if len(boList) != 3 || boList[0].Name != "updated" || boList[1].Id != "3" || boList[2].Name != "saved" {
+		t.Fatalf("%s failed - Received: %v", name, boList)
+	}
+
+	// with optimistic locking, the struct carries its new version after each write
+	dao.SetVersionField(dao.collectionName, "version")
+	bo := &myRepoBo{Id: "3", Username: "user3", Name: "BO - 3", Version: 3}
+	for i := 1; i <= 2; i++ {
+		if numRows, err := repo.Update(bo); err != nil || numRows != 1 || bo.Version != 3+i {
+			t.Fatalf("%s failed - NumRows: %v / Version: %v / Error: %e", name, numRows, bo.Version, err)
+		}
+	}
+	if numRows, err := repo.Save(bo); err != nil || numRows != 1 || bo.Version != 6 {
+		t.Fatalf("%s failed - NumRows: %v / Version: %v / Error: %e", name, numRows, bo.Version, err)
+	}
+}
+
+func TestGenericDaoMemory_IGenericDaoWithContext(t *testing.T) {
+	name := "TestGenericDaoMemory_IGenericDaoWithContext"
+	var dao interface{} = initDao()
+	if _, ok := dao.(godal.IGenericDaoWithContext); !ok {
+		t.Fatalf("%s failed: GenericDaoMemory does not implement godal.IGenericDaoWithContext", name)
+	}
+}
+
+func TestGenericDaoMemory_CtxCancelled(t *testing.T) {
+	name := "TestGenericDaoMemory_CtxCancelled"
+	dao := initDao()
+	bo := &MyBo{
+		Id:       "1",
+		Username: "2",
+		Name:     "BO - 3",
+		Version:  4,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	if numRows, err := dao.GdaoCreateCtx(ctx, dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	cancel()
+	if numRows, err := dao.GdaoSaveCtx(ctx, dao.collectionName, bo.ToGbo()); err != context.Canceled || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err := dao.GdaoFetchOneCtx(ctx, dao.collectionName, map[string]interface{}{fieldId: "1"}); err != context.Canceled || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoDeleteCtx(ctx, dao.collectionName, bo.ToGbo()); err != context.Canceled || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err := dao.GdaoFetchOneCtx(context.Background(), dao.collectionName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if numRows, err := dao.GdaoSaveCtx(ctx, dao.collectionName, bo.ToGbo()); !errors.Is(err, godal.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoWithTransaction(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoWithTransaction"
+	dao := initDao()
+	var _ godal.ITransactionalDao = dao
+
+	// committed
+	err := dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		for i := 0; i < 2; i++ {
+			bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+			if numRows, err := txDao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+				return err
+			}
+		}
+		// nested transaction joins the outer one
+		return dao.GdaoWithTransaction(txCtx, func(txCtx context.Context, txDao godal.IGenericDao) error {
+			bo := &MyBo{Id: "2", Username: "2", Name: "BO - 2", Version: 2}
+			_, err := txDao.GdaoSave(dao.collectionName, bo.ToGbo())
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0); err != nil || len(boList) != 3 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(boList), err)
+	}
+
+	// rolled back
+	err = dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		bo := &MyBo{Id: "0", Username: "100", Name: "BO", Version: 100}
+		if numRows, err := txDao.GdaoUpdate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		if numRows, err := dao.GdaoDeleteCtx(txCtx, dao.collectionName, (&MyBo{Id: "1"}).ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		bo = &MyBo{Id: "3", Username: "2", Name: "BO - 3", Version: 3}
+		_, err := txDao.GdaoCreate(dao.collectionName, bo.ToGbo())
+		return err
+	})
+	if err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+	boList, err := dao.GdaoFetchMany(dao.collectionName, nil, map[string]int{"version": 1}, 0, 0)
+	if err != nil || len(boList) != 3 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, len(boList), err)
+	}
+	for i, gbo := range boList {
+		if myBo := fromGbo(gbo); myBo.Id != strconv.Itoa(i) || myBo.Version != i {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, i, myBo)
+		}
+	}
+}
+
+func TestGenericDaoMemory_GdaoWithTransaction_TxCtx(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoWithTransaction_TxCtx"
+	dao := initDao()
+
+	// txCtx can be shared by several goroutines
+	var staleCtx context.Context
+	err := dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		staleCtx = txCtx
+		wg := &sync.WaitGroup{}
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+				if numRows, err := txDao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+					t.Errorf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+				}
+				if _, err := dao.GdaoFetchManyCtx(txCtx, dao.collectionName, nil, nil, 0, 0); err != nil {
+					t.Errorf("%s failed - Error: %e", name, err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 10 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+
+	// txCtx is no longer usable once the transaction has ended
+	if numRows, err := dao.GdaoCreateCtx(staleCtx, dao.collectionName, (&MyBo{Id: "10", Username: "10"}).ToGbo()); err != godal.ErrTxEnded || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err := dao.GdaoFetchOneCtx(staleCtx, dao.collectionName, map[string]interface{}{fieldId: "1"}); err != godal.ErrTxEnded || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	err = dao.GdaoWithTransaction(staleCtx, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		t.Fatalf("%s failed - txFunc is not expected to be called", name)
+		return nil
+	})
+	if err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+
+	// the context of another dao's transaction does not join it
+	other := initDao()
+	err = other.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		_, err := dao.GdaoCreateCtx(txCtx, dao.collectionName, (&MyBo{Id: "10", Username: "10"}).ToGbo())
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 11 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoWithTransaction_Isolation(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoWithTransaction_Isolation"
+	dao := initDao()
+	bo := &MyBo{Id: "0", Username: "0", Name: "BO - 0"}
+	if _, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+
+	// calls made without txCtx from txFunc do not wait for the transaction and do not see its changes
+	err := dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		if _, err := txDao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil {
+			return err
+		}
+		if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 1 {
+			t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+		}
+		if count, err := txDao.GdaoCount(dao.collectionName, nil); err != nil || count != 2 {
+			t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 2 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+
+	// conflicting writes made outside the transaction fail the commit, which is retried under the retry policy
+	numCalls := 0
+	err = dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		if _, err := txDao.GdaoCreate(dao.collectionName, (&MyBo{Id: "2", Username: "2"}).ToGbo()); err != nil {
+			return err
+		}
+		_, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1" + strconv.Itoa(numCalls), Username: "1" + strconv.Itoa(numCalls)}).ToGbo())
+		return err
+	})
+	if !errors.Is(err, godal.ErrConcurrentModification) || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %v / Error: %e", name, numCalls, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "2"}); err != nil || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	numCalls = 0
+	dao.SetRetryPolicy(&godal.RetryPolicy{MaxAttempts: 3})
+	err = dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		if _, err := txDao.GdaoCreate(dao.collectionName, (&MyBo{Id: "2", Username: "2"}).ToGbo()); err != nil {
+			return err
+		}
+		if numCalls > 1 {
+			return nil
+		}
+		_, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "3", Username: "3"}).ToGbo())
+		return err
+	})
+	if err != nil || numCalls != 2 {
+		t.Fatalf("%s failed - NumCalls: %v / Error: %e", name, numCalls, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+}
+
+func TestGenericDaoMemory_GdaoWithTransaction_Panic(t *testing.T) {
+	name := "TestGenericDaoMemory_GdaoWithTransaction_Panic"
+	dao := initDao()
+	var staleCtx context.Context
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatalf("%s failed - panic expected", name)
+			}
+		}()
+		dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+			staleCtx = txCtx
+			if _, err := txDao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil {
+				return err
+			}
+			panic("txFunc panics")
+		})
+	}()
+
+	// changes are discarded, the transaction has ended and the dao is still usable
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 0 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+	if _, err := dao.GdaoCreateCtx(staleCtx, dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
@@ -117,14 +117,19 @@ import (
 	"errors"
 	"fmt"
 	"github.com/btnguyen2k/consu/reddo"
+	"github.com/btnguyen2k/consu/semita"
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/prom"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 /*
@@ -149,8 +154,7 @@ func (mapper *GenericRowMapperMongo) ToRow(collectionName string, bo godal.IGene
 	if bo == nil {
 		return nil, nil
 	}
-	result := make(map[string]interface{})
-	return result, bo.GboTransferViaJson(&result)
+	return bo.GboToMap()
 }
 
 /*
@@ -265,9 +269,19 @@ Function implementations (n = No, y = Yes, i = inherited):
 	(y) GdaoDeleteMany(storageId string, filter interface{}) (int, error)
 	(y) GdaoFetchOne(storageId string, filter interface{}) (godal.IGenericBo, error)
 	(y) GdaoFetchMany(storageId string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error)
+	(y) GdaoFetchIterator(storageId string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error)
+	(y) GdaoFetchPage(storageId string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error)
+	(y) GdaoCount(storageId string, filter interface{}) (int64, error)
+	(y) GdaoExists(storageId string, filter interface{}) (bool, error)
 	(y) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
 	(y) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error)
+	(y) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error)
 	(y) GdaoSave(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
+
+GenericDaoMongo also implements godal.IGenericDaoWithContext and godal.ITransactionalDao (since v0.3.0).
 */
 type GenericDaoMongo struct {
 	*godal.AbstractGenericDao
@@ -331,7 +345,12 @@ MongoDeleteMany performs a MongoDB's delete-many command on the specified collec
 	- filter: see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 */
 func (dao *GenericDaoMongo) MongoDeleteMany(ctx context.Context, collectionName string, filter map[string]interface{}) (*mongo.DeleteResult, error) {
-	return dao.GetMongoCollection(collectionName).DeleteMany(ctx, filter)
+	var result *mongo.DeleteResult
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		result, err = dao.GetMongoCollection(collectionName).DeleteMany(ctx, filter)
+		return dao.TranslateError(err)
+	})
+	return result, err
 }
 
 /*
@@ -341,7 +360,12 @@ MongoFetchOne performs a MongoDB's find-one command on the specified collection.
 	- filter: see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 */
 func (dao *GenericDaoMongo) MongoFetchOne(ctx context.Context, collectionName string, filter map[string]interface{}) *mongo.SingleResult {
-	return dao.GetMongoCollection(collectionName).FindOne(ctx, filter)
+	var result *mongo.SingleResult
+	dao.retry(ctx, func(ctx context.Context) error {
+		result = dao.GetMongoCollection(collectionName).FindOne(ctx, filter)
+		return result.Err()
+	})
+	return result
 }
 
 /*
@@ -350,10 +374,11 @@ MongoFetchMany performs a MongoDB's find command on the specified collection.
 	- ctx: can be used to pass a transaction down to the operation
 	- filter: see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 	- sorting: see MongoDB ascending/descending sort (https://docs.mongodb.com/manual/reference/method/cursor.sort/index.html#sort-asc-desc)
+	- sorting can be a map[string]int or a bson.D (since v0.3.0); use bson.D if sort order of multiple fields is significant
 */
-func (dao *GenericDaoMongo) MongoFetchMany(ctx context.Context, collectionName string, filter map[string]interface{}, sorting map[string]int, startOffset, numItems int) (*mongo.Cursor, error) {
+func (dao *GenericDaoMongo) MongoFetchMany(ctx context.Context, collectionName string, filter map[string]interface{}, sorting interface{}, startOffset, numItems int) (*mongo.Cursor, error) {
 	opt := &options.FindOptions{}
-	if sorting != nil && len(sorting) > 0 {
+	if v := reflect.ValueOf(sorting); sorting != nil && ((v.Kind() != reflect.Map && v.Kind() != reflect.Slice) || v.Len() > 0) {
 		opt.SetSort(sorting)
 	}
 	if numItems > 0 {
@@ -362,7 +387,38 @@ func (dao *GenericDaoMongo) MongoFetchMany(ctx context.Context, collectionName s
 	if startOffset > 0 {
 		opt.SetSkip(int64(startOffset))
 	}
-	return dao.GetMongoCollection(collectionName).Find(ctx, filter, opt)
+	var cursor *mongo.Cursor
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		cursor, err = dao.GetMongoCollection(collectionName).Find(ctx, filter, opt)
+		return dao.TranslateError(err)
+	})
+	return cursor, err
+}
+
+/*
+MongoCountDocuments performs a MongoDB's count-documents command on the specified collection.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- filter: see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
+	- limit: if positive, maximum number of documents to count
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) MongoCountDocuments(ctx context.Context, collectionName string, filter map[string]interface{}, limit int) (int64, error) {
+	opt := options.Count()
+	if limit > 0 {
+		opt.SetLimit(int64(limit))
+	}
+	if filter == nil {
+		// count-documents requires a non-nil document
+		filter = map[string]interface{}{}
+	}
+	var count int64
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		count, err = dao.GetMongoCollection(collectionName).CountDocuments(ctx, filter, opt)
+		return dao.TranslateError(err)
+	})
+	return count, err
 }
 
 /*
@@ -371,7 +427,12 @@ MongoInsertOne performs a MongoDB's insert-one command on the specified collecti
 	- ctx: can be used to pass a transaction down to the operation
 */
 func (dao *GenericDaoMongo) MongoInsertOne(ctx context.Context, collectionName string, doc interface{}) (*mongo.InsertOneResult, error) {
-	return dao.GetMongoCollection(collectionName).InsertOne(ctx, doc)
+	var result *mongo.InsertOneResult
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		result, err = dao.GetMongoCollection(collectionName).InsertOne(ctx, doc)
+		return dao.TranslateError(err)
+	})
+	return result, err
 }
 
 /*
@@ -383,7 +444,30 @@ MongoUpdateOne performs a MongoDB's find-one-and-replace command with 'upsert=fa
 func (dao *GenericDaoMongo) MongoUpdateOne(ctx context.Context, collectionName string, filter map[string]interface{}, doc interface{}) *mongo.SingleResult {
 	upsert := false
 	opt := options.FindOneAndReplaceOptions{Upsert: &upsert}
-	return dao.GetMongoCollection(collectionName).FindOneAndReplace(ctx, filter, doc, &opt)
+	var result *mongo.SingleResult
+	dao.retry(ctx, func(ctx context.Context) error {
+		result = dao.GetMongoCollection(collectionName).FindOneAndReplace(ctx, filter, doc, &opt)
+		return result.Err()
+	})
+	return result
+}
+
+/*
+MongoPatchOne performs a MongoDB's update-one command on the specified collection.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- filter: see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
+	- update: update document built with update operators (e.g. $set, $inc), see https://docs.mongodb.com/manual/reference/operator/update/
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) MongoPatchOne(ctx context.Context, collectionName string, filter map[string]interface{}, update interface{}) (*mongo.UpdateResult, error) {
+	var result *mongo.UpdateResult
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		result, err = dao.GetMongoCollection(collectionName).UpdateOne(ctx, filter, update)
+		return dao.TranslateError(err)
+	})
+	return result, err
 }
 
 /*
@@ -395,16 +479,111 @@ MongoSaveOne performs a MongoDB's find-one-and-replace command with 'upsert=true
 func (dao *GenericDaoMongo) MongoSaveOne(ctx context.Context, collectionName string, filter map[string]interface{}, doc interface{}) *mongo.SingleResult {
 	upsert := true
 	opt := options.FindOneAndReplaceOptions{Upsert: &upsert}
-	return dao.GetMongoCollection(collectionName).FindOneAndReplace(ctx, filter, doc, &opt)
+	var result *mongo.SingleResult
+	dao.retry(ctx, func(ctx context.Context) error {
+		result = dao.GetMongoCollection(collectionName).FindOneAndReplace(ctx, filter, doc, &opt)
+		return result.Err()
+	})
+	return result
+
+}
+
+/*
+MongoBulkWrite performs a MongoDB's unordered bulk-write command on the specified collection.
+
+	- ctx: can be used to pass a transaction down to the operation
 
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) MongoBulkWrite(ctx context.Context, collectionName string, models []mongo.WriteModel) (*mongo.BulkWriteResult, error) {
+	var result *mongo.BulkWriteResult
+	err := dao.retry(ctx, func(ctx context.Context) (err error) {
+		result, err = dao.GetMongoCollection(collectionName).BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+		return dao.TranslateError(err)
+	})
+	return result, err
 }
 
 /*----------------------------------------------------------------------*/
 
+var mongoFilterOps = map[godal.FilterOperator]string{
+	godal.FilterOpEq:  "$eq",
+	godal.FilterOpGt:  "$gt",
+	godal.FilterOpGte: "$gte",
+	godal.FilterOpLt:  "$lt",
+	godal.FilterOpLte: "$lte",
+}
+
+// filterOptToMap translates a godal.FilterOpt to MongoDB query selector.
+func filterOptToMap(f *godal.FilterOpt) (map[string]interface{}, error) {
+	if f == nil {
+		return nil, errors.New("nil filter")
+	}
+	switch f.Operator {
+	case godal.FilterOpNe:
+		// {field: {$ne: value}} also matches documents where field is null or does not exist, hence $nin is used instead
+		return map[string]interface{}{f.Field: map[string]interface{}{"$nin": []interface{}{f.Value, nil}}}, nil
+	case godal.FilterOpEq, godal.FilterOpGt, godal.FilterOpGte, godal.FilterOpLt, godal.FilterOpLte:
+		return map[string]interface{}{f.Field: map[string]interface{}{mongoFilterOps[f.Operator]: f.Value}}, nil
+	case godal.FilterOpIn:
+		if len(f.Values) == 0 {
+			return nil, errors.New("filter IN requires at least one value")
+		}
+		return map[string]interface{}{f.Field: map[string]interface{}{"$in": f.Values}}, nil
+	case godal.FilterOpBetween:
+		if len(f.Values) != 2 {
+			return nil, errors.New("filter BETWEEN requires exactly two values")
+		}
+		return map[string]interface{}{f.Field: map[string]interface{}{"$gte": f.Values[0], "$lte": f.Values[1]}}, nil
+	case godal.FilterOpPrefix:
+		prefix, err := reddo.ToString(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{f.Field: map[string]interface{}{"$regex": "^" + regexp.QuoteMeta(prefix)}}, nil
+	case godal.FilterOpIsNull:
+		// {field: null} matches documents where field is null or does not exist
+		return map[string]interface{}{f.Field: nil}, nil
+	case godal.FilterOpExists:
+		return map[string]interface{}{f.Field: map[string]interface{}{"$ne": nil}}, nil
+	case godal.FilterOpAnd, godal.FilterOpOr, godal.FilterOpNot:
+		if len(f.Filters) == 0 {
+			return nil, errors.New("filter AND/OR/NOT requires at least one sub-filter")
+		}
+		filters := make([]interface{}, len(f.Filters))
+		for i, sub := range f.Filters {
+			var err error
+			if filters[i], err = filterOptToMap(sub); err != nil {
+				return nil, err
+			}
+		}
+		switch f.Operator {
+		case godal.FilterOpAnd:
+			return map[string]interface{}{"$and": filters}, nil
+		case godal.FilterOpOr:
+			return map[string]interface{}{"$or": filters}, nil
+		}
+		if len(filters) != 1 {
+			return nil, errors.New("filter NOT requires exactly one sub-filter")
+		}
+		return map[string]interface{}{"$nor": filters}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("unsupported filter operator %d", f.Operator))
+}
+
 func toMap(input interface{}) (map[string]interface{}, error) {
 	if input == nil {
 		return nil, nil
 	}
+	switch f := input.(type) {
+	case *godal.FilterOpt:
+		if f == nil {
+			return nil, nil
+		}
+		return filterOptToMap(f)
+	case godal.FilterOpt:
+		return filterOptToMap(&f)
+	}
 	v := reflect.ValueOf(input)
 	for v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -433,6 +612,32 @@ func toMap(input interface{}) (map[string]interface{}, error) {
 	return nil, errors.New(fmt.Sprintf("cannot convert %v to map[string]interface{}", input))
 }
 
+// toSorting converts the input to MongoDB sort specification: a godal.SortingOpt is converted to bson.D, others are converted to map[string]int.
+func toSorting(input interface{}) (interface{}, error) {
+	switch o := input.(type) {
+	case *godal.SortingOpt:
+		if o == nil {
+			return nil, nil
+		}
+		return sortingOptToBsonD(o), nil
+	case godal.SortingOpt:
+		return sortingOptToBsonD(&o), nil
+	}
+	return toSortingMap(input)
+}
+
+func sortingOptToBsonD(o *godal.SortingOpt) bson.D {
+	result := bson.D{}
+	for _, f := range o.Fields {
+		order := 1
+		if f.Descending() {
+			order = -1
+		}
+		result = append(result, bson.E{Key: f.Field, Value: order})
+	}
+	return result
+}
+
 func toSortingMap(input interface{}) (map[string]int, error) {
 	if input == nil {
 		return nil, nil
@@ -481,15 +686,25 @@ GdaoDeleteWithContext is extended-implementation of godal.IGenericDao.GdaoDelete
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoMongo) GdaoDeleteWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+func (dao *GenericDaoMongo) GdaoDeleteWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDelete", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeDelete, collectionName, bo); err != nil {
+		return 0, err
+	}
 	filter := dao.GdaoCreateFilter(collectionName, bo)
-	return dao.GdaoDeleteManyWithContext(ctx, collectionName, filter)
+	numRows, err = dao.GdaoDeleteManyWithContext(ctx, collectionName, filter)
+	return dao.AfterWrite(ctx, godal.HookAfterDelete, collectionName, bo, numRows, err)
 }
 
 /*
 GdaoDeleteMany implements godal.IGenericDao.GdaoDeleteMany.
 
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 */
 func (dao *GenericDaoMongo) GdaoDeleteMany(collectionName string, filter interface{}) (int, error) {
@@ -501,11 +716,17 @@ GdaoDeleteManyWithContext is extended-implementation of godal.IGenericDao.GdaoDe
 
 	- ctx: can be used to pass a transaction down to the operation
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoMongo) GdaoDeleteManyWithContext(ctx context.Context, collectionName string, filter interface{}) (int, error) {
+func (dao *GenericDaoMongo) GdaoDeleteManyWithContext(ctx context.Context, collectionName string, filter interface{}) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDeleteMany", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
 	if f, err := toMap(filter); err != nil {
 		return 0, err
 	} else {
@@ -524,6 +745,7 @@ func (dao *GenericDaoMongo) GdaoDeleteManyWithContext(ctx context.Context, colle
 GdaoFetchOne implements godal.IGenericDao.GdaoFetchOne.
 
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 */
 func (dao *GenericDaoMongo) GdaoFetchOne(collectionName string, filter interface{}) (godal.IGenericBo, error) {
@@ -535,11 +757,23 @@ GdaoFetchOneWithContext is extended-implementation of godal.IGenericDao.GdaoFetc
 
 	- ctx: can be used to pass a transaction down to the operation
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoMongo) GdaoFetchOneWithContext(ctx context.Context, collectionName string, filter interface{}) (godal.IGenericBo, error) {
+func (dao *GenericDaoMongo) GdaoFetchOneWithContext(ctx context.Context, collectionName string, filter interface{}) (bo godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchOne", collectionName)
+	defer func() {
+		if bo != nil {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, err
+	}
 	if f, err := toMap(filter); err != nil {
 		return nil, err
 	} else {
@@ -548,9 +782,9 @@ func (dao *GenericDaoMongo) GdaoFetchOneWithContext(ctx context.Context, collect
 		}
 		dbResult := dao.MongoFetchOne(ctx, collectionName, f)
 		if jsData, err := dao.mongoConnect.DecodeSingleResultRaw(dbResult); err != nil || jsData == nil {
-			return nil, err
+			return nil, dao.TranslateError(err)
 		} else {
-			return dao.GetRowMapper().ToBo(collectionName, jsData)
+			return dao.RowToBo(ctx, collectionName, jsData)
 		}
 	}
 }
@@ -559,9 +793,11 @@ func (dao *GenericDaoMongo) GdaoFetchOneWithContext(ctx context.Context, collect
 GdaoFetchMany implements godal.IGenericDao.GdaoFetchMany.
 
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- nil filter means "match all"
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 	- sorting should be a map[string]int, or it can be a string/[]byte representing map[string]int in JSON, then it is unmarshalled to map[string]int
+	- sorting can also be a godal.SortingOpt, which keeps the order of sorting fields (since v0.3.0)
 	- see MongoDB ascending/descending sort (https://docs.mongodb.com/manual/reference/method/cursor.sort/index.html#sort-asc-desc)
 */
 func (dao *GenericDaoMongo) GdaoFetchMany(collectionName string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
@@ -573,19 +809,26 @@ GdaoFetchManyWithContext is extended-implementation of godal.IGenericDao.GdaoFet
 
 	- ctx: can be used to pass a transaction down to the operation
 	- filter should be a map[string]interface{}, or it can be a string/[]byte representing map[string]interface{} in JSON, then it is unmarshalled to map[string]interface{}
+	- filter can also be a godal.FilterOpt, which is translated to MongoDB query selector (since v0.3.0)
 	- nil filter means "match all"
 	- see MongoDB query selector (https://docs.mongodb.com/manual/reference/operator/query/#query-selectors)
 	- sorting should be a map[string]int, or it can be a string/[]byte representing map[string]int in JSON, then it is unmarshalled to map[string]int
+	- sorting can also be a godal.SortingOpt, which keeps the order of sorting fields (since v0.3.0)
 	- see MongoDB ascending/descending sort (https://docs.mongodb.com/manual/reference/method/cursor.sort/index.html#sort-asc-desc)
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoMongo) GdaoFetchManyWithContext(ctx context.Context, collectionName string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
+func (dao *GenericDaoMongo) GdaoFetchManyWithContext(ctx context.Context, collectionName string, filter interface{}, sorting interface{}, startOffset, numItems int) (boList []godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchMany", collectionName)
+	defer func() { op.Finish(len(boList), err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, err
+	}
 	f, err := toMap(filter)
 	if err != nil {
 		return nil, err
 	}
-	s, err := toSortingMap(sorting)
+	s, err := toSorting(sorting)
 	if err != nil {
 		return nil, err
 	}
@@ -603,10 +846,10 @@ func (dao *GenericDaoMongo) GdaoFetchManyWithContext(ctx context.Context, collec
 	var resultError error = nil
 	dao.mongoConnect.DecodeResultCallbackRaw(ctx, cursor, func(docNum int, doc []byte, err error) bool {
 		if err != nil {
-			resultError = err
+			resultError = dao.TranslateError(err)
 			return false
 		} else {
-			bo, e := dao.GetRowMapper().ToBo(collectionName, doc)
+			bo, e := dao.RowToBo(ctx, collectionName, doc)
 			if e != nil {
 				resultError = e
 				return false
@@ -619,177 +862,1229 @@ func (dao *GenericDaoMongo) GdaoFetchManyWithContext(ctx context.Context, collec
 	return resultBoList, resultError
 }
 
-func isErrorDuplicatedKey(err error) bool {
-	if err == nil {
-		return false
-	}
-	return regexp.MustCompile(`\WE11000\W`).FindString(err.Error()) != ""
-}
+/*
+GdaoFetchIterator implements godal.IGenericDao.GdaoFetchIterator.
 
-func (dao *GenericDaoMongo) insertIfNotExist(ctx context.Context, collectionName string, bo godal.IGenericBo) (bool, error) {
-	// first fetch existing document from storage
-	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
-	if err != nil {
-		return false, err
-	}
-	row := dao.MongoFetchOne(ctx, collectionName, filter)
-	if jsData, err := dao.mongoConnect.DecodeSingleResultRaw(row); err != nil || jsData != nil {
-		if err != nil {
-			return false, err
-		} else {
-			return false, godal.GdaoErrorDuplicatedEntry
-		}
-	}
+	- filter and sorting: see GdaoFetchMany.
 
-	// insert new document
-	if doc, err := dao.GetRowMapper().ToRow(collectionName, bo); err != nil {
-		return false, err
-	} else if _, err = dao.MongoInsertOne(ctx, collectionName, doc); err != nil {
-		return false, err
-	}
-	return true, nil
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoFetchIterator(collectionName string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithContext(nil, collectionName, filter, sorting)
 }
 
 /*
-WrapTransaction wraps a function inside a transaction.
+GdaoFetchIteratorWithContext is extended-implementation of godal.IGenericDao.GdaoFetchIterator.
 
-	- txFunc: the function to wrap. If the function returns error, the transaction will be aborted, otherwise transaction is committed.
+	- ctx: can be used to pass a transaction down to the operation. If ctx is nil, a context without deadline is used
+	  (the default timeout is not applied as the iteration can take long).
+	- filter and sorting: see GdaoFetchMany.
+	- the returned iterator is backed by the *mongo.Cursor of the query: documents are fetched from database in batches and transformed to BOs lazily.
 
-Available: since v0.0.4
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) WrapTransaction(ctx context.Context, txFunc func(sctx mongo.SessionContext) error) error {
+func (dao *GenericDaoMongo) GdaoFetchIteratorWithContext(ctx context.Context, collectionName string, filter interface{}, sorting interface{}) (it godal.IGenericBoIterator, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchIterator", collectionName)
+	defer func() { op.Finish(0, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, err
+	}
+	f, err := toMap(filter)
+	if err != nil {
+		return nil, err
+	}
+	s, err := toSorting(sorting)
+	if err != nil {
+		return nil, err
+	}
 	if ctx == nil {
-		ctx, _ = dao.mongoConnect.NewContext()
+		ctx = context.Background()
 	}
-	// UseSession will close open session and pending transaction
-	return dao.mongoConnect.GetMongoClient().UseSession(ctx, func(sctx mongo.SessionContext) error {
-		if err := sctx.StartTransaction(options.Transaction().
-			SetReadConcern(readconcern.Snapshot()).
-			SetWriteConcern(writeconcern.New(writeconcern.WMajority()))); err != nil {
-			return err
+	cursor, err := dao.MongoFetchMany(ctx, collectionName, f, s, 0, 0)
+	if err != nil {
+		if cursor != nil {
+			_ = cursor.Close(ctx)
 		}
-		if err := txFunc(sctx); err != nil {
-			sctx.AbortTransaction(sctx)
-			return err
+		return nil, err
+	}
+	return &mongoBoIterator{ctx: ctx, dao: dao, collectionName: collectionName, cursor: cursor}, nil
+}
+
+// mongoBoIterator implements godal.IGenericBoIterator.
+type mongoBoIterator struct {
+	ctx            context.Context
+	dao            *GenericDaoMongo
+	collectionName string
+	cursor         *mongo.Cursor
+	closed         bool
+	bo             godal.IGenericBo
+	err            error
+}
+
+// Next implements godal.IGenericBoIterator.Next.
+func (it *mongoBoIterator) Next() bool {
+	it.bo = nil
+	if it.err != nil || it.closed {
+		return false
+	}
+	// the callback returns false after the first document, hence exactly one document is consumed from the cursor
+	it.dao.mongoConnect.DecodeResultCallbackRaw(it.ctx, it.cursor, func(docNum int, doc []byte, err error) bool {
+		if it.err = it.dao.TranslateError(err); err == nil {
+			it.bo, it.err = it.dao.RowToBo(it.ctx, it.collectionName, doc)
 		}
-		return sctx.CommitTransaction(sctx)
+		return false
 	})
+	if it.bo == nil && it.err == nil {
+		it.err = it.dao.TranslateError(it.cursor.Err())
+	}
+	return it.bo != nil && it.err == nil
+}
+
+// Bo implements godal.IGenericBoIterator.Bo.
+func (it *mongoBoIterator) Bo() godal.IGenericBo {
+	return it.bo
+}
+
+// Err implements godal.IGenericBoIterator.Err.
+func (it *mongoBoIterator) Err() error {
+	return it.err
+}
+
+// Close implements godal.IGenericBoIterator.Close.
+func (it *mongoBoIterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	return it.cursor.Close(it.ctx)
 }
 
 /*
-GdaoCreate implements godal.IGenericDao.GdaoCreate.
+GdaoFetchPage implements godal.IGenericDao.GdaoFetchPage.
+
+	- filter and sorting: see GdaoFetchPageWithContext.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoCreate(collectionName string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoCreateWithContext(nil, collectionName, bo)
+func (dao *GenericDaoMongo) GdaoFetchPage(collectionName string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithContext(nil, collectionName, filter, sorting, pageToken, numItems)
 }
 
 /*
-GdaoCreateWithContext is extended-implementation of godal.IGenericDao.GdaoCreate.
+GdaoFetchPageWithContext is extended-implementation of godal.IGenericDao.GdaoFetchPage.
 
 	- ctx: can be used to pass a transaction down to the operation
+	- filter: see GdaoFetchMany.
+	- keyset pagination is used (see godal.KeysetPaging): sorting must be a godal.SortingOpt whose fields combined are unique
+	  (e.g. end the sorting with "_id"), and the page token carries the values of these fields of the last document of the previous page.
+	- the next page is fetched with {"$and": [<filter>, <document comes after the last document of the previous page>]}, which can use an index on the sorting fields.
 
-Available: since v0.1.0
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoCreateWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
-	if ctx == nil {
-		ctx, _ = dao.mongoConnect.NewContext()
+func (dao *GenericDaoMongo) GdaoFetchPageWithContext(ctx context.Context, collectionName string, filter interface{}, sorting interface{}, pageToken string, numItems int) (boList []godal.IGenericBo, nextPageToken string, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchPage", collectionName)
+	defer func() { op.Finish(len(boList), err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, "", err
 	}
-	if dao.txModeOnWrite {
-		numRows := 0
-		err := dao.WrapTransaction(ctx, func(sctx mongo.SessionContext) error {
-			if result, err := dao.insertIfNotExist(sctx, collectionName, bo); err != nil {
-				return err
-			} else if result {
-				numRows = 1
-			}
-			return nil
-		})
-		if isErrorDuplicatedKey(err) {
-			return 0, godal.GdaoErrorDuplicatedEntry
+	paging, err := godal.NewKeysetPaging(sorting, pageToken)
+	if err != nil {
+		return nil, "", err
+	}
+	f, err := toMap(filter)
+	if err != nil {
+		return nil, "", err
+	}
+	if kf := paging.Filter(); kf != nil {
+		keysetFilter, err := filterOptToMap(kf)
+		if err != nil {
+			return nil, "", err
 		}
-		return numRows, err
-	} else {
-		if result, err := dao.insertIfNotExist(ctx, collectionName, bo); err != nil {
-			if isErrorDuplicatedKey(err) {
-				return 0, godal.GdaoErrorDuplicatedEntry
-			}
-			return 0, err
-		} else if result {
-			return 1, nil
+		if len(f) == 0 {
+			f = keysetFilter
+		} else {
+			f = map[string]interface{}{"$and": []interface{}{f, keysetFilter}}
 		}
-		return 0, nil
 	}
+	limit := numItems
+	if numItems > 0 {
+		// fetch one more document to know if there is a next page
+		limit++
+	}
+	boList, err = dao.GdaoFetchManyWithContext(ctx, collectionName, f, paging.Sorting, 0, limit)
+	if err != nil || numItems <= 0 || len(boList) <= numItems {
+		return boList, "", err
+	}
+	boList = boList[:numItems]
+	row, err := dao.GetRowMapper().ToRow(collectionName, boList[numItems-1])
+	if err != nil {
+		return nil, "", err
+	}
+	rowMap, ok := row.(map[string]interface{})
+	if !ok {
+		return nil, "", errors.New(fmt.Sprintf("cannot build page token from document %v", row))
+	}
+	nextPageToken, err = paging.NextPageToken(rowMap)
+	if err != nil {
+		return nil, "", err
+	}
+	return boList, nextPageToken, nil
 }
 
 /*
-GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+GdaoCount implements godal.IGenericDao.GdaoCount.
+
+	- filter: see GdaoFetchMany.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoUpdate(collectionName string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoUpdateWithContext(nil, collectionName, bo)
+func (dao *GenericDaoMongo) GdaoCount(collectionName string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithContext(nil, collectionName, filter)
 }
 
 /*
-GdaoUpdateWithContext is extended-implementation of godal.IGenericDao.GdaoUpdate.
+GdaoCountWithContext is extended-implementation of godal.IGenericDao.GdaoCount.
 
 	- ctx: can be used to pass a transaction down to the operation
+	- filter: see GdaoFetchMany.
 
-Available: since v0.1.0
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoUpdateWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
-	if ctx == nil {
-		ctx, _ = dao.mongoConnect.NewContext()
-	}
-	doc, err := dao.GetRowMapper().ToRow(collectionName, bo)
-	if err != nil {
+func (dao *GenericDaoMongo) GdaoCountWithContext(ctx context.Context, collectionName string, filter interface{}) (count int64, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCount", collectionName)
+	defer func() { op.Finish(int(count), err) }()
+	if err := dao.checkTx(ctx); err != nil {
 		return 0, err
 	}
-	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	f, err := toMap(filter)
 	if err != nil {
 		return 0, err
 	}
-	result := dao.MongoUpdateOne(ctx, collectionName, filter, doc)
-	if _, err := result.DecodeBytes(); err == mongo.ErrNoDocuments {
-		return 0, nil
-	} else if isErrorDuplicatedKey(err) {
-		return 0, godal.GdaoErrorDuplicatedEntry
-	} else {
-		return 1, err
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
 	}
+	return dao.MongoCountDocuments(ctx, collectionName, f, 0)
 }
 
 /*
-GdaoSave implements godal.IGenericDao.GdaoSave.
+GdaoExists implements godal.IGenericDao.GdaoExists.
+
+	- filter: see GdaoFetchMany.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoSave(collectionName string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoSaveWithContext(nil, collectionName, bo)
+func (dao *GenericDaoMongo) GdaoExists(collectionName string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithContext(nil, collectionName, filter)
 }
 
 /*
-GdaoSaveWithContext is extended-implementation of godal.IGenericDao.GdaoSave.
+GdaoExistsWithContext is extended-implementation of godal.IGenericDao.GdaoExists.
 
 	- ctx: can be used to pass a transaction down to the operation
+	- filter: see GdaoFetchMany.
 
-Available: since v0.1.0
+Available: since v0.3.0
 */
-func (dao *GenericDaoMongo) GdaoSaveWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+func (dao *GenericDaoMongo) GdaoExistsWithContext(ctx context.Context, collectionName string, filter interface{}) (exists bool, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoExists", collectionName)
+	defer func() {
+		if exists {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	if err := dao.checkTx(ctx); err != nil {
+		return false, err
+	}
+	f, err := toMap(filter)
+	if err != nil {
+		return false, err
+	}
 	if ctx == nil {
 		ctx, _ = dao.mongoConnect.NewContext()
 	}
-	doc, err := dao.GetRowMapper().ToRow(collectionName, bo)
-	if err != nil {
-		return 0, err
+	count, err := dao.MongoCountDocuments(ctx, collectionName, f, 1)
+	return count > 0, err
+}
+
+var (
+	// MongoDB error codes, see https://github.com/mongodb/mongo/blob/master/src/mongo/base/error_codes.yml
+	mongoErrorKinds = map[int]error{
+		11000: godal.ErrConflict,               // DuplicateKey
+		11001: godal.ErrConflict,               // DuplicateKey (legacy)
+		121:   godal.ErrConstraintViolation,    // DocumentValidationFailure
+		112:   godal.ErrConcurrentModification, // WriteConflict
+		50:    godal.ErrTimeout,                // MaxTimeMSExpired
+		24:    godal.ErrTimeout,                // LockTimeout
+		26:    godal.ErrNotFound,               // NamespaceNotFound
+		16500: godal.ErrThrottled,              // RequestRateTooLarge (Azure Cosmos DB's API for MongoDB)
+	}
+	reMongoErrorCode = regexp.MustCompile(`\WE(11000|11001)\W`) // duplicate key errors not carrying an error code
+)
+
+// mongoErrorCode extracts the MongoDB error code from err, 0 if not found. Bulk-write exceptions are not looked into as they carry
+// one error per write.
+func mongoErrorCode(err error) int {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch v := e.(type) {
+		case mongo.CommandError:
+			return int(v.Code)
+		case *mongo.CommandError:
+			return int(v.Code)
+		case mongo.WriteError:
+			return v.Code
+		case *mongo.WriteError:
+			return v.Code
+		case mongo.WriteException:
+			return writeExceptionCode(&v)
+		case *mongo.WriteException:
+			return writeExceptionCode(v)
+		case mongo.BulkWriteException, *mongo.BulkWriteException:
+			return 0
+		}
 	}
-	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
 	if err != nil {
-		return 0, err
+		if m := reMongoErrorCode.FindStringSubmatch(" " + err.Error() + " "); m != nil {
+			code, _ := strconv.Atoi(m[1])
+			return code
+		}
 	}
-	result := dao.MongoSaveOne(ctx, collectionName, filter, doc)
-	if err = result.Err(); err == nil || err == mongo.ErrNoDocuments {
-		return 1, nil
-	} else {
-		if isErrorDuplicatedKey(err) {
-			return 0, godal.GdaoErrorDuplicatedEntry
+	return 0
+}
+
+// asBulkWriteException returns the bulk-write exception wrapped in err, nil if not found.
+func asBulkWriteException(err error) *mongo.BulkWriteException {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch v := e.(type) {
+		case mongo.BulkWriteException:
+			return &v
+		case *mongo.BulkWriteException:
+			return v
 		}
-		return 1, err
 	}
+	return nil
+}
+
+func writeExceptionCode(e *mongo.WriteException) int {
+	if len(e.WriteErrors) > 0 {
+		return e.WriteErrors[0].Code
+	}
+	if e.WriteConcernError != nil {
+		return e.WriteConcernError.Code
+	}
+	return 0
+}
+
+/*
+TranslateError maps a MongoDB error to godal's error kinds (godal.ErrConflict, godal.ErrTimeout, etc) by wrapping it into a
+*godal.DaoError; errors of unknown kinds are returned as-is.
+
+	- 11000/11001 (duplicate key): godal.ErrConflict
+	- 121 (document validation failure): godal.ErrConstraintViolation
+	- 112 (write conflict): godal.ErrConcurrentModification
+	- 50 (max-time-ms expired), 24 (lock timeout) and context.DeadlineExceeded: godal.ErrTimeout
+	- 16500 (request rate too large, Azure Cosmos DB): godal.ErrThrottled
+	- 26 (namespace not found): godal.ErrNotFound
+
+Errors returned by MongoXxx functions and GdaoXxx functions are translated. Errors of bulk writes are translated per BO (see
+GdaoCreateMany).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) TranslateError(err error) error {
+	var daoErr *godal.DaoError
+	if err == nil || errors.As(err, &daoErr) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return godal.WrapError(godal.ErrTimeout, err)
+	}
+	return godal.WrapError(mongoErrorKinds[mongoErrorCode(err)], err)
+}
+
+/*
+IsRetryableError is the classifier of retryable errors used with the DAO's retry policy (see godal.AbstractGenericDao.SetRetryPolicy):
+write conflicts, throttling errors (see TranslateError and godal.IsRetryableError) and errors labelled "TransientTransactionError".
+
+MongoXxx functions called outside of a transaction, and WrapTransaction, are retried according to the DAO's retry policy.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) IsRetryableError(err error) bool {
+	if godal.IsRetryableError(dao.TranslateError(err)) {
+		return true
+	}
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		switch v := e.(type) {
+		case mongo.CommandError:
+			return v.HasErrorLabel("TransientTransactionError")
+		case *mongo.CommandError:
+			return v.HasErrorLabel("TransientTransactionError")
+		}
+	}
+	return false
+}
+
+// startOp reports the start of a Gdao* call to the DAO's instrumentation, see godal.AbstractGenericDao.StartOp.
+// If ctx is a mongo.SessionContext, so is the returned context.
+func (dao *GenericDaoMongo) startOp(ctx context.Context, operation, collectionName string) (context.Context, *godal.DaoOperation) {
+	opCtx, op := dao.StartOp(ctx, &godal.DaoOperation{Backend: "mongo", Operation: operation, StorageId: collectionName})
+	if sctx, ok := ctx.(mongo.SessionContext); ok && op != nil {
+		return &sessionContext{Context: opCtx, Session: sctx}, op
+	}
+	return opCtx, op
+}
+
+// sessionContext binds a session to a context derived from a mongo.SessionContext.
+type sessionContext struct {
+	context.Context
+	mongo.Session
+}
+
+// retry calls fn under the DAO's retry policy, unless ctx is carrying a session: calls made inside a transaction are not retried
+// individually. The session of another DAO's transaction carried by ctx is not used: fn is called with a context detached from it.
+func (dao *GenericDaoMongo) retry(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx := txFromContext(ctx); tx != nil && tx.dao != dao {
+		ctx = detachSession(ctx)
+	} else if _, ok := ctx.(mongo.SessionContext); ok || tx != nil {
+		return fn(ctx)
+	}
+	return dao.WithRetry(ctx, dao.IsRetryableError, func() error {
+		return fn(ctx)
+	})
+}
+
+func isErrorDuplicatedKey(err error) bool {
+	return errors.Is(err, godal.ErrConflict) || mongoErrorCode(err) == 11000
+}
+
+func (dao *GenericDaoMongo) insertIfNotExist(ctx context.Context, collectionName string, bo godal.IGenericBo) (bool, error) {
+	// first fetch existing document from storage
+	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	if err != nil {
+		return false, err
+	}
+	row := dao.MongoFetchOne(ctx, collectionName, filter)
+	if jsData, err := dao.mongoConnect.DecodeSingleResultRaw(row); err != nil || jsData != nil {
+		if err != nil {
+			return false, dao.TranslateError(err)
+		} else {
+			return false, godal.GdaoErrorDuplicatedEntry
+		}
+	}
+
+	// insert new document
+	if doc, err := dao.GetRowMapper().ToRow(collectionName, bo); err != nil {
+		return false, err
+	} else if _, err = dao.MongoInsertOne(ctx, collectionName, doc); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+/*
+WrapTransaction wraps a function inside a transaction.
+
+	- txFunc: the function to wrap. If the function returns error, the transaction will be aborted, otherwise transaction is committed.
+	- if ctx is carrying a transaction started by the dao's GdaoWithTransaction/WrapTransaction, txFunc joins that transaction (no new
+	  transaction is started); godal.ErrTxEnded is returned if that transaction has ended.
+	- if the transaction fails with a retryable error, a new transaction is started and txFunc is re-run according to the DAO's retry
+	  policy (see godal.AbstractGenericDao.SetRetryPolicy, since v0.3.0); txFunc should have no side effects outside the transaction.
+
+Available: since v0.0.4
+*/
+func (dao *GenericDaoMongo) WrapTransaction(ctx context.Context, txFunc func(sctx mongo.SessionContext) error) error {
+	if tx, err := dao.txFromContext(ctx); err != nil {
+		return err
+	} else if tx != nil {
+		return mongo.WithSession(ctx, tx.session, txFunc)
+	}
+	return dao.retry(ctx, func(ctx context.Context) error {
+		return dao.wrapTransaction(ctx, txFunc)
+	})
+}
+
+// wrapTransaction runs txFunc inside a new transaction, see WrapTransaction.
+func (dao *GenericDaoMongo) wrapTransaction(ctx context.Context, txFunc func(sctx mongo.SessionContext) error) error {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	// UseSession will close open session and pending transaction
+	return dao.mongoConnect.GetMongoClient().UseSession(ctx, func(sctx mongo.SessionContext) error {
+		if err := sctx.StartTransaction(options.Transaction().
+			SetReadConcern(readconcern.Snapshot()).
+			SetWriteConcern(writeconcern.New(writeconcern.WMajority()))); err != nil {
+			return dao.TranslateError(err)
+		}
+		tx := &mongoTx{dao: dao, session: sctx}
+		err := func() error {
+			defer tx.end()
+			return mongo.WithSession(context.WithValue(sctx, ctxKeyTx{}, tx), sctx, txFunc)
+		}()
+		if err != nil {
+			sctx.AbortTransaction(sctx)
+			return err
+		}
+		return dao.TranslateError(sctx.CommitTransaction(sctx))
+	})
+}
+
+type ctxKeyTx struct{}
+
+// mongoTx is a transaction started by WrapTransaction, carried by the context passed to txFunc.
+type mongoTx struct {
+	dao     *GenericDaoMongo
+	session mongo.Session
+	lock    sync.RWMutex
+	ended   bool // txFunc has returned, the transaction is being (or has been) committed or aborted
+}
+
+// end marks the transaction as ended.
+func (tx *mongoTx) end() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.ended = true
+}
+
+// txFromContext returns the transaction carried by ctx (of any DAO), nil if ctx is not carrying any.
+func txFromContext(ctx context.Context) *mongoTx {
+	if ctx == nil {
+		return nil
+	}
+	tx, _ := ctx.Value(ctxKeyTx{}).(*mongoTx)
+	return tx
+}
+
+// txFromContext returns the transaction of this dao carried by ctx, nil if ctx is not carrying any; godal.ErrTxEnded is returned if
+// the transaction has ended.
+func (dao *GenericDaoMongo) txFromContext(ctx context.Context) (*mongoTx, error) {
+	tx := txFromContext(ctx)
+	if tx == nil || tx.dao != dao {
+		return nil, nil
+	}
+	tx.lock.RLock()
+	defer tx.lock.RUnlock()
+	if tx.ended {
+		return nil, godal.ErrTxEnded
+	}
+	return tx, nil
+}
+
+// checkTx returns godal.ErrTxEnded if ctx is carrying a transaction of this dao that has ended.
+func (dao *GenericDaoMongo) checkTx(ctx context.Context) error {
+	_, err := dao.txFromContext(ctx)
+	return err
+}
+
+// detachSession returns a context derived from ctx that does not carry any session: operations made with it do not join the
+// transaction of the session.
+func detachSession(ctx context.Context) context.Context {
+	mongo.WithSession(ctx, nil, func(sctx mongo.SessionContext) error {
+		ctx = sctx
+		return nil
+	})
+	return ctx
+}
+
+// boundDao returns the DAO (the outer one if possible) bound to ctx.
+func (dao *GenericDaoMongo) boundDao(ctx context.Context) godal.IGenericDao {
+	if gdao, ok := dao.IGenericDao.(godal.IGenericDaoWithContext); ok {
+		return godal.NewContextBoundGenericDao(ctx, gdao)
+	}
+	return godal.NewContextBoundGenericDao(ctx, dao)
+}
+
+/*
+GdaoWithTransaction implements godal.ITransactionalDao.GdaoWithTransaction.
+
+	- txCtx is a mongo.SessionContext, all Gdao* calls made via the 'dao' passed to txFunc, as well as GdaoXxxCtx/GdaoXxxWithContext calls made with txCtx, join the transaction.
+	- as of MongoDB 4.0, transactions are available for replica set deployments only. Since MongoDB 4.2, transactions are also available for sharded cluster.
+	- the whole txFunc is re-run on retryable errors, see WrapTransaction.
+	- contexts carrying a transaction of another DAO do not join it (its session is not used); once txFunc returns, operations made with
+	  txCtx return godal.ErrTxEnded.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoWithTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao godal.IGenericDao) error) error {
+	return dao.WrapTransaction(ctx, func(sctx mongo.SessionContext) error {
+		return txFunc(sctx, dao.boundDao(sctx))
+	})
+}
+
+/*
+GdaoCreate implements godal.IGenericDao.GdaoCreate.
+*/
+func (dao *GenericDaoMongo) GdaoCreate(collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithContext(nil, collectionName, bo)
+}
+
+/*
+GdaoCreateWithContext is extended-implementation of godal.IGenericDao.GdaoCreate.
+
+	- ctx: can be used to pass a transaction down to the operation
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoMongo) GdaoCreateWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreate", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, collectionName, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.createWithContext(ctx, collectionName, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterCreate, collectionName, bo, numRows, err)
+}
+
+// createWithContext inserts a new document, see GdaoCreateWithContext.
+func (dao *GenericDaoMongo) createWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	if dao.txModeOnWrite {
+		numRows := 0
+		err := dao.WrapTransaction(ctx, func(sctx mongo.SessionContext) error {
+			if result, err := dao.insertIfNotExist(sctx, collectionName, bo); err != nil {
+				return err
+			} else if result {
+				numRows = 1
+			}
+			return nil
+		})
+		if isErrorDuplicatedKey(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return numRows, err
+	} else {
+		if result, err := dao.insertIfNotExist(ctx, collectionName, bo); err != nil {
+			if isErrorDuplicatedKey(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		} else if result {
+			return 1, nil
+		}
+		return 0, nil
+	}
+}
+
+/*
+GdaoCreateMany implements godal.IGenericDao.GdaoCreateMany.
+*/
+func (dao *GenericDaoMongo) GdaoCreateMany(collectionName string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithContext(nil, collectionName, boList)
+}
+
+/*
+GdaoCreateManyWithContext is extended-implementation of godal.IGenericDao.GdaoCreateMany.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- BOs are inserted using one unordered bulk-write command (see MongoBulkWrite), txModeOnWrite does not apply.
+	- unlike GdaoCreate, existing documents are not looked up using GdaoCreateFilter: duplicated entries are detected by unique indexes only.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoCreateManyWithContext(ctx context.Context, collectionName string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreateMany", collectionName)
+	defer func() { op.FinishBulk(results, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, err
+	}
+	return dao.bulkWrite(ctx, collectionName, boList, godal.HookBeforeCreate, godal.HookAfterCreate, func(filter map[string]interface{}, doc interface{}) mongo.WriteModel {
+		return mongo.NewInsertOneModel().SetDocument(doc)
+	})
+}
+
+// bulkWrite writes BOs using one unordered bulk-write command and returns per-BO results; hooks at 'before' and 'after' are invoked for each BO.
+func (dao *GenericDaoMongo) bulkWrite(ctx context.Context, collectionName string, boList []godal.IGenericBo, before, after godal.HookPoint, newModel func(filter map[string]interface{}, doc interface{}) mongo.WriteModel) ([]godal.GdaoBulkResult, error) {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	results := make([]godal.GdaoBulkResult, len(boList))
+	models := make([]mongo.WriteModel, 0, len(boList))
+	modelIndexes := make([]int, 0, len(boList)) // modelIndexes[i] is the index of the BO of models[i]
+	for i, bo := range boList {
+		if err := dao.BeforeWrite(ctx, before, collectionName, bo); err != nil {
+			results[i].Error = err
+		} else if doc, err := dao.GetRowMapper().ToRow(collectionName, bo); err != nil {
+			results[i].Error = err
+		} else if filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo)); err != nil {
+			results[i].Error = err
+		} else {
+			models = append(models, newModel(filter, doc))
+			modelIndexes = append(modelIndexes, i)
+		}
+	}
+	if len(models) == 0 {
+		return results, bulkError(results)
+	}
+
+	_, err := dao.MongoBulkWrite(ctx, collectionName, models)
+	var writeErrors []mongo.BulkWriteError
+	if bwe := asBulkWriteException(err); bwe != nil {
+		writeErrors = bwe.WriteErrors
+		if bwe.WriteConcernError == nil {
+			err = nil
+		}
+	}
+	// 'err' is now the error that applies to all BOs
+	numRows := 1
+	if err != nil {
+		numRows = 0
+	}
+	for _, i := range modelIndexes {
+		results[i].NumRows, results[i].Error = numRows, err
+	}
+	for _, we := range writeErrors {
+		if we.Index < 0 || we.Index >= len(modelIndexes) {
+			continue
+		}
+		i := modelIndexes[we.Index]
+		results[i].NumRows, results[i].Error = 0, dao.TranslateError(we.WriteError)
+		if we.Code == 11000 {
+			results[i].Error = godal.GdaoErrorDuplicatedEntry
+		}
+	}
+	for _, i := range modelIndexes {
+		results[i].NumRows, results[i].Error = dao.AfterWrite(ctx, after, collectionName, boList[i], results[i].NumRows, results[i].Error)
+	}
+	return results, bulkError(results)
+}
+
+// bulkError returns the first error of the bulk results.
+func bulkError(results []godal.GdaoBulkResult) error {
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+	}
+	return nil
+}
+
+/*
+GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+*/
+func (dao *GenericDaoMongo) GdaoUpdate(collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithContext(nil, collectionName, bo)
+}
+
+/*
+GdaoUpdateWithContext is extended-implementation of godal.IGenericDao.GdaoUpdate.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- the document is replaced as a whole, hence attributes removed via godal.IGenericBo.GboDeleteAttr are unset.
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoMongo) GdaoUpdateWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdate", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, collectionName, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.updateWithContext(ctx, collectionName, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, collectionName, bo, numRows, err)
+}
+
+// updateWithContext replaces an existing document, see GdaoUpdateWithContext.
+func (dao *GenericDaoMongo) updateWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	doc, err := dao.GetRowMapper().ToRow(collectionName, bo)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	if err != nil {
+		return 0, err
+	}
+	versionedFilter, versioned, err := dao.versionedFilter(collectionName, filter, doc)
+	if err != nil {
+		return 0, err
+	}
+	result := dao.MongoUpdateOne(ctx, collectionName, versionedFilter, doc)
+	if _, err := result.DecodeBytes(); err == mongo.ErrNoDocuments {
+		if versioned {
+			return 0, dao.versionMismatchError(ctx, collectionName, filter)
+		}
+		return 0, nil
+	} else if isErrorDuplicatedKey(err) {
+		return 0, godal.GdaoErrorDuplicatedEntry
+	} else if err != nil {
+		return 1, dao.TranslateError(err)
+	}
+	return 1, dao.setWrittenVersion(collectionName, bo, doc, versioned)
+}
+
+/*
+versionedFilter returns the filter matching both 'filter' and the version of 'doc' if optimistic locking is enabled on the collection
+(see godal.AbstractGenericDao.SetVersionField), and increments the version in 'doc'. Otherwise 'filter' is returned as-is.
+*/
+func (dao *GenericDaoMongo) versionedFilter(collectionName string, filter map[string]interface{}, doc interface{}) (map[string]interface{}, bool, error) {
+	field := dao.GetVersionField(collectionName)
+	row, ok := doc.(map[string]interface{})
+	if field == "" || !ok {
+		return filter, false, nil
+	}
+	current, next, err := godal.NextVersion(row, field)
+	if err != nil {
+		return nil, false, err
+	}
+	versionFilter, err := filterOptToMap(godal.VersionFilter(field, current))
+	if err != nil {
+		return nil, false, err
+	}
+	row[field] = next
+	return map[string]interface{}{"$and": []interface{}{filter, versionFilter}}, true, nil
+}
+
+// setWrittenVersion sets the version written to the stored document (found in 'doc', see versionedFilter) to bo if 'written' is true.
+func (dao *GenericDaoMongo) setWrittenVersion(collectionName string, bo godal.IGenericBo, doc interface{}, written bool) error {
+	if !written {
+		return nil
+	}
+	version, err := reddo.ToInt(doc.(map[string]interface{})[dao.GetVersionField(collectionName)])
+	if err != nil {
+		return err
+	}
+	return dao.SetBoVersion(collectionName, bo, version)
+}
+
+// versionMismatchError is called when a versioned write matches no document: it returns godal.GdaoErrorConcurrentModification if the
+// document exists (i.e. the version did not match), nil otherwise.
+func (dao *GenericDaoMongo) versionMismatchError(ctx context.Context, collectionName string, filter map[string]interface{}) error {
+	count, err := dao.MongoCountDocuments(ctx, collectionName, filter, 1)
+	if err == nil && count > 0 {
+		err = godal.GdaoErrorConcurrentModification
+	}
+	return err
+}
+
+/*
+GdaoPatch implements godal.IGenericDao.GdaoPatch.
+*/
+func (dao *GenericDaoMongo) GdaoPatch(collectionName string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithContext(nil, collectionName, bo, patch)
+}
+
+// mongoPatchOps maps patch operators to MongoDB update operators.
+var mongoPatchOps = map[godal.PatchOperator]string{
+	godal.PatchOpSet:       "$set",
+	godal.PatchOpUnset:     "$unset",
+	godal.PatchOpIncrement: "$inc",
+	godal.PatchOpAppend:    "$push",
+}
+
+/*
+GdaoPatchWithContext is extended-implementation of godal.IGenericDao.GdaoPatch.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- patch operators are mapped to MongoDB update operators $set, $unset, $inc and $push.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoPatchWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo, patch *godal.PatchOpt) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoPatch", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforePatch(ctx, collectionName, bo, patch); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.patchWithContext(ctx, collectionName, bo, patch)
+	return dao.AfterPatch(ctx, collectionName, bo, patch, numRows, err)
+}
+
+// patchWithContext patches an existing document, see GdaoPatchWithContext.
+func (dao *GenericDaoMongo) patchWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	if patch == nil || len(patch.Fields) == 0 {
+		return 0, errors.New("patch must have at least one field")
+	}
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	if err != nil {
+		return 0, err
+	}
+	update := bson.M{}
+	for _, f := range patch.Fields {
+		op, ok := mongoPatchOps[f.Operator]
+		if !ok {
+			return 0, errors.New(fmt.Sprintf("unsupported patch operator %d", f.Operator))
+		}
+		if update[op] == nil {
+			update[op] = bson.M{}
+		}
+		if f.Operator == godal.PatchOpUnset {
+			update[op].(bson.M)[f.Field] = ""
+		} else {
+			update[op].(bson.M)[f.Field] = f.Value
+		}
+	}
+	result, err := dao.MongoPatchOne(ctx, collectionName, filter, update)
+	if err != nil {
+		if isErrorDuplicatedKey(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	}
+	return int(result.MatchedCount), nil
+}
+
+/*
+GdaoUpdateDirty implements godal.IGenericDao.GdaoUpdateDirty.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoUpdateDirty(collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithContext(nil, collectionName, bo)
+}
+
+/*
+GdaoUpdateDirtyWithContext is extended-implementation of godal.IGenericDao.GdaoUpdateDirty.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- dirty paths (see godal.IGenericBo.GboDirtyPaths) are "$set", e.g. "options.workhour[0].value" is set as "options.workhour.0.value";
+	  top-level attributes removed via godal.IGenericBo.GboDeleteAttr are "$unset".
+	- values are taken from the document the row mapper returns for the whole BO, at the same paths (i.e. field names are expected to
+	  be kept intact, as GenericRowMapperMongo does).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoUpdateDirtyWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdateDirty", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if !bo.GboIsDirty() {
+		return 0, nil
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, collectionName, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.updateDirtyWithContext(ctx, collectionName, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, collectionName, bo, numRows, err)
+}
+
+// updateDirtyWithContext updates dirty attributes of an existing document, see GdaoUpdateDirtyWithContext.
+func (dao *GenericDaoMongo) updateDirtyWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	row, err := dao.GetRowMapper().ToRow(collectionName, bo)
+	if err != nil {
+		return 0, err
+	}
+	doc, err := toMap(row)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	if err != nil {
+		return 0, err
+	}
+	versionedFilter, versioned, err := dao.versionedFilter(collectionName, filter, doc)
+	if err != nil {
+		return 0, err
+	}
+	update := bson.M{}
+	toSet := bson.M{}
+	s := semita.NewSemita(doc)
+	for _, path := range bo.GboDirtyPaths() {
+		value, err := s.GetValue(path)
+		if err != nil {
+			return 0, err
+		}
+		toSet[toMongoPath(path)] = value
+	}
+	if versioned {
+		field := dao.GetVersionField(collectionName)
+		toSet[field] = doc[field]
+	}
+	if len(toSet) > 0 {
+		update["$set"] = toSet
+	}
+	if attrs := bo.GboDeletedAttrs(); len(attrs) > 0 {
+		toUnset := bson.M{}
+		for _, attr := range attrs {
+			toUnset[attr] = ""
+		}
+		update["$unset"] = toUnset
+	}
+	result, err := dao.MongoPatchOne(ctx, collectionName, versionedFilter, update)
+	if err != nil {
+		if isErrorDuplicatedKey(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	}
+	if result.MatchedCount == 0 && versioned {
+		return 0, dao.versionMismatchError(ctx, collectionName, filter)
+	}
+	return int(result.MatchedCount), dao.setWrittenVersion(collectionName, bo, doc, versioned && result.MatchedCount > 0)
+}
+
+// toMongoPath converts a BO's path to MongoDB's dot notation, e.g. "a.b[0].c" -> "a.b.0.c".
+func toMongoPath(path string) string {
+	tokens := semita.SplitPath(path)
+	for i, token := range tokens {
+		tokens[i] = strings.TrimSuffix(strings.TrimPrefix(token, "["), "]")
+	}
+	return strings.Join(tokens, ".")
+}
+
+/*
+GdaoSave implements godal.IGenericDao.GdaoSave.
+*/
+func (dao *GenericDaoMongo) GdaoSave(collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveWithContext(nil, collectionName, bo)
+}
+
+/*
+GdaoSaveWithContext is extended-implementation of godal.IGenericDao.GdaoSave.
+
+	- ctx: can be used to pass a transaction down to the operation
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoMongo) GdaoSaveWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSave", collectionName)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return 0, err
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeSave, collectionName, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.saveWithContext(ctx, collectionName, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterSave, collectionName, bo, numRows, err)
+}
+
+// saveWithContext replaces an existing document or inserts a new one, see GdaoSaveWithContext.
+func (dao *GenericDaoMongo) saveWithContext(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	if ctx == nil {
+		ctx, _ = dao.mongoConnect.NewContext()
+	}
+	doc, err := dao.GetRowMapper().ToRow(collectionName, bo)
+	if err != nil {
+		return 0, err
+	}
+	filter, err := toMap(dao.GdaoCreateFilter(collectionName, bo))
+	if err != nil {
+		return 0, err
+	}
+	if versionedFilter, versioned, err := dao.versionedFilter(collectionName, filter, doc); err != nil {
+		return 0, err
+	} else if versioned {
+		// optimistic locking: replace the document if its version matches, otherwise insert it if it does not exist
+		result := dao.MongoUpdateOne(ctx, collectionName, versionedFilter, doc)
+		if _, err := result.DecodeBytes(); err == nil {
+			return 1, dao.setWrittenVersion(collectionName, bo, doc, true)
+		} else if isErrorDuplicatedKey(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		} else if err != mongo.ErrNoDocuments {
+			return 0, dao.TranslateError(err)
+		}
+		if err := dao.versionMismatchError(ctx, collectionName, filter); err != nil {
+			return 0, err
+		}
+		if _, err := dao.MongoInsertOne(ctx, collectionName, doc); err != nil {
+			if isErrorDuplicatedKey(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		}
+		return 1, dao.setWrittenVersion(collectionName, bo, doc, true)
+	}
+	result := dao.MongoSaveOne(ctx, collectionName, filter, doc)
+	if err = result.Err(); err == nil || err == mongo.ErrNoDocuments {
+		return 1, nil
+	} else {
+		if isErrorDuplicatedKey(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 1, dao.TranslateError(err)
+	}
+}
+
+/*
+GdaoSaveMany implements godal.IGenericDao.GdaoSaveMany.
+*/
+func (dao *GenericDaoMongo) GdaoSaveMany(collectionName string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyWithContext(nil, collectionName, boList)
+}
+
+/*
+GdaoSaveManyWithContext is extended-implementation of godal.IGenericDao.GdaoSaveMany.
+
+	- ctx: can be used to pass a transaction down to the operation
+	- BOs are saved using one unordered bulk-write command of replace-one models with 'upsert=true' (see MongoBulkWrite).
+	- if optimistic locking is enabled on the collection (see godal.AbstractGenericDao.SetVersionField), BOs are saved one by one
+	  using GdaoSaveWithContext instead, so that each BO's version is checked.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoSaveManyWithContext(ctx context.Context, collectionName string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSaveMany", collectionName)
+	defer func() { op.FinishBulk(results, err) }()
+	if err := dao.checkTx(ctx); err != nil {
+		return nil, err
+	}
+	if dao.GetVersionField(collectionName) != "" {
+		results = make([]godal.GdaoBulkResult, len(boList))
+		for i, bo := range boList {
+			results[i].NumRows, results[i].Error = dao.GdaoSaveWithContext(ctx, collectionName, bo)
+		}
+		return results, bulkError(results)
+	}
+	return dao.bulkWrite(ctx, collectionName, boList, godal.HookBeforeSave, godal.HookAfterSave, func(filter map[string]interface{}, doc interface{}) mongo.WriteModel {
+		return mongo.NewReplaceOneModel().SetFilter(filter).SetReplacement(doc).SetUpsert(true)
+	})
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+GdaoDeleteCtx implements godal.IGenericDaoWithContext.GdaoDeleteCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoDeleteCtx(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoDeleteWithContext(ctx, collectionName, bo)
+}
+
+/*
+GdaoDeleteManyCtx implements godal.IGenericDaoWithContext.GdaoDeleteManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoDeleteManyCtx(ctx context.Context, collectionName string, filter interface{}) (int, error) {
+	return dao.GdaoDeleteManyWithContext(ctx, collectionName, filter)
+}
+
+/*
+GdaoFetchOneCtx implements godal.IGenericDaoWithContext.GdaoFetchOneCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoFetchOneCtx(ctx context.Context, collectionName string, filter interface{}) (godal.IGenericBo, error) {
+	return dao.GdaoFetchOneWithContext(ctx, collectionName, filter)
+}
+
+/*
+GdaoFetchManyCtx implements godal.IGenericDaoWithContext.GdaoFetchManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoFetchManyCtx(ctx context.Context, collectionName string, filter interface{}, sorting interface{}, startOffset, numItems int) ([]godal.IGenericBo, error) {
+	return dao.GdaoFetchManyWithContext(ctx, collectionName, filter, sorting, startOffset, numItems)
+}
+
+/*
+GdaoFetchIteratorCtx implements godal.IGenericDaoWithContext.GdaoFetchIteratorCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoFetchIteratorCtx(ctx context.Context, collectionName string, filter interface{}, sorting interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithContext(ctx, collectionName, filter, sorting)
+}
+
+/*
+GdaoFetchPageCtx implements godal.IGenericDaoWithContext.GdaoFetchPageCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoFetchPageCtx(ctx context.Context, collectionName string, filter interface{}, sorting interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithContext(ctx, collectionName, filter, sorting, pageToken, numItems)
+}
+
+/*
+GdaoCountCtx implements godal.IGenericDaoWithContext.GdaoCountCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoCountCtx(ctx context.Context, collectionName string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithContext(ctx, collectionName, filter)
+}
+
+/*
+GdaoExistsCtx implements godal.IGenericDaoWithContext.GdaoExistsCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoExistsCtx(ctx context.Context, collectionName string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithContext(ctx, collectionName, filter)
+}
+
+/*
+GdaoCreateCtx implements godal.IGenericDaoWithContext.GdaoCreateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoCreateCtx(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithContext(ctx, collectionName, bo)
+}
+
+/*
+GdaoCreateManyCtx implements godal.IGenericDaoWithContext.GdaoCreateManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoCreateManyCtx(ctx context.Context, collectionName string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithContext(ctx, collectionName, boList)
+}
+
+/*
+GdaoUpdateCtx implements godal.IGenericDaoWithContext.GdaoUpdateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoUpdateCtx(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithContext(ctx, collectionName, bo)
+}
+
+/*
+GdaoPatchCtx implements godal.IGenericDaoWithContext.GdaoPatchCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoPatchCtx(ctx context.Context, collectionName string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithContext(ctx, collectionName, bo, patch)
+}
+
+/*
+GdaoUpdateDirtyCtx implements godal.IGenericDaoWithContext.GdaoUpdateDirtyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoUpdateDirtyCtx(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithContext(ctx, collectionName, bo)
+}
+
+/*
+GdaoSaveCtx implements godal.IGenericDaoWithContext.GdaoSaveCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoSaveCtx(ctx context.Context, collectionName string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveWithContext(ctx, collectionName, bo)
+}
+
+/*
+GdaoSaveManyCtx implements godal.IGenericDaoWithContext.GdaoSaveManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoMongo) GdaoSaveManyCtx(ctx context.Context, collectionName string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyWithContext(ctx, collectionName, boList)
 }
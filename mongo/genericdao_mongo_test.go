@@ -1,16 +1,21 @@
 package mongo
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/godal"
 	"github.com/btnguyen2k/prom"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"reflect"
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 )
 
 func createMongoConnect() *prom.MongoConnect {
@@ -633,6 +638,159 @@ func TestGenericDaoMongo_GdaoFetchManyWithPaging(t *testing.T) {
 	}
 }
 
+func TestGenericDaoMongo_GdaoFetchIterator(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoFetchIterator"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	iter, err := dao.GdaoFetchIterator(dao.collectionName, godal.FilterGte("version", 80), godal.NewSortingOpt().Add("version", godal.SortOrderDesc))
+	if err != nil || iter == nil {
+		t.Fatalf("%s failed - Iterator: %v / Error: %e", name, iter, err)
+	}
+	defer func() { _ = iter.Close() }()
+	count := 0
+	for ; iter.Next(); count++ {
+		if bo := fromGbo(iter.Bo()); bo.Version != numItems-count-1 {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, numItems-count-1, bo)
+		}
+	}
+	if err := iter.Err(); err != nil || count != 20 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, count, err)
+	}
+}
+
+func TestGenericDaoMongo_GdaoFetchPage(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoFetchPage"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i%10),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	filter := godal.FilterGte("version", 20)
+	sorting := godal.NewSortingOpt().Add("name", godal.SortOrderDesc).Add("version", godal.SortOrderAsc)
+	pageToken, fetched := "", make([]*MyBo, 0)
+	for numPages := 1; ; numPages++ {
+		boList, nextPageToken, err := dao.GdaoFetchPage(dao.collectionName, filter, sorting, pageToken, 7)
+		if err != nil || len(boList) > 7 || numPages > 12 {
+			t.Fatalf("%s failed - Page: %v / NumItems: %v / Error: %e", name, numPages, len(boList), err)
+		}
+		for _, gbo := range boList {
+			fetched = append(fetched, fromGbo(gbo))
+		}
+		if pageToken = nextPageToken; pageToken == "" {
+			break
+		}
+	}
+	if len(fetched) != 80 {
+		t.Fatalf("%s failed - Expected %v items / Received: %v", name, 80, len(fetched))
+	}
+	for i := 1; i < len(fetched); i++ {
+		prev, cur := fetched[i-1], fetched[i]
+		if prev.Name < cur.Name || (prev.Name == cur.Name && prev.Version >= cur.Version) {
+			t.Fatalf("%s failed - Items out of order: %v / %v", name, prev, cur)
+		}
+	}
+}
+
+func TestGenericDaoMongo_GdaoCountExists(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoCountExists"
+	dao := initDao()
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       strconv.Itoa(i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != int64(numItems) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, numItems, count, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, godal.FilterGte("version", 80)); err != nil || count != 20 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 20, count, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, map[string]interface{}{fieldId: "99"}); err != nil || !exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, true, exists, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, godal.FilterGte("version", numItems)); err != nil || exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, false, exists, err)
+	}
+}
+
+func TestGenericDaoMongo_GdaoCreateManySaveMany(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoCreateManySaveMany"
+	dao := initDao()
+	for i := 0; i < 4; i += 2 {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// BOs "0" and "2" already exist
+	boList := make([]godal.IGenericBo, 5)
+	for i := range boList {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: "user" + strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		boList[i] = bo.ToGbo()
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, boList)
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if i < 4 && i%2 == 0 && (r.Error != godal.GdaoErrorDuplicatedEntry || r.NumRows != 0) {
+			t.Fatalf("%s failed - Item: %d / Expected error: %e / Result: %v", name, i, godal.GdaoErrorDuplicatedEntry, r)
+		}
+		if (i >= 4 || i%2 != 0) && (r.Error != nil || r.NumRows != 1) {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+
+	results, err = dao.GdaoSaveMany(dao.collectionName, boList)
+	if err != nil || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if r.Error != nil || r.NumRows != 1 {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "2"}); err != nil || gbo == nil || fromGbo(gbo).Username != "user2" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
 func TestGenericDaoMongo_GdaoUpdateNotExist(t *testing.T) {
 	name := "TestGenericDaoMongo_GdaoUpdateNotExist"
 	dao := initDao()
@@ -712,6 +870,493 @@ func TestGenericDaoMongo_GdaoUpdate(t *testing.T) {
 	}
 }
 
+func TestGenericDaoMongo_GdaoPatch(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoPatch"
+	dao := initDao()
+	for i := 1; i <= 2; i++ {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	bo := (&MyBo{Id: "1"}).ToGbo()
+
+	patch := godal.NewPatchOpt().Set("name", "patched").Increment("version", 2).Append("tags", "a")
+	if numRows, err := dao.GdaoPatch(dao.collectionName, (&MyBo{Id: "9"}).ToGbo(), patch); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, patch); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if myBo := fromGbo(gbo); myBo.Name != "patched" || myBo.Version != 3 || myBo.Username != "1" {
+		t.Fatalf("%s failed - Received: %v", name, myBo)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || fmt.Sprint(tags) != "[a]" {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if numRows, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Unset("name").Append("tags", "b")); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, _ = dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if v, err := gbo.GboGetAttr("name", nil); err != nil || v != nil {
+		t.Fatalf("%s failed - Name: %v / Error: %e", name, v, err)
+	}
+	if tags, err := gbo.GboGetAttr("tags", nil); err != nil || fmt.Sprint(tags) != "[a b]" {
+		t.Fatalf("%s failed - Tags: %v / Error: %e", name, tags, err)
+	}
+
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Set(fieldUsername, "2")); err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt().Increment(fieldUsername, 1)); err == nil {
+		t.Fatalf("%s failed - Expected error for incrementing a string field", name)
+	}
+	if _, err := dao.GdaoPatch(dao.collectionName, bo, godal.NewPatchOpt()); err == nil {
+		t.Fatalf("%s failed - Expected error for empty patch", name)
+	}
+}
+
+func TestGenericDaoMongo_GboDeleteAttr(t *testing.T) {
+	name := "TestGenericDaoMongo_GboDeleteAttr"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || !gbo.GboHasAttr("name") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err = dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || gbo.GboHasAttr("name") || !gbo.GboHasAttr("username") {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoMongo_GdaoUpdateDirty(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoUpdateDirty"
+	dao := initDao()
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchGbo := func(id string) godal.IGenericBo {
+		gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil || gbo.GboIsDirty() {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return gbo
+	}
+	gbo1, gbo2 := fetchGbo("1"), fetchGbo("1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo1); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// writers modifying different attributes do not overwrite each other's changes
+	gbo1.GboSetAttr("name", "changed")
+	gbo2.GboSetAttr(fieldUsername, "u2")
+	for _, gbo := range []godal.IGenericBo{gbo1, gbo2} {
+		if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "changed" || bo.Username != "u2" || bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// removed attributes are removed from storage
+	gbo := fetchGbo("1")
+	gbo.GboDeleteAttr("name")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo = fetchGbo("1"); gbo.GboHasAttr("name") || !gbo.GboHasAttr(fieldUsername) {
+		t.Fatalf("%s failed - Gbo: %v", name, gbo)
+	}
+
+	gbo = (&MyBo{Id: "9", Username: "9"}).ToGbo()
+	gbo.GboSetAttr("name", "changed")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// optimistic locking
+	dao.SetVersionField(dao.collectionName, "version")
+	gbo1, gbo2 = fetchGbo("1"), fetchGbo("1")
+	gbo1.GboSetAttr("name", "v1")
+	if numRows, err := dao.GdaoUpdateDirty(dao.collectionName, gbo1); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo2.GboSetAttr(fieldUsername, "stale")
+	if _, err := dao.GdaoUpdateDirty(dao.collectionName, gbo2); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fromGbo(fetchGbo("1")); bo.Name != "v1" || bo.Username != "u2" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+}
+
+func TestToMongoPath(t *testing.T) {
+	name := "TestToMongoPath"
+	for path, expected := range map[string]string{"a": "a", "a.b": "a.b", "a.b[0].c": "a.b.0.c", "a[1][2]": "a.1.2"} {
+		if received := toMongoPath(path); received != expected {
+			t.Fatalf("%s failed - Path: %s / Expected: %s / Received: %s", name, path, expected, received)
+		}
+	}
+}
+
+func TestGenericDaoMongo_Validator(t *testing.T) {
+	name := "TestGenericDaoMongo_Validator"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.collectionName, &godal.BoSchema{
+		Type:       godal.SchemaTypeObject,
+		Required:   []string{fieldId, fieldUsername},
+		Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}},
+	})
+	isValidationError := func(err error) bool {
+		_, ok := err.(*godal.ValidationError)
+		return ok
+	}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	invalidBo := (&MyBo{Id: "2", Username: "2"}).ToGbo()
+	if _, err := dao.GdaoCreate(dao.collectionName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoSave(dao.collectionName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, []godal.IGenericBo{(&MyBo{Id: "3", Username: "3", Name: "BO"}).ToGbo(), invalidBo})
+	if !isValidationError(err) || len(results) != 2 || results[0].Error != nil || results[0].NumRows != 1 || !isValidationError(results[1].Error) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if exists, err := dao.GdaoExists(dao.collectionName, map[string]interface{}{fieldId: "2"}); err != nil || exists {
+		t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || fromGbo(gbo).Name != "BO" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+
+	dao.SetValidator(dao.collectionName, nil)
+	if numRows, err := dao.GdaoCreate(dao.collectionName, invalidBo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+func TestGenericDaoMongo_Hooks(t *testing.T) {
+	name := "TestGenericDaoMongo_Hooks"
+	dao := initDao()
+	minLen := 1
+	dao.SetValidator(dao.collectionName, &godal.BoSchema{Properties: map[string]*godal.BoSchema{"name": {Type: godal.SchemaTypeString, MinLength: &minLen}}})
+	counters := make(map[godal.HookPoint]int)
+	errAbort := errors.New("abort")
+	for _, point := range []godal.HookPoint{godal.HookBeforeCreate, godal.HookAfterCreate, godal.HookBeforeUpdate, godal.HookAfterUpdate,
+		godal.HookBeforeSave, godal.HookAfterSave, godal.HookBeforeDelete, godal.HookAfterDelete, godal.HookAfterFetch} {
+		point := point
+		dao.AddHook(point, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+			if ctx == nil || storageId != dao.collectionName {
+				return errors.New("invalid hook arguments")
+			}
+			counters[point]++
+			return nil
+		})
+	}
+	stamp := func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe("name", reddo.TypeString) == "" {
+			return bo.GboSetAttr("name", "stamped")
+		}
+		return nil
+	}
+	dao.AddHook(godal.HookBeforeCreate, stamp).AddHook(godal.HookBeforeUpdate, stamp).AddHook(godal.HookBeforeSave, stamp)
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return bo.GboSetAttr("fetched", true)
+	})
+	dao.AddHook(godal.HookBeforeDelete, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		if bo.GboGetAttrUnsafe(fieldId, reddo.TypeString) == "locked" {
+			return errAbort
+		}
+		return nil
+	})
+
+	// "before" hooks run before validation, changes made by hooks are written
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"})
+	if err != nil || gbo == nil || fromGbo(gbo).Name != "stamped" || gbo.GboGetAttrUnsafe("fetched", nil) != true || gbo.GboIsDirty() {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "0", Username: "0"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "locked", Username: "locked"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	results, err := dao.GdaoCreateMany(dao.collectionName, []godal.IGenericBo{(&MyBo{Id: "2", Username: "2"}).ToGbo(), (&MyBo{Id: "1", Username: "1"}).ToGbo()})
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != 2 || results[0].NumRows != 1 || results[1].Error != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, (&MyBo{Id: "locked"}).ToGbo()); err != errAbort || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(dao.collectionName, (&MyBo{Id: "2"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0); err != nil || len(boList) != 2 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	expected := map[godal.HookPoint]int{godal.HookBeforeCreate: 3, godal.HookAfterCreate: 2, godal.HookBeforeUpdate: 2, godal.HookAfterUpdate: 1,
+		godal.HookBeforeSave: 1, godal.HookAfterSave: 1, godal.HookBeforeDelete: 2, godal.HookAfterDelete: 1, godal.HookAfterFetch: 3}
+	if !reflect.DeepEqual(counters, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, counters)
+	}
+
+	// hooks can abort the operation
+	dao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, bo godal.IGenericBo) error {
+		return errAbort
+	})
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != errAbort || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	dao.ClearHooks(godal.HookAfterFetch)
+	if gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: "1"}); err != nil || gbo == nil || gbo.GboGetAttrUnsafe("fetched", nil) != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func TestGenericDaoMongo_RetryPolicy(t *testing.T) {
+	name := "TestGenericDaoMongo_RetryPolicy"
+	dao := initDao()
+	attempts := make([]godal.RetryAttempt, 0)
+	policy := godal.NewRetryPolicy(3)
+	policy.InitialBackoff = time.Millisecond
+	policy.OnAttempt = func(a godal.RetryAttempt) { attempts = append(attempts, a) }
+	dao.SetRetryPolicy(policy)
+
+	bo := &MyBo{Id: "1", Username: "1", Name: "BO"}
+	if numRows, err := dao.GdaoCreate(dao.collectionName, bo.ToGbo()); err != nil || numRows != 1 || len(attempts) == 0 {
+		t.Fatalf("%s failed - NumRows: %v / Attempts: %v / Error: %e", name, numRows, attempts, err)
+	}
+	// non-retryable errors are returned immediately
+	attempts = attempts[:0]
+	if _, err := dao.MongoInsertOne(nil, dao.collectionName, map[string]interface{}{fieldId: "1"}); !errors.Is(err, godal.ErrConflict) || len(attempts) != 1 || attempts[0].Retry {
+		t.Fatalf("%s failed - Attempts: %v / Error: %e", name, attempts, err)
+	}
+
+	// retryable errors
+	attempts = attempts[:0]
+	errConflict := godal.WrapError(godal.ErrConcurrentModification, errors.New("write conflict"))
+	numCalls := 0
+	err := dao.retry(nil, func(ctx context.Context) error {
+		numCalls++
+		return errConflict
+	})
+	if err != errConflict || numCalls != 3 || len(attempts) != 3 {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+}
+
+type testInstrumentation struct {
+	lock     sync.Mutex
+	finished []*godal.DaoOperation
+}
+
+func (ti *testInstrumentation) OnStart(ctx context.Context, op *godal.DaoOperation) context.Context {
+	return ctx
+}
+
+func (ti *testInstrumentation) OnFinish(ctx context.Context, op *godal.DaoOperation) {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	ti.finished = append(ti.finished, op)
+}
+
+func TestGenericDaoMongo_Instrumentation(t *testing.T) {
+	name := "TestGenericDaoMongo_Instrumentation"
+	dao := initDao()
+	instrumentation := &testInstrumentation{}
+	dao.SetInstrumentation(instrumentation)
+	verify := func(operation string, numRows int, err error) {
+		if len(instrumentation.finished) == 0 {
+			t.Fatalf("%s failed - no operation reported", name)
+		}
+		op := instrumentation.finished[len(instrumentation.finished)-1]
+		if op.Backend != "mongo" || op.Operation != operation || op.StorageId != dao.collectionName || op.NumRows != numRows || op.Err != err {
+			t.Fatalf("%s failed - expected %s/%d/%v but received %v", name, operation, numRows, err, op)
+		}
+	}
+
+	if _, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	verify("GdaoCreate", 1, nil)
+	_, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo())
+	verify("GdaoCreate", 0, err)
+	if err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if boList, err := dao.GdaoFetchMany(dao.collectionName, nil, nil, 0, 0); err != nil || len(boList) != 1 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	verify("GdaoFetchMany", 1, nil)
+
+	// calls made with a session context still join the transaction
+	errAbort := errors.New("abort")
+	err = dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		if _, err := dao.GdaoCreateCtx(txCtx, dao.collectionName, (&MyBo{Id: "2", Username: "2", Name: "BO"}).ToGbo()); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 1 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+	verify("GdaoCount", 1, nil)
+}
+
+func TestGenericDaoMongo_Transaction(t *testing.T) {
+	name := "TestGenericDaoMongo_Transaction"
+	dao := initDao()
+	other := createDaoMongo(createMongoConnect(), dao.collectionName)
+
+	// the second dao does not join the transaction: its write persists even if the transaction is aborted
+	errAbort := errors.New("abort")
+	var staleCtx context.Context
+	err := dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		staleCtx = txCtx
+		if _, err := txDao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+			return err
+		}
+		if _, err := other.GdaoCreateWithContext(txCtx, dao.collectionName, (&MyBo{Id: "2", Username: "2", Name: "BO"}).ToGbo()); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 1 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+
+	// the context of an ended transaction is rejected
+	if _, err := dao.GdaoCreateWithContext(staleCtx, dao.collectionName, (&MyBo{Id: "3", Username: "3", Name: "BO"}).ToGbo()); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if err := dao.GdaoWithTransaction(staleCtx, func(txCtx context.Context, txDao godal.IGenericDao) error { return nil }); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(dao.collectionName, nil); err != nil || count != 1 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+}
+
+func TestGenericDaoMongo_OptimisticLocking(t *testing.T) {
+	name := "TestGenericDaoMongo_OptimisticLocking"
+	dao := initDao()
+	dao.SetVersionField(dao.collectionName, "version")
+	if numRows, err := dao.GdaoCreate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "BO", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	fetchBo := func(id string) *MyBo {
+		gbo, err := dao.GdaoFetchOne(dao.collectionName, map[string]interface{}{fieldId: id})
+		if err != nil || gbo == nil {
+			t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+		}
+		return fromGbo(gbo)
+	}
+
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v1", Version: 1}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	// stale version
+	if _, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if _, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo()); err != godal.GdaoErrorConcurrentModification {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, godal.GdaoErrorConcurrentModification, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v1" || bo.Version != 2 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v2", Version: 2}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// GdaoSave creates non-existing BO, GdaoUpdate does not
+	if numRows, err := dao.GdaoSave(dao.collectionName, (&MyBo{Id: "2", Username: "2", Name: "new"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if bo := fetchBo("2"); bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "3", Username: "3"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	// the new version is written back to the BO: it can be updated again without being re-fetched
+	gbo := (&MyBo{Id: "2", Username: "2", Name: "new", Version: 1}).ToGbo()
+	for _, boName := range []string{"u1", "u2"} {
+		gbo.GboSetAttr("name", boName)
+		if numRows, err := dao.GdaoUpdate(dao.collectionName, gbo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+	if bo := fetchBo("2"); bo.Name != "u2" || bo.Version != 3 || fromGbo(gbo).Version != 3 {
+		t.Fatalf("%s failed - Received: %v / BO: %v", name, bo, fromGbo(gbo))
+	}
+
+	// GdaoSaveMany checks the version of each BO
+	boList := []godal.IGenericBo{
+		(&MyBo{Id: "1", Username: "1", Name: "stale", Version: 1}).ToGbo(),
+		(&MyBo{Id: "2", Username: "2", Name: "v4", Version: 3}).ToGbo(),
+		(&MyBo{Id: "4", Username: "4", Name: "new"}).ToGbo(),
+	}
+	results, err := dao.GdaoSaveMany(dao.collectionName, boList)
+	if err != godal.GdaoErrorConcurrentModification || results[0].Error != godal.GdaoErrorConcurrentModification || results[1].NumRows != 1 || results[2].NumRows != 1 {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if bo := fetchBo("1"); bo.Name != "v2" || bo.Version != 3 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("2"); bo.Name != "v4" || bo.Version != 4 || fromGbo(boList[1]).Version != 4 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+	if bo := fetchBo("4"); bo.Version != 1 || fromGbo(boList[2]).Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	// optimistic locking disabled
+	dao.SetVersionField(dao.collectionName, "")
+	if numRows, err := dao.GdaoUpdate(dao.collectionName, (&MyBo{Id: "1", Username: "1", Name: "v0", Version: 0}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
 func TestGenericDaoMongo_GdaoSaveDuplicated(t *testing.T) {
 	name := "TestGenericDaoMongo_GdaoSaveDuplicated"
 	dao := initDao()
@@ -791,3 +1436,165 @@ func TestGenericDaoMongo_GdaoSave(t *testing.T) {
 		t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
 	}
 }
+
+func TestGenericDaoMongo_IGenericDaoWithContext(t *testing.T) {
+	name := "TestGenericDaoMongo_IGenericDaoWithContext"
+	var dao interface{} = &GenericDaoMongo{}
+	if _, ok := dao.(godal.IGenericDaoWithContext); !ok {
+		t.Fatalf("%s failed: GenericDaoMongo does not implement godal.IGenericDaoWithContext", name)
+	}
+}
+
+func TestGenericDaoMongo_ITransactionalDao(t *testing.T) {
+	name := "TestGenericDaoMongo_ITransactionalDao"
+	var dao interface{} = &GenericDaoMongo{}
+	if _, ok := dao.(godal.ITransactionalDao); !ok {
+		t.Fatalf("%s failed: GenericDaoMongo does not implement godal.ITransactionalDao", name)
+	}
+}
+
+func TestGenericDaoMongo_GdaoWithTransaction_TxCtx(t *testing.T) {
+	name := "TestGenericDaoMongo_GdaoWithTransaction_TxCtx"
+	dao := createDaoMongo(nil, collectionName)
+	other := createDaoMongo(nil, collectionName)
+	tx := &mongoTx{dao: dao.GenericDaoMongo}
+	txCtx := context.WithValue(context.Background(), ctxKeyTx{}, tx)
+
+	// the dao's own active transaction is joined, the other dao's is not
+	if v, err := dao.txFromContext(txCtx); v != tx || err != nil {
+		t.Fatalf("%s failed - Tx: %v / Error: %e", name, v, err)
+	}
+	if v, err := other.txFromContext(txCtx); v != nil || err != nil {
+		t.Fatalf("%s failed - Tx: %v / Error: %e", name, v, err)
+	}
+	numCalls := 0
+	err := other.retry(txCtx, func(ctx context.Context) error {
+		numCalls++
+		if sctx, ok := ctx.(mongo.SessionContext); !ok || !reflect.ValueOf(sctx).Elem().FieldByName("Session").IsNil() {
+			t.Fatalf("%s failed - context of the other dao is not detached from the transaction", name)
+		}
+		return nil
+	})
+	if err != nil || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+
+	// once ended, the transaction is rejected by its dao but not by the other dao
+	tx.end()
+	bo := &MyBo{Id: "1", Username: "1", Name: "BO"}
+	if _, err := dao.GdaoCreateWithContext(txCtx, dao.collectionName, bo.ToGbo()); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - GdaoCreate: %e", name, err)
+	}
+	if _, err := dao.GdaoSaveWithContext(txCtx, dao.collectionName, bo.ToGbo()); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - GdaoSave: %e", name, err)
+	}
+	if _, err := dao.GdaoFetchOneWithContext(txCtx, dao.collectionName, bson.M{fieldId: "1"}); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - GdaoFetchOne: %e", name, err)
+	}
+	if err := dao.WrapTransaction(txCtx, func(sctx mongo.SessionContext) error { return nil }); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed - WrapTransaction: %e", name, err)
+	}
+	if v, err := other.txFromContext(txCtx); v != nil || err != nil {
+		t.Fatalf("%s failed - Tx: %v / Error: %e", name, v, err)
+	}
+}
+
+func TestGenericDaoMongo_TranslateError(t *testing.T) {
+	name := "TestGenericDaoMongo_TranslateError"
+	dao := &GenericDaoMongo{}
+	testCases := []struct {
+		err      error
+		expected error
+	}{
+		{mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 11000, Message: "E11000 duplicate key error"}}}, godal.ErrConflict},
+		{&mongo.WriteException{WriteErrors: mongo.WriteErrors{{Code: 121, Message: "Document failed validation"}}}, godal.ErrConstraintViolation},
+		{mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 50, Message: "operation exceeded time limit"}}, godal.ErrTimeout},
+		{mongo.CommandError{Code: 112, Message: "WriteConflict"}, godal.ErrConcurrentModification},
+		{mongo.CommandError{Code: 26, Message: "ns not found"}, godal.ErrNotFound},
+		{mongo.CommandError{Code: 16500, Message: "Request rate is large"}, godal.ErrThrottled},
+		{errors.New("(E11000 duplicate key error)"), godal.ErrConflict},
+		{context.DeadlineExceeded, godal.ErrTimeout},
+		{fmt.Errorf("wrapped: %w", mongo.CommandError{Code: 11000}), godal.ErrConflict},
+	}
+	for _, tc := range testCases {
+		err := dao.TranslateError(tc.err)
+		if !errors.Is(err, tc.expected) {
+			t.Fatalf("%s failed - Expected kind: %e / Received: %e", name, tc.expected, err)
+		}
+		if dao.TranslateError(err) != err {
+			t.Fatalf("%s failed - translated error is expected to be returned as-is: %e", name, err)
+		}
+	}
+	if !isErrorDuplicatedKey(dao.TranslateError(testCases[0].err)) || isErrorDuplicatedKey(nil) {
+		t.Fatalf("%s failed - isErrorDuplicatedKey", name)
+	}
+
+	for _, err := range []error{nil, mongo.ErrNoDocuments, &mongo.CommandError{Code: 2}, &mongo.BulkWriteException{}} {
+		if translated := dao.TranslateError(err); translated != err {
+			t.Fatalf("%s failed - Expected: %e / Received: %e", name, err, translated)
+		}
+	}
+	bwe := mongo.BulkWriteException{WriteErrors: []mongo.BulkWriteError{{WriteError: mongo.WriteError{Code: 11000}}}}
+	if asBulkWriteException(fmt.Errorf("wrapped: %w", bwe)) == nil || asBulkWriteException(errors.New("error")) != nil {
+		t.Fatalf("%s failed - asBulkWriteException", name)
+	}
+}
+
+func TestGenericDaoMongo_IsRetryableError(t *testing.T) {
+	name := "TestGenericDaoMongo_IsRetryableError"
+	dao := &GenericDaoMongo{}
+	for _, err := range []error{mongo.CommandError{Code: 112, Message: "WriteConflict"}, mongo.CommandError{Code: 16500},
+		mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}},
+		fmt.Errorf("wrapped: %w", &mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}})} {
+		if !dao.IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be retryable", name, err)
+		}
+	}
+	for _, err := range []error{nil, errors.New("error"), mongo.CommandError{Code: 11000}, godal.GdaoErrorConcurrentModification,
+		mongo.CommandError{Code: 50, Labels: []string{"UnknownTransactionCommitResult"}}} {
+		if dao.IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be non-retryable", name, err)
+		}
+	}
+}
+
+func TestGenericDaoMongo_FilterOpt(t *testing.T) {
+	name := "TestGenericDaoMongo_FilterOpt"
+	filter := godal.FilterAnd(
+		godal.FilterEq("a", 1),
+		godal.FilterOr(godal.FilterNe("b", 2), godal.FilterLt("c", 3)),
+		godal.FilterIn("d", "x", "y"),
+		godal.FilterBetween("e", 4, 5),
+		godal.FilterPrefix("f", "a.b"),
+		godal.FilterNot(godal.FilterIsNull("g")),
+		godal.FilterExists("h"),
+	)
+	f, err := toMap(filter)
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	js, _ := json.Marshal(f)
+	expected := `{"$and":[{"a":{"$eq":1}},{"$or":[{"b":{"$nin":[2,null]}},{"c":{"$lt":3}}]},{"d":{"$in":["x","y"]}},{"e":{"$gte":4,"$lte":5}},{"f":{"$regex":"^a\\.b"}},{"$nor":[{"g":null}]},{"h":{"$ne":null}}]}`
+	if string(js) != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, js)
+	}
+
+	invalidBetween := &godal.FilterOpt{Operator: godal.FilterOpBetween, Field: "a", Values: []interface{}{1}}
+	for _, invalid := range []*godal.FilterOpt{godal.FilterIn("a"), godal.FilterAnd(), invalidBetween} {
+		if _, err := toMap(invalid); err == nil {
+			t.Fatalf("%s failed - Expected error for filter %v", name, invalid)
+		}
+	}
+}
+
+func TestGenericDaoMongo_SortingOpt(t *testing.T) {
+	name := "TestGenericDaoMongo_SortingOpt"
+	sorting, err := toSorting(godal.NewSortingOpt().Add("b", godal.SortOrderDesc).Add("a", godal.SortOrderAsc))
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	expected := bson.D{{Key: "b", Value: -1}, {Key: "a", Value: 1}}
+	if !reflect.DeepEqual(sorting, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, sorting)
+	}
+}
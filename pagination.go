@@ -0,0 +1,205 @@
+package godal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+/*
+KeysetPaging implements keyset (a.k.a. "seek") pagination on top of FilterOpt and SortingOpt.
+
+Instead of skipping 'startOffset' items, the next page is fetched by filtering items that come after the last item of the previous page
+according to the sorting order. The page token carries the sort values of the last item of the previous page.
+
+KeysetPaging is used by implementations of IGenericDao.GdaoFetchPage for backends without native continuation tokens. Requirements:
+
+	- sorting must be a SortingOpt, and the combination of sort fields must be unique (e.g. end the sorting with the primary key),
+	  otherwise items sharing the same sort values at the page boundary are skipped.
+	- sort fields must not be null/absent (comparisons never match null, see FilterOpt), otherwise next page can not be built.
+
+Available since v0.3.0
+*/
+type KeysetPaging struct {
+	Sorting    *SortingOpt
+	LastValues []interface{} // sort values of the last item of the previous page, nil for the first page
+}
+
+// keysetValue is the typed representation of a sort value in a page token, so that value type survives JSON encoding.
+type keysetValue struct {
+	Type  string          `json:"t"`
+	Value json.RawMessage `json:"v"`
+}
+
+// keysetToken is the JSON form of a page token.
+type keysetToken struct {
+	Fields []SortingField `json:"f"`
+	Values []keysetValue  `json:"v"`
+}
+
+/*
+NewKeysetPaging parses sorting and pageToken. pageToken is the token returned by NextPageToken, or "" for the first page.
+
+If pageToken is malformed or was built for another sorting, GdaoErrorInvalidPageToken is returned.
+*/
+func NewKeysetPaging(sorting interface{}, pageToken string) (*KeysetPaging, error) {
+	var s *SortingOpt
+	switch o := sorting.(type) {
+	case *SortingOpt:
+		s = o
+	case SortingOpt:
+		s = &o
+	default:
+		return nil, errors.New(fmt.Sprintf("keyset paging requires sorting to be a godal.SortingOpt, got %T", sorting))
+	}
+	if s == nil || len(s.Fields) == 0 {
+		return nil, errors.New("keyset paging requires at least one sorting field")
+	}
+	result := &KeysetPaging{Sorting: s}
+	if pageToken == "" {
+		return result, nil
+	}
+	js, err := base64.RawURLEncoding.DecodeString(pageToken)
+	if err != nil {
+		return nil, GdaoErrorInvalidPageToken
+	}
+	token := keysetToken{}
+	if json.Unmarshal(js, &token) != nil || len(token.Fields) != len(s.Fields) || len(token.Values) != len(s.Fields) {
+		return nil, GdaoErrorInvalidPageToken
+	}
+	result.LastValues = make([]interface{}, len(token.Values))
+	for i, v := range token.Values {
+		if token.Fields[i].Field != s.Fields[i].Field || token.Fields[i].Descending() != s.Fields[i].Descending() {
+			return nil, GdaoErrorInvalidPageToken
+		}
+		if result.LastValues[i], err = decodeKeysetValue(v); err != nil {
+			return nil, GdaoErrorInvalidPageToken
+		}
+	}
+	return result, nil
+}
+
+func encodeKeysetValue(v interface{}) (keysetValue, error) {
+	var t string
+	rv := reflect.ValueOf(v)
+	for ; rv.Kind() == reflect.Ptr && !rv.IsNil(); rv = rv.Elem() {
+		v = rv.Elem().Interface()
+	}
+	switch rv.Kind() {
+	case reflect.Bool:
+		t, v = "b", rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		t, v = "i", rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		t, v = "u", rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		t, v = "f", rv.Float()
+	case reflect.String:
+		t, v = "s", rv.String()
+	default:
+		if tv, ok := v.(time.Time); ok {
+			t, v = "t", tv.Format(time.RFC3339Nano)
+		} else {
+			return keysetValue{}, errors.New(fmt.Sprintf("unsupported type of sort value %T", v))
+		}
+	}
+	js, err := json.Marshal(v)
+	return keysetValue{Type: t, Value: js}, err
+}
+
+func decodeKeysetValue(v keysetValue) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(v.Value))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	switch v.Type {
+	case "b":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	case "i":
+		if n, ok := value.(json.Number); ok {
+			return strconv.ParseInt(n.String(), 10, 64)
+		}
+	case "u":
+		if n, ok := value.(json.Number); ok {
+			return strconv.ParseUint(n.String(), 10, 64)
+		}
+	case "f":
+		if n, ok := value.(json.Number); ok {
+			return n.Float64()
+		}
+	case "s":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "t":
+		if s, ok := value.(string); ok {
+			return time.Parse(time.RFC3339Nano, s)
+		}
+	}
+	return nil, errors.New(fmt.Sprintf("invalid sort value %s of type [%s]", v.Value, v.Type))
+}
+
+/*
+Filter returns the filter matching items that come after the last item of the previous page, or nil for the first page.
+
+For sorting (f1 ASC, f2 DESC) the filter is: f1 > v1 OR (f1 = v1 AND f2 < v2).
+*/
+func (p *KeysetPaging) Filter() *FilterOpt {
+	if p.LastValues == nil {
+		return nil
+	}
+	alternatives := make([]*FilterOpt, 0, len(p.Sorting.Fields))
+	for i, field := range p.Sorting.Fields {
+		conds := make([]*FilterOpt, 0, i+1)
+		for j := 0; j < i; j++ {
+			conds = append(conds, FilterEq(p.Sorting.Fields[j].Field, p.LastValues[j]))
+		}
+		if field.Descending() {
+			conds = append(conds, FilterLt(field.Field, p.LastValues[i]))
+		} else {
+			conds = append(conds, FilterGt(field.Field, p.LastValues[i]))
+		}
+		if len(conds) == 1 {
+			alternatives = append(alternatives, conds[0])
+		} else {
+			alternatives = append(alternatives, FilterAnd(conds...))
+		}
+	}
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	return FilterOr(alternatives...)
+}
+
+/*
+NextPageToken builds the token to fetch the page following the item 'row'.
+
+'row' is the last item of the current page, in the form of IRowMapper.ToRow (i.e. keyed by native field names).
+*/
+func (p *KeysetPaging) NextPageToken(row map[string]interface{}) (string, error) {
+	token := keysetToken{Fields: p.Sorting.Fields, Values: make([]keysetValue, len(p.Sorting.Fields))}
+	for i, field := range p.Sorting.Fields {
+		v, ok := row[field.Field]
+		if !ok || v == nil {
+			return "", errors.New(fmt.Sprintf("cannot build page token: sort field [%s] is null or absent", field.Field))
+		}
+		var err error
+		if token.Values[i], err = encodeKeysetValue(v); err != nil {
+			return "", err
+		}
+	}
+	js, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(js), nil
+}
@@ -0,0 +1,64 @@
+package godal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestKeysetPaging_Filter(t *testing.T) {
+	name := "TestKeysetPaging_Filter"
+	sorting := NewSortingOpt().Add("a", SortOrderAsc).Add("b", SortOrderDesc)
+
+	paging, err := NewKeysetPaging(sorting, "")
+	if err != nil || paging.Filter() != nil {
+		t.Fatalf("%s failed - Paging: %v / Error: %e", name, paging, err)
+	}
+
+	paging.LastValues = []interface{}{1, "x"}
+	expected := FilterOr(FilterGt("a", 1), FilterAnd(FilterEq("a", 1), FilterLt("b", "x")))
+	if f := paging.Filter(); !reflect.DeepEqual(f, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, f)
+	}
+}
+
+func TestKeysetPaging_PageToken(t *testing.T) {
+	name := "TestKeysetPaging_PageToken"
+	now := time.Now()
+	sorting := NewSortingOpt().Add("i", SortOrderAsc).Add("u", SortOrderAsc).Add("f", SortOrderAsc).
+		Add("s", SortOrderDesc).Add("b", SortOrderAsc).Add("t", SortOrderDesc)
+	row := map[string]interface{}{"i": int32(-12), "u": uint8(34), "f": 5.6, "s": "str", "b": true, "t": now, "other": "value"}
+	paging, _ := NewKeysetPaging(sorting, "")
+	token, err := paging.NextPageToken(row)
+	if err != nil || token == "" {
+		t.Fatalf("%s failed - Token: %v / Error: %e", name, token, err)
+	}
+
+	paging, err = NewKeysetPaging(*sorting, token)
+	if err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	expected := []interface{}{int64(-12), uint64(34), 5.6, "str", true}
+	if !reflect.DeepEqual(paging.LastValues[:5], expected) || !paging.LastValues[5].(time.Time).Equal(now) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, paging.LastValues)
+	}
+
+	otherSorting := NewSortingOpt().Add("i", SortOrderDesc).Add("u", SortOrderAsc).Add("f", SortOrderAsc).
+		Add("s", SortOrderDesc).Add("b", SortOrderAsc).Add("t", SortOrderDesc)
+	for _, token := range []string{token + "!", "e30", token[:len(token)-4]} {
+		if _, err := NewKeysetPaging(sorting, token); err != GdaoErrorInvalidPageToken {
+			t.Fatalf("%s failed - Expected error %e / Received: %e", name, GdaoErrorInvalidPageToken, err)
+		}
+	}
+	if _, err := NewKeysetPaging(otherSorting, token); err != GdaoErrorInvalidPageToken {
+		t.Fatalf("%s failed - Expected error %e / Received: %e", name, GdaoErrorInvalidPageToken, err)
+	}
+
+	delete(row, "s")
+	if _, err := paging.NextPageToken(row); err == nil {
+		t.Fatalf("%s failed - Expected error for absent sort field", name)
+	}
+	if _, err := NewKeysetPaging(map[string]int{"i": 1}, ""); err == nil {
+		t.Fatalf("%s failed - Expected error for non-SortingOpt sorting", name)
+	}
+}
@@ -0,0 +1,83 @@
+package godal
+
+/*
+PatchOperator identifies the operation of a PatchField.
+
+Available since v0.3.0
+*/
+type PatchOperator int
+
+const (
+	// PatchOpSet sets the field to the value.
+	PatchOpSet PatchOperator = iota
+	// PatchOpUnset removes the field (SQL: sets the column to NULL).
+	PatchOpUnset
+	// PatchOpIncrement adds the (numeric) value to the field; an absent/null field is treated as 0.
+	PatchOpIncrement
+	// PatchOpAppend appends the value as one element to the end of the (list) field; an absent/null field is treated as an empty list.
+	PatchOpAppend
+)
+
+/*
+PatchField is a {field, operator, value} triple of a PatchOpt.
+
+Available since v0.3.0
+*/
+type PatchField struct {
+	Field    string        // field to patch
+	Operator PatchOperator // operation to perform on the field
+	Value    interface{}   // value to set, to add or to append; not used by PatchOpUnset
+}
+
+/*
+PatchOpt is a backend-neutral partial update specification: only the listed fields of an existing BO are modified, other fields are
+left untouched (unlike GdaoUpdate, which replaces the whole BO). Hence concurrent writers patching different fields do not overwrite
+each other's changes.
+
+PatchOpt can be passed to GdaoPatch of all generic DAO implementations. Example:
+
+	// set name, remove note, increment version by 1
+	patch := godal.NewPatchOpt().Set("name", "New name").Unset("note").Increment("version", 1)
+	numRows, err := dao.GdaoPatch(storageId, bo, patch)
+
+Notes:
+
+	- Field is the native field name: column name for SQL, top-level field name for the in-memory DAO, document path for MongoDB,
+	  attribute path for DynamoDB.
+	- a field should be listed at most once.
+	- SQL does not support PatchOpAppend.
+
+Available since v0.3.0
+*/
+type PatchOpt struct {
+	Fields []PatchField
+}
+
+// NewPatchOpt creates a new PatchOpt instance.
+func NewPatchOpt(fields ...PatchField) *PatchOpt {
+	return &PatchOpt{Fields: append([]PatchField{}, fields...)}
+}
+
+// Set appends a "set field to value" operation.
+func (o *PatchOpt) Set(field string, value interface{}) *PatchOpt {
+	o.Fields = append(o.Fields, PatchField{Field: field, Operator: PatchOpSet, Value: value})
+	return o
+}
+
+// Unset appends a "remove field" operation.
+func (o *PatchOpt) Unset(field string) *PatchOpt {
+	o.Fields = append(o.Fields, PatchField{Field: field, Operator: PatchOpUnset})
+	return o
+}
+
+// Increment appends a "add delta to field" operation.
+func (o *PatchOpt) Increment(field string, delta interface{}) *PatchOpt {
+	o.Fields = append(o.Fields, PatchField{Field: field, Operator: PatchOpIncrement, Value: delta})
+	return o
+}
+
+// Append appends a "append value to the end of the list field" operation.
+func (o *PatchOpt) Append(field string, value interface{}) *PatchOpt {
+	o.Fields = append(o.Fields, PatchField{Field: field, Operator: PatchOpAppend, Value: value})
+	return o
+}
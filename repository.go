@@ -0,0 +1,348 @@
+package godal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// repoField is a struct field mapped to a BO attribute.
+type repoField struct {
+	index int    // index of the field in the struct
+	name  string // name of the BO attribute
+	key   bool   // true if the field is part of the BO's key
+}
+
+/*
+Repository maps business structs to/from IGenericBo and offers CRUD operations on native structs on top of an IGenericDao.
+
+Struct fields are mapped to BO attributes using tags 'godal:"name[,key]"':
+
+	type User struct {
+		Id       string    `godal:"id,key"`   // attribute "id", part of the key
+		Email    string    `godal:"email"`    // attribute "email"
+		Age      int                          // no tag: attribute "Age"
+		Password string    `godal:"-"`        // not mapped
+		Created  time.Time `godal:"created"`  // stored in its JSON form
+	}
+	repo, err := godal.NewRepository(dao, "users", User{})
+	_, err = repo.Create(&User{Id: "1", Email: "user@domain.com"})
+	obj, err := repo.Get("1")      // obj is a *User, nil if not found
+	user := obj.(*User)
+
+Notes:
+
+	- only exported fields are mapped; attribute names are top-level names (embedded structs are not flattened).
+	- field values are stored in their JSON form (same as GboImportViaJson/GboTransferViaJson).
+	- the struct must have at least one key field. Get builds a BO from the key values and finds it using the DAO's GdaoCreateFilter;
+	  GdaoCreateFilter of the DAO can be implemented by calling CreateFilter.
+
+Available since v0.3.0
+*/
+type Repository struct {
+	dao       IGenericDao
+	storageId string
+	typ       reflect.Type
+	fields    []repoField
+	keyFields []repoField
+}
+
+/*
+NewRepository creates a new Repository instance.
+
+	- dao: the DAO to perform operations.
+	- storageId: the storage (table/collection) the repository works on.
+	- proto: a struct (or pointer to struct) value, used as the prototype of the business objects.
+
+Available since v0.3.0
+*/
+func NewRepository(dao IGenericDao, storageId string, proto interface{}) (*Repository, error) {
+	typ := reflect.TypeOf(proto)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, errors.New(fmt.Sprintf("prototype must be a struct or pointer to struct, got [%T]", proto))
+	}
+	repo := &Repository{dao: dao, storageId: storageId, typ: typ}
+	for i, n := 0, typ.NumField(); i < n; i++ {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("godal")
+		if sf.PkgPath != "" || tag == "-" {
+			// unexported or ignored field
+			continue
+		}
+		tokens := strings.Split(tag, ",")
+		field := repoField{index: i, name: strings.TrimSpace(tokens[0])}
+		if field.name == "" {
+			field.name = sf.Name
+		}
+		for _, opt := range tokens[1:] {
+			if strings.TrimSpace(opt) == "key" {
+				field.key = true
+			}
+		}
+		repo.fields = append(repo.fields, field)
+		if field.key {
+			repo.keyFields = append(repo.keyFields, field)
+		}
+	}
+	if len(repo.keyFields) == 0 {
+		return nil, errors.New(fmt.Sprintf("struct [%s] has no key field", typ))
+	}
+	return repo, nil
+}
+
+// GetDao returns the DAO the repository works on.
+func (r *Repository) GetDao() IGenericDao {
+	return r.dao
+}
+
+// GetStorageId returns the storage the repository works on.
+func (r *Repository) GetStorageId() string {
+	return r.storageId
+}
+
+// KeyAttrs returns names of the key attributes, in the order they are declared in the struct.
+func (r *Repository) KeyAttrs() []string {
+	result := make([]string, len(r.keyFields))
+	for i, f := range r.keyFields {
+		result[i] = f.name
+	}
+	return result
+}
+
+// structValue returns the struct value of obj, which must be a struct or a (non-nil) pointer to struct of the prototype's type.
+func (r *Repository) structValue(obj interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(obj)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Type() != r.typ {
+		return reflect.Value{}, errors.New(fmt.Sprintf("expected [%s] or pointer to it, got [%T]", r.typ, obj))
+	}
+	return v, nil
+}
+
+/*
+ToBo transforms a struct (or pointer to struct) to IGenericBo.
+
+Available since v0.3.0
+*/
+func (r *Repository) ToBo(obj interface{}) (IGenericBo, error) {
+	if obj == nil || reflect.ValueOf(obj).Kind() == reflect.Ptr && reflect.ValueOf(obj).IsNil() {
+		return nil, nil
+	}
+	v, err := r.structValue(obj)
+	if err != nil {
+		return nil, err
+	}
+	data := make(map[string]interface{}, len(r.fields))
+	for _, f := range r.fields {
+		data[f.name] = v.Field(f.index).Interface()
+	}
+	bo := NewGenericBo()
+	return bo, bo.GboFromMap(data)
+}
+
+/*
+FromBo transforms an IGenericBo to a pointer to struct of the prototype's type. Attributes missing from the BO leave the corresponding
+fields at their zero values.
+
+Available since v0.3.0
+*/
+func (r *Repository) FromBo(bo IGenericBo) (interface{}, error) {
+	if bo == nil {
+		return nil, nil
+	}
+	ptr := reflect.New(r.typ)
+	for _, f := range r.fields {
+		value, err := bo.GboGetAttr(f.name, nil)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			continue
+		}
+		field := ptr.Elem().Field(f.index)
+		if js, err := json.Marshal(value); err == nil && json.Unmarshal(js, field.Addr().Interface()) == nil {
+			continue
+		}
+		// JSON transformation does not work (e.g. "1" stored for bool), try type conversion
+		value, err = bo.GboGetAttr(f.name, field.Type())
+		if rv := reflect.ValueOf(value); err != nil || !rv.IsValid() || !rv.Type().ConvertibleTo(field.Type()) {
+			return nil, errors.New(fmt.Sprintf("cannot convert attribute [%s] to [%s]: %v", f.name, field.Type(), err))
+		} else {
+			field.Set(rv.Convert(field.Type()))
+		}
+	}
+	return ptr.Interface(), nil
+}
+
+// fromBoList transforms a list of BOs to a slice of pointers to struct.
+func (r *Repository) fromBoList(boList []IGenericBo) (interface{}, error) {
+	result := reflect.MakeSlice(reflect.SliceOf(reflect.PtrTo(r.typ)), 0, len(boList))
+	for _, bo := range boList {
+		obj, err := r.FromBo(bo)
+		if err != nil {
+			return nil, err
+		}
+		result = reflect.Append(result, reflect.ValueOf(obj))
+	}
+	return result.Interface(), nil
+}
+
+/*
+CreateFilter creates a filter to match exactly a specific BO, using its key attributes: map[string]interface{}{keyAttr: value}.
+
+This function can be used to implement IGenericDao.GdaoCreateFilter:
+
+	func (dao *MyDao) GdaoCreateFilter(storageId string, bo godal.IGenericBo) interface{} {
+		return dao.repo.CreateFilter(bo)
+	}
+
+Available since v0.3.0
+*/
+func (r *Repository) CreateFilter(bo IGenericBo) interface{} {
+	filter := make(map[string]interface{}, len(r.keyFields))
+	for _, f := range r.keyFields {
+		filter[f.name] = bo.GboGetAttrUnsafe(f.name, nil)
+	}
+	return filter
+}
+
+// boOrError is a convenient function to transform obj to IGenericBo, nil obj is not allowed.
+func (r *Repository) boOrError(obj interface{}) (IGenericBo, error) {
+	bo, err := r.ToBo(obj)
+	if err == nil && bo == nil {
+		err = errors.New("nil object")
+	}
+	return bo, err
+}
+
+// writeBack copies the BO written to storage (e.g. carrying a new version, see AbstractGenericDao.SetVersionField) back to obj if it is a
+// pointer to struct.
+func (r *Repository) writeBack(obj interface{}, bo IGenericBo, numRows int, err error) (int, error) {
+	if err != nil || numRows == 0 {
+		return numRows, err
+	}
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr {
+		return numRows, nil
+	}
+	target, e := r.structValue(obj)
+	if e != nil || !target.CanSet() {
+		return numRows, nil
+	}
+	written, e := r.FromBo(bo)
+	if e != nil {
+		return numRows, e
+	}
+	target.Set(reflect.ValueOf(written).Elem())
+	return numRows, nil
+}
+
+/*
+Create persists a new struct to storage, see IGenericDao.GdaoCreate.
+
+If obj is a pointer to struct, the struct is updated with the BO written to storage (e.g. columns read back by the DAO).
+
+Available since v0.3.0
+*/
+func (r *Repository) Create(obj interface{}) (int, error) {
+	bo, err := r.boOrError(obj)
+	if err != nil {
+		return 0, err
+	}
+	numRows, err := r.dao.GdaoCreate(r.storageId, bo)
+	return r.writeBack(obj, bo, numRows, err)
+}
+
+/*
+Get fetches a struct by its key values (in the order the key fields are declared), see IGenericDao.GdaoFetchOne.
+
+This function returns a pointer to struct of the prototype's type, or nil if not found.
+
+Available since v0.3.0
+*/
+func (r *Repository) Get(keyValues ...interface{}) (interface{}, error) {
+	if len(keyValues) != len(r.keyFields) {
+		return nil, errors.New(fmt.Sprintf("expected %d key value(s), got %d", len(r.keyFields), len(keyValues)))
+	}
+	keys := make(map[string]interface{}, len(r.keyFields))
+	for i, f := range r.keyFields {
+		keys[f.name] = keyValues[i]
+	}
+	keyBo := NewGenericBo()
+	if err := keyBo.GboImportViaJson(keys); err != nil {
+		return nil, err
+	}
+	bo, err := r.dao.GdaoFetchOne(r.storageId, r.dao.GdaoCreateFilter(r.storageId, keyBo))
+	if err != nil || bo == nil {
+		return nil, err
+	}
+	return r.FromBo(bo)
+}
+
+/*
+Find fetches structs matching a filter, see IGenericDao.GdaoFetchMany.
+
+This function returns a slice of pointers to struct of the prototype's type.
+
+Available since v0.3.0
+*/
+func (r *Repository) Find(filter interface{}, sorting interface{}, startOffset, numItems int) (interface{}, error) {
+	boList, err := r.dao.GdaoFetchMany(r.storageId, filter, sorting, startOffset, numItems)
+	if err != nil {
+		return nil, err
+	}
+	return r.fromBoList(boList)
+}
+
+/*
+Update modifies an existing struct in storage, see IGenericDao.GdaoUpdate.
+
+If obj is a pointer to struct, the struct is updated with the BO written to storage (e.g. its new version if optimistic locking is
+enabled, see AbstractGenericDao.SetVersionField), hence can be updated again without being re-fetched.
+
+Available since v0.3.0
+*/
+func (r *Repository) Update(obj interface{}) (int, error) {
+	bo, err := r.boOrError(obj)
+	if err != nil {
+		return 0, err
+	}
+	numRows, err := r.dao.GdaoUpdate(r.storageId, bo)
+	return r.writeBack(obj, bo, numRows, err)
+}
+
+/*
+Save persists a struct to storage: update it if exists, otherwise create it, see IGenericDao.GdaoSave.
+
+If obj is a pointer to struct, the struct is updated with the BO written to storage, same as Update.
+
+Available since v0.3.0
+*/
+func (r *Repository) Save(obj interface{}) (int, error) {
+	bo, err := r.boOrError(obj)
+	if err != nil {
+		return 0, err
+	}
+	numRows, err := r.dao.GdaoSave(r.storageId, bo)
+	return r.writeBack(obj, bo, numRows, err)
+}
+
+/*
+Delete removes a struct from storage, see IGenericDao.GdaoDelete.
+
+Available since v0.3.0
+*/
+func (r *Repository) Delete(obj interface{}) (int, error) {
+	bo, err := r.boOrError(obj)
+	if err != nil {
+		return 0, err
+	}
+	return r.dao.GdaoDelete(r.storageId, bo)
+}
@@ -0,0 +1,106 @@
+package godal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type repoTestBo struct {
+	Id       string            `godal:"id,key"`
+	Email    string            `godal:"email"`
+	Age      int               // no tag
+	Active   bool              `godal:"active"`
+	Created  time.Time         `godal:"created"`
+	Tags     []string          `godal:"tags"`
+	Props    map[string]string `godal:"props"`
+	Password string            `godal:"-"`
+	internal string
+}
+
+func TestNewRepository(t *testing.T) {
+	name := "TestNewRepository"
+	for _, proto := range []interface{}{repoTestBo{}, &repoTestBo{}} {
+		repo, err := NewRepository(nil, "table", proto)
+		if err != nil || repo == nil {
+			t.Fatalf("%s failed - Repository: %v / Error: %e", name, repo, err)
+		}
+		if keys := repo.KeyAttrs(); !reflect.DeepEqual(keys, []string{"id"}) {
+			t.Fatalf("%s failed - Received: %v", name, keys)
+		}
+	}
+	type noKey struct {
+		Id string `godal:"id"`
+	}
+	for _, proto := range []interface{}{nil, 1, "string", noKey{}} {
+		if repo, err := NewRepository(nil, "table", proto); err == nil || repo != nil {
+			t.Fatalf("%s failed - expected error for prototype %#v", name, proto)
+		}
+	}
+}
+
+func TestRepository_ToBoFromBo(t *testing.T) {
+	name := "TestRepository_ToBoFromBo"
+	repo, _ := NewRepository(nil, "table", repoTestBo{})
+	now := time.Now().Round(time.Second)
+	obj := &repoTestBo{Id: "1", Email: "a@b.c", Age: 18, Active: true, Created: now, Tags: []string{"x", "y"},
+		Props: map[string]string{"k": "v"}, Password: "secret", internal: "internal"}
+
+	bo, err := repo.ToBo(obj)
+	if err != nil || bo == nil {
+		t.Fatalf("%s failed - Bo: %v / Error: %e", name, bo, err)
+	}
+	for attr, expected := range map[string]interface{}{"id": "1", "email": "a@b.c", "Age": 18.0, "active": true} {
+		if v := bo.GboGetAttrUnsafe(attr, nil); v != expected {
+			t.Fatalf("%s failed - Attr: %s / Expected: %#v / Received: %#v", name, attr, expected, v)
+		}
+	}
+	for _, attr := range []string{"Password", "internal"} {
+		if v := bo.GboGetAttrUnsafe(attr, nil); v != nil {
+			t.Fatalf("%s failed - Attr [%s] should not be mapped: %#v", name, attr, v)
+		}
+	}
+
+	result, err := repo.FromBo(bo)
+	if err != nil || result == nil {
+		t.Fatalf("%s failed - Result: %v / Error: %e", name, result, err)
+	}
+	expected := *obj
+	expected.Password, expected.internal = "", ""
+	if received := result.(*repoTestBo); !received.Created.Equal(now) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, now, received.Created)
+	} else if received.Created = now; !reflect.DeepEqual(*received, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, *received)
+	}
+
+	// type conversion
+	bo = NewGenericBo()
+	bo.GboImportViaJson(map[string]interface{}{"id": 1, "Age": "20", "active": "true"})
+	if result, err := repo.FromBo(bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	} else if received := result.(*repoTestBo); received.Id != "1" || received.Age != 20 || !received.Active {
+		t.Fatalf("%s failed - Received: %v", name, received)
+	}
+
+	if _, err := repo.ToBo(struct{ Id string }{}); err == nil {
+		t.Fatalf("%s failed - expected error for struct of other type", name)
+	}
+	if bo, err := repo.ToBo((*repoTestBo)(nil)); err != nil || bo != nil {
+		t.Fatalf("%s failed - Bo: %v / Error: %e", name, bo, err)
+	}
+}
+
+func TestRepository_CreateFilter(t *testing.T) {
+	name := "TestRepository_CreateFilter"
+	type compositeKey struct {
+		Tenant string `godal:"tenant,key"`
+		Id     int    `godal:"id,key"`
+		Name   string `godal:"name"`
+	}
+	repo, _ := NewRepository(nil, "table", compositeKey{})
+	bo, _ := repo.ToBo(compositeKey{Tenant: "t", Id: 1, Name: "n"})
+	expected := map[string]interface{}{"tenant": "t", "id": 1.0}
+	if filter := repo.CreateFilter(bo); !reflect.DeepEqual(filter, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, filter)
+	}
+}
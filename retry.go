@@ -0,0 +1,182 @@
+package godal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+/*
+RetryAttempt describes an attempt of an operation made under a RetryPolicy, see RetryPolicy.OnAttempt.
+
+Available since v0.3.0
+*/
+type RetryAttempt struct {
+	Attempt int           // the attempt number, starting from 1
+	Err     error         // error returned by the attempt, nil if the attempt succeeded
+	Retry   bool          // true if the operation is going to be retried
+	Delay   time.Duration // delay before the next attempt, if Retry is true
+}
+
+/*
+RetryPolicy specifies how operations failing with transient errors (e.g. deadlock, throttling) are retried.
+
+	- MaxAttempts: maximum number of attempts, including the first one; values less than 2 disable retrying.
+	- InitialBackoff: delay before the first retry; the delay is multiplied by Multiplier (2 if less than 1) after each retry,
+	  and capped at MaxBackoff (if positive).
+	- Jitter: in range [0, 1], delays are randomized in range [delay*(1-Jitter), delay].
+	- IsRetryable: classifier of retryable errors; if nil, the DAO's classifier is used (e.g. IsRetryableError).
+	- OnAttempt: if not nil, it is called after each attempt, so that retries can be observed (e.g. logged or counted).
+
+Available since v0.3.0
+*/
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	IsRetryable    func(err error) bool
+	OnAttempt      func(attempt RetryAttempt)
+}
+
+/*
+NewRetryPolicy creates a new RetryPolicy with maximum 'maxAttempts' attempts and default backoff settings: initial backoff 20ms,
+max backoff 1s, multiplier 2 and jitter 0.5.
+
+Available since v0.3.0
+*/
+func NewRetryPolicy(maxAttempts int) *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: 20 * time.Millisecond,
+		MaxBackoff:     1 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+// backoff calculates the delay before the retry following the attempt number 'attempt' (starting from 1).
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+/*
+Do calls fn, and retries it as long as it fails with a retryable error and the maximum number of attempts has not been reached.
+The error of the last attempt is returned. Retrying stops if ctx is done while waiting for the next attempt.
+
+	- isRetryable: classifier of retryable errors, used if the policy's IsRetryable is nil; IsRetryableError is used if both are nil.
+	- a nil policy calls fn once.
+
+Available since v0.3.0
+*/
+func (p *RetryPolicy) Do(ctx context.Context, isRetryable func(err error) bool, fn func() error) error {
+	if p == nil || p.MaxAttempts < 2 {
+		return fn()
+	}
+	if p.IsRetryable != nil {
+		isRetryable = p.IsRetryable
+	} else if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		retry := err != nil && attempt < p.MaxAttempts && isRetryable(err)
+		var delay time.Duration
+		if retry {
+			delay = p.backoff(attempt)
+		}
+		if p.OnAttempt != nil {
+			p.OnAttempt(RetryAttempt{Attempt: attempt, Err: err, Retry: retry, Delay: delay})
+		}
+		if !retry {
+			return err
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+/*
+IsRetryableError is the default classifier of retryable errors: deadlocks (ErrDeadlock), throttling (ErrThrottled) and concurrent
+modifications detected by the storage (ErrConcurrentModification, e.g. serialization failures or transaction write conflicts).
+
+Version mismatches detected by optimistic locking (GdaoErrorConcurrentModification) and timeouts are not retryable.
+
+Available since v0.3.0
+*/
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, GdaoErrorConcurrentModification) {
+		return false
+	}
+	return errors.Is(err, ErrDeadlock) || errors.Is(err, ErrThrottled) || errors.Is(err, ErrConcurrentModification)
+}
+
+/*
+SetRetryPolicy sets the policy to retry operations failing with transient errors, nil to disable retrying.
+
+Generic DAO implementations apply the policy to each call made to the storage outside of a transaction (a Gdao* call is made of
+one or several such calls), and to their transaction helpers (e.g. GdaoWithTransaction), which re-run the whole transaction function.
+Calls made inside a transaction are not retried individually. GenericDaoMemory never fails with transient errors, except for
+transactions failing to commit because of conflicting writes.
+
+Example:
+
+	policy := godal.NewRetryPolicy(5)
+	policy.OnAttempt = func(a godal.RetryAttempt) {
+		if a.Retry {
+			log.Printf("attempt #%d failed (%s), retrying in %s", a.Attempt, a.Err, a.Delay)
+		}
+	}
+	dao.SetRetryPolicy(policy)
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) SetRetryPolicy(policy *RetryPolicy) *AbstractGenericDao {
+	dao.retryLock.Lock()
+	defer dao.retryLock.Unlock()
+	dao.retryPolicy = policy
+	return dao
+}
+
+/*
+GetRetryPolicy returns the policy set by SetRetryPolicy, nil if retrying is disabled.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) GetRetryPolicy() *RetryPolicy {
+	dao.retryLock.RLock()
+	defer dao.retryLock.RUnlock()
+	return dao.retryPolicy
+}
+
+/*
+WithRetry calls fn under the DAO's retry policy (see SetRetryPolicy and RetryPolicy.Do); isRetryable is the DAO's classifier of
+retryable errors.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) WithRetry(ctx context.Context, isRetryable func(err error) bool, fn func() error) error {
+	return dao.GetRetryPolicy().Do(ctx, isRetryable, fn)
+}
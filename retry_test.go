@@ -0,0 +1,131 @@
+package godal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	name := "TestRetryPolicy_Backoff"
+	p := &RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt, expected := range []time.Duration{0, 10, 20, 40, 50, 50} {
+		if attempt == 0 {
+			continue
+		}
+		if delay := p.backoff(attempt); delay != expected*time.Millisecond {
+			t.Fatalf("%s failed - Attempt %d / Expected: %s / Received: %s", name, attempt, expected*time.Millisecond, delay)
+		}
+	}
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if delay := p.backoff(2); delay < 10*time.Millisecond || delay > 20*time.Millisecond {
+			t.Fatalf("%s failed - delay out of range: %s", name, delay)
+		}
+	}
+}
+
+func TestRetryPolicy_Do(t *testing.T) {
+	name := "TestRetryPolicy_Do"
+	errDeadlock := WrapError(ErrDeadlock, errors.New("deadlock"))
+	attempts := make([]RetryAttempt, 0)
+	p := &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, OnAttempt: func(a RetryAttempt) {
+		attempts = append(attempts, a)
+	}}
+
+	// retryable error, until max attempts is reached
+	numCalls := 0
+	err := p.Do(nil, nil, func() error {
+		numCalls++
+		return errDeadlock
+	})
+	if err != errDeadlock || numCalls != 3 || len(attempts) != 3 {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+	if !attempts[0].Retry || !attempts[1].Retry || attempts[2].Retry || attempts[2].Attempt != 3 || attempts[0].Delay != time.Millisecond {
+		t.Fatalf("%s failed - Attempts: %v", name, attempts)
+	}
+
+	// success after a retry
+	numCalls, attempts = 0, attempts[:0]
+	err = p.Do(nil, nil, func() error {
+		if numCalls++; numCalls < 2 {
+			return errDeadlock
+		}
+		return nil
+	})
+	if err != nil || numCalls != 2 || len(attempts) != 2 || attempts[1].Err != nil {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+
+	// non-retryable error
+	numCalls = 0
+	for _, e := range []error{errors.New("error"), GdaoErrorConcurrentModification, WrapError(ErrTimeout, errors.New("timeout"))} {
+		if err := p.Do(nil, nil, func() error { numCalls++; return e }); err != e || numCalls != 1 {
+			t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+		}
+		numCalls = 0
+	}
+
+	// classifiers
+	errCustom := errors.New("custom")
+	if err := p.Do(nil, func(err error) bool { return err == errCustom }, func() error { numCalls++; return errCustom }); err != errCustom || numCalls != 3 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+	numCalls = 0
+	p.IsRetryable = func(err error) bool { return false }
+	if err := p.Do(nil, IsRetryableError, func() error { numCalls++; return errDeadlock }); err != errDeadlock || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+	p.IsRetryable = nil
+
+	// context done while waiting
+	numCalls = 0
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.InitialBackoff = time.Hour
+	if err := p.Do(ctx, nil, func() error { numCalls++; return errDeadlock }); err != errDeadlock || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+
+	// nil policy
+	numCalls = 0
+	if err := (*RetryPolicy)(nil).Do(nil, nil, func() error { numCalls++; return errDeadlock }); err != errDeadlock || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+}
+
+func TestIsRetryableError(t *testing.T) {
+	name := "TestIsRetryableError"
+	native := errors.New("native")
+	for _, err := range []error{WrapError(ErrDeadlock, native), WrapError(ErrThrottled, native), WrapError(ErrConcurrentModification, native)} {
+		if !IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be retryable", name, err)
+		}
+	}
+	for _, err := range []error{nil, native, GdaoErrorConcurrentModification, GdaoErrorDuplicatedEntry, WrapError(ErrTimeout, native)} {
+		if IsRetryableError(err) {
+			t.Fatalf("%s failed - %e is expected to be non-retryable", name, err)
+		}
+	}
+}
+
+func TestAbstractGenericDao_SetRetryPolicy(t *testing.T) {
+	name := "TestAbstractGenericDao_SetRetryPolicy"
+	dao := NewAbstractGenericDao(nil)
+	errThrottled := WrapError(ErrThrottled, errors.New("throttled"))
+	numCalls := 0
+	if err := dao.WithRetry(nil, nil, func() error { numCalls++; return errThrottled }); err != errThrottled || numCalls != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+	policy := NewRetryPolicy(2)
+	policy.InitialBackoff = time.Millisecond
+	if dao.SetRetryPolicy(policy).GetRetryPolicy() != policy {
+		t.Fatalf("%s failed - unexpected retry policy", name)
+	}
+	numCalls = 0
+	if err := dao.WithRetry(nil, nil, func() error { numCalls++; return errThrottled }); err != errThrottled || numCalls != 2 {
+		t.Fatalf("%s failed - NumCalls: %d / Error: %e", name, numCalls, err)
+	}
+}
@@ -0,0 +1,457 @@
+package godal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/*
+IBoValidator validates BOs before they are persisted, see AbstractGenericDao.SetValidator.
+
+Validate returns nil if the BO is valid, an error otherwise (preferably a *ValidationError).
+
+Available since v0.3.0
+*/
+type IBoValidator interface {
+	Validate(storageId string, bo IGenericBo) error
+}
+
+/*
+IPatchValidator can be implemented by validators attached to storages (see AbstractGenericDao.SetValidator) to validate patches before
+they are written by GdaoPatch; BoSchema implements it.
+
+ValidatePatch returns nil if the patch is valid, an error otherwise (preferably a *ValidationError). 'bo' only identifies the patched BO.
+
+Available since v0.3.0
+*/
+type IPatchValidator interface {
+	ValidatePatch(storageId string, bo IGenericBo, patch *PatchOpt) error
+}
+
+/*
+BoValidatorFunc is an adapter to use an ordinary function as IBoValidator.
+
+Available since v0.3.0
+*/
+type BoValidatorFunc func(storageId string, bo IGenericBo) error
+
+// Validate implements IBoValidator.Validate.
+func (f BoValidatorFunc) Validate(storageId string, bo IGenericBo) error {
+	return f(storageId, bo)
+}
+
+/*
+ValidationViolation is an offending path found by a validator.
+
+Available since v0.3.0
+*/
+type ValidationViolation struct {
+	Path    string // path of the offending attribute, e.g. "options.workhour[0].value"; "" means the BO itself
+	Message string // description of the violation
+}
+
+/*
+ValidationError is returned by DAOs when a BO fails validation (see AbstractGenericDao.SetValidator), it lists all offending paths.
+
+Available since v0.3.0
+*/
+type ValidationError struct {
+	StorageId  string
+	Violations []ValidationViolation
+}
+
+// Error implements error.Error.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		path := v.Path
+		if path == "" {
+			path = "(root)"
+		}
+		msgs[i] = path + ": " + v.Message
+	}
+	return fmt.Sprintf("validation failed for storage [%s]: %s", e.StorageId, strings.Join(msgs, "; "))
+}
+
+const (
+	// SchemaTypeNull is the schema type of null values.
+	SchemaTypeNull = "null"
+	// SchemaTypeBoolean is the schema type of boolean values.
+	SchemaTypeBoolean = "boolean"
+	// SchemaTypeNumber is the schema type of numeric values.
+	SchemaTypeNumber = "number"
+	// SchemaTypeInteger is the schema type of numeric values without fractional part.
+	SchemaTypeInteger = "integer"
+	// SchemaTypeString is the schema type of string values.
+	SchemaTypeString = "string"
+	// SchemaTypeArray is the schema type of lists.
+	SchemaTypeArray = "array"
+	// SchemaTypeObject is the schema type of maps.
+	SchemaTypeObject = "object"
+)
+
+var schemaTypes = map[string]bool{SchemaTypeNull: true, SchemaTypeBoolean: true, SchemaTypeNumber: true, SchemaTypeInteger: true,
+	SchemaTypeString: true, SchemaTypeArray: true, SchemaTypeObject: true}
+
+/*
+BoSchema is a schema of BOs, it implements IBoValidator. A schema can be defined in Go code:
+
+	min, maxLen := 0.0, 100
+	schema := &godal.BoSchema{
+		Type:     godal.SchemaTypeObject,
+		Required: []string{"id", "email"},
+		Properties: map[string]*godal.BoSchema{
+			"id":     {Type: godal.SchemaTypeString, Pattern: "^[0-9a-f]+$"},
+			"email":  {Type: godal.SchemaTypeString, MaxLength: &maxLen},
+			"age":    {Type: godal.SchemaTypeInteger, Minimum: &min},
+			"status": {Enum: []interface{}{"active", "disabled"}},
+			"tags":   {Type: godal.SchemaTypeArray, Items: &godal.BoSchema{Type: godal.SchemaTypeString}},
+		},
+	}
+	dao.SetValidator("users", schema)
+
+or parsed from a JSON-Schema document, see ParseBoSchema.
+
+Notes:
+
+	- the BO is validated in its JSON-equivalent form (see IGenericBo.GboToMap): numbers are float64, structs are objects, etc.
+	- the supported subset of JSON-Schema is: type (a single type), required, properties, items, enum, minimum, maximum,
+	  minLength, maxLength, minItems, maxItems and pattern. Empty/nil constraints are not checked.
+	- properties not listed in Properties are allowed; a required property that is present with null value satisfies Required.
+	- Pattern is a Go regular expression (see package regexp), matched against any part of the string unless anchored.
+
+Available since v0.3.0
+*/
+type BoSchema struct {
+	Type       string               `json:"type,omitempty"`
+	Required   []string             `json:"required,omitempty"`
+	Properties map[string]*BoSchema `json:"properties,omitempty"`
+	Items      *BoSchema            `json:"items,omitempty"`
+	Enum       []interface{}        `json:"enum,omitempty"`
+	Minimum    *float64             `json:"minimum,omitempty"`
+	Maximum    *float64             `json:"maximum,omitempty"`
+	MinLength  *int                 `json:"minLength,omitempty"`
+	MaxLength  *int                 `json:"maxLength,omitempty"`
+	MinItems   *int                 `json:"minItems,omitempty"`
+	MaxItems   *int                 `json:"maxItems,omitempty"`
+	Pattern    string               `json:"pattern,omitempty"`
+}
+
+/*
+ParseBoSchema parses a JSON-Schema document (see BoSchema for the supported subset). Keywords not supported are ignored.
+
+Available since v0.3.0
+*/
+func ParseBoSchema(js []byte) (*BoSchema, error) {
+	schema := &BoSchema{}
+	if err := json.Unmarshal(js, schema); err != nil {
+		return nil, err
+	}
+	return schema, schema.check("")
+}
+
+// check verifies that the schema is well-formed.
+func (s *BoSchema) check(path string) error {
+	if s.Type != "" && !schemaTypes[s.Type] {
+		return errors.New(fmt.Sprintf("invalid type [%s] at [%s]", s.Type, path))
+	}
+	if s.Pattern != "" {
+		if _, err := compilePattern(s.Pattern); err != nil {
+			return errors.New(fmt.Sprintf("invalid pattern at [%s]: %s", path, err))
+		}
+	}
+	for name, prop := range s.Properties {
+		if prop == nil {
+			continue
+		}
+		if err := prop.check(joinSchemaPath(path, name)); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+var patternCache sync.Map // pattern -> *regexp.Regexp
+
+// compilePattern compiles a regular expression, compiled expressions are cached.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, re)
+	return re, nil
+}
+
+// joinSchemaPath builds the path of a property.
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+/*
+Validate implements IBoValidator.Validate.
+
+This function returns nil if bo is valid, a *ValidationError listing all violations otherwise.
+*/
+func (s *BoSchema) Validate(storageId string, bo IGenericBo) error {
+	if bo == nil {
+		return nil
+	}
+	data, err := bo.GboToMap()
+	if err != nil {
+		return err
+	}
+	var violations []ValidationViolation
+	s.validate("", data, &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{StorageId: storageId, Violations: violations}
+}
+
+/*
+ValidatePatch implements IPatchValidator.ValidatePatch.
+
+Fields of the patch are resolved as paths of properties (e.g. "options.workhour" is the property "workhour" of the property "options"):
+
+	- the value of PatchOpSet must be valid against the property's schema.
+	- a required property can not be removed by PatchOpUnset.
+	- PatchOpIncrement requires a numeric property, and PatchOpAppend a list property whose items schema the value must be valid against.
+	- fields without schema are not checked, and neither are constraints on the resulting value (e.g. maximum after increment).
+
+This function returns nil if the patch is valid, a *ValidationError listing all violations otherwise.
+*/
+func (s *BoSchema) ValidatePatch(storageId string, bo IGenericBo, patch *PatchOpt) error {
+	if patch == nil {
+		return nil
+	}
+	var violations []ValidationViolation
+	for _, f := range patch.Fields {
+		parent, prop := s.propertyOf(f.Field)
+		switch f.Operator {
+		case PatchOpSet:
+			if prop != nil {
+				value, err := toJsonValue(f.Value)
+				if err != nil {
+					return err
+				}
+				prop.validate(f.Field, value, &violations)
+			}
+		case PatchOpUnset:
+			if parent == nil {
+				break
+			}
+			name := f.Field[strings.LastIndex(f.Field, ".")+1:]
+			for _, required := range parent.Required {
+				if required == name {
+					violations = append(violations, ValidationViolation{Path: f.Field, Message: "required attribute can not be unset"})
+				}
+			}
+		case PatchOpIncrement:
+			if prop != nil && prop.Type != "" && prop.Type != SchemaTypeNumber && prop.Type != SchemaTypeInteger {
+				violations = append(violations, ValidationViolation{Path: f.Field, Message: fmt.Sprintf("can not increment attribute of type [%s]", prop.Type)})
+			}
+		case PatchOpAppend:
+			if prop != nil && prop.Type != "" && prop.Type != SchemaTypeArray {
+				violations = append(violations, ValidationViolation{Path: f.Field, Message: fmt.Sprintf("can not append to attribute of type [%s]", prop.Type)})
+			} else if prop != nil && prop.Items != nil {
+				value, err := toJsonValue(f.Value)
+				if err != nil {
+					return err
+				}
+				prop.Items.validate(f.Field+"[]", value, &violations)
+			}
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &ValidationError{StorageId: storageId, Violations: violations}
+}
+
+// propertyOf returns the schema of the property at 'path' and the schema of its parent (nil if not found).
+func (s *BoSchema) propertyOf(path string) (parent, prop *BoSchema) {
+	prop = s
+	for _, name := range strings.Split(path, ".") {
+		if parent = prop; parent == nil {
+			return nil, nil
+		}
+		prop = parent.Properties[name]
+	}
+	return parent, prop
+}
+
+// schemaTypeOf returns the schema type of a JSON-equivalent value.
+func schemaTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return SchemaTypeNull
+	case bool:
+		return SchemaTypeBoolean
+	case float64:
+		return SchemaTypeNumber
+	case string:
+		return SchemaTypeString
+	case []interface{}:
+		return SchemaTypeArray
+	case map[string]interface{}:
+		return SchemaTypeObject
+	}
+	return ""
+}
+
+// validate checks a JSON-equivalent value against the schema and appends violations found.
+func (s *BoSchema) validate(path string, value interface{}, violations *[]ValidationViolation) {
+	addViolation := func(path, format string, a ...interface{}) {
+		*violations = append(*violations, ValidationViolation{Path: path, Message: fmt.Sprintf(format, a...)})
+	}
+	typ := schemaTypeOf(value)
+	if s.Type != "" {
+		f, isNumber := value.(float64)
+		if s.Type != typ && !(s.Type == SchemaTypeInteger && isNumber && f == math.Trunc(f)) {
+			addViolation(path, "expected type [%s], got [%s]", s.Type, typ)
+			return
+		}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if ev, err := toJsonValue(e); err == nil && reflect.DeepEqual(ev, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			addViolation(path, "value %v is not one of %v", value, s.Enum)
+		}
+	}
+	switch v := value.(type) {
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			addViolation(path, "value %v is less than minimum %v", v, *s.Minimum)
+		}
+		if s.Maximum != nil && v > *s.Maximum {
+			addViolation(path, "value %v is greater than maximum %v", v, *s.Maximum)
+		}
+	case string:
+		length := utf8.RuneCountInString(v)
+		if s.MinLength != nil && length < *s.MinLength {
+			addViolation(path, "length %d is less than minLength %d", length, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			addViolation(path, "length %d is greater than maxLength %d", length, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := compilePattern(s.Pattern); err != nil {
+				addViolation(path, "invalid pattern [%s]: %s", s.Pattern, err)
+			} else if !re.MatchString(v) {
+				addViolation(path, "value %q does not match pattern [%s]", v, s.Pattern)
+			}
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(v) < *s.MinItems {
+			addViolation(path, "number of items %d is less than minItems %d", len(v), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(v) > *s.MaxItems {
+			addViolation(path, "number of items %d is greater than maxItems %d", len(v), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	case map[string]interface{}:
+		required := append([]string{}, s.Required...)
+		sort.Strings(required)
+		for _, name := range required {
+			if _, ok := v[name]; !ok {
+				addViolation(joinSchemaPath(path, name), "required attribute is missing")
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if prop, ok := v[name]; ok && s.Properties[name] != nil {
+				s.Properties[name].validate(joinSchemaPath(path, name), prop, violations)
+			}
+		}
+	}
+}
+
+/*
+SetValidator attaches a validator to a storage; pass nil to remove it. The validator is run by GdaoCreate, GdaoCreateMany, GdaoUpdate,
+GdaoUpdateDirty, GdaoSave and GdaoSaveMany (and their context-aware variants) of all generic DAO implementations before the BO is
+written; a BO failing validation is not written and the validator's error is returned (or reported in the BO's GdaoBulkResult).
+If the validator implements IPatchValidator, GdaoPatch validates the patch before writing it.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) SetValidator(storageId string, validator IBoValidator) *AbstractGenericDao {
+	dao.validatorLock.Lock()
+	defer dao.validatorLock.Unlock()
+	if dao.validators == nil {
+		dao.validators = make(map[string]IBoValidator)
+	}
+	if validator == nil {
+		delete(dao.validators, storageId)
+	} else {
+		dao.validators[storageId] = validator
+	}
+	return dao
+}
+
+/*
+GetValidator returns the validator attached to a storage, nil if none (see SetValidator).
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) GetValidator(storageId string) IBoValidator {
+	dao.validatorLock.RLock()
+	defer dao.validatorLock.RUnlock()
+	return dao.validators[storageId]
+}
+
+/*
+ValidateBo validates a BO using the validator attached to the storage (see SetValidator), nil is returned if the storage has no validator.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) ValidateBo(storageId string, bo IGenericBo) error {
+	if validator := dao.GetValidator(storageId); validator != nil && bo != nil {
+		return validator.Validate(storageId, bo)
+	}
+	return nil
+}
+
+/*
+ValidatePatch validates a patch using the validator attached to the storage (see SetValidator) if it implements IPatchValidator, nil is
+returned otherwise.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) ValidatePatch(storageId string, bo IGenericBo, patch *PatchOpt) error {
+	if validator, ok := dao.GetValidator(storageId).(IPatchValidator); ok && patch != nil {
+		return validator.ValidatePatch(storageId, bo, patch)
+	}
+	return nil
+}
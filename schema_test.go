@@ -0,0 +1,157 @@
+package godal
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBoSchema(t *testing.T) {
+	name := "TestParseBoSchema"
+	js := `{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string", "pattern": "^[a-z]+$", "minLength": 1, "maxLength": 8},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"tags": {"type": "array", "maxItems": 2, "items": {"enum": ["a", "b", "c"]}}
+		},
+		"$schema": "ignored"
+	}`
+	schema, err := ParseBoSchema([]byte(js))
+	if err != nil || schema == nil {
+		t.Fatalf("%s failed - Schema: %v / Error: %e", name, schema, err)
+	}
+	if schema.Type != SchemaTypeObject || !reflect.DeepEqual(schema.Required, []string{"id"}) || len(schema.Properties) != 3 {
+		t.Fatalf("%s failed - Received: %v", name, schema)
+	}
+	if p := schema.Properties["age"]; p.Type != SchemaTypeInteger || *p.Minimum != 0 || *p.Maximum != 150 {
+		t.Fatalf("%s failed - Received: %v", name, p)
+	}
+	if p := schema.Properties["tags"]; *p.MaxItems != 2 || len(p.Items.Enum) != 3 {
+		t.Fatalf("%s failed - Received: %v", name, p)
+	}
+
+	for _, js := range []string{`{"type": "unknown"}`, `{"properties": {"a": {"pattern": "["}}}`, `{"items": {"type": 1}}`, `[]`} {
+		if _, err := ParseBoSchema([]byte(js)); err == nil {
+			t.Fatalf("%s failed - expected error for schema %s", name, js)
+		}
+	}
+}
+
+func TestBoSchema_Validate(t *testing.T) {
+	name := "TestBoSchema_Validate"
+	min, max, maxLen, maxItems := 0.0, 150.0, 8, 2
+	schema := &BoSchema{
+		Type:     SchemaTypeObject,
+		Required: []string{"id", "email"},
+		Properties: map[string]*BoSchema{
+			"id":     {Type: SchemaTypeString, Pattern: "^[a-z]+$", MaxLength: &maxLen},
+			"age":    {Type: SchemaTypeInteger, Minimum: &min, Maximum: &max},
+			"status": {Enum: []interface{}{"active", 1}},
+			"tags":   {Type: SchemaTypeArray, MaxItems: &maxItems, Items: &BoSchema{Type: SchemaTypeString}},
+			"opts":   {Type: SchemaTypeObject, Required: []string{"x"}},
+		},
+	}
+
+	bo := NewGenericBo()
+	bo.GboImportViaJson(map[string]interface{}{"id": "abc", "email": nil, "age": 30, "status": 1, "tags": []string{"t"},
+		"opts": map[string]interface{}{"x": 1}, "other": true})
+	if err := schema.Validate("table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+
+	bo.GboImportViaJson(map[string]interface{}{"id": "ABC123456", "age": 30.5, "status": "x", "tags": []interface{}{"a", 1, "c"},
+		"opts": map[string]interface{}{}})
+	err := schema.Validate("table", bo)
+	vErr, ok := err.(*ValidationError)
+	if !ok || vErr.StorageId != "table" {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	paths := make([]string, len(vErr.Violations))
+	for i, v := range vErr.Violations {
+		paths[i] = v.Path
+	}
+	expected := []string{"email", "age", "id", "id", "opts.x", "status", "tags", "tags[1]"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v (%s)", name, expected, paths, err)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "table") || !strings.Contains(msg, "tags[1]: expected type [string], got [number]") {
+		t.Fatalf("%s failed - Received: %s", name, msg)
+	}
+
+	if err := (&BoSchema{Type: SchemaTypeArray}).Validate("table", bo); err == nil {
+		t.Fatalf("%s failed - expected error for root of wrong type", name)
+	}
+	if err := schema.Validate("table", nil); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+}
+
+func TestBoSchema_ValidatePatch(t *testing.T) {
+	name := "TestBoSchema_ValidatePatch"
+	maxLen := 8
+	schema := &BoSchema{
+		Type:     SchemaTypeObject,
+		Required: []string{"id"},
+		Properties: map[string]*BoSchema{
+			"id":   {Type: SchemaTypeString, MaxLength: &maxLen},
+			"age":  {Type: SchemaTypeInteger},
+			"tags": {Type: SchemaTypeArray, Items: &BoSchema{Type: SchemaTypeString}},
+			"opts": {Type: SchemaTypeObject, Required: []string{"x"}, Properties: map[string]*BoSchema{"x": {Type: SchemaTypeString}}},
+		},
+	}
+	patch := NewPatchOpt().Set("id", "abc").Increment("age", 1).Append("tags", "t").Set("opts.x", "x").Unset("age").Set("other", 1)
+	if err := schema.ValidatePatch("table", nil, patch); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+
+	patch = NewPatchOpt().Set("id", "abcdefghij").Unset("id").Increment("tags", 1).Append("age", 1).Append("tags", 1).Set("opts.x", 1).Unset("opts.x")
+	err := schema.ValidatePatch("table", nil, patch)
+	vErr, ok := err.(*ValidationError)
+	if !ok || vErr.StorageId != "table" {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	paths := make([]string, len(vErr.Violations))
+	for i, v := range vErr.Violations {
+		paths[i] = v.Path
+	}
+	expected := []string{"id", "id", "tags", "age", "tags[]", "opts.x", "opts.x"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v (%s)", name, expected, paths, err)
+	}
+	if err := schema.ValidatePatch("table", nil, nil); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+}
+
+func TestAbstractGenericDao_SetValidator(t *testing.T) {
+	name := "TestAbstractGenericDao_SetValidator"
+	dao := NewAbstractGenericDao(nil)
+	bo := NewGenericBo()
+	if v, err := dao.GetValidator("table"), dao.ValidateBo("table", bo); v != nil || err != nil {
+		t.Fatalf("%s failed - Validator: %v / Error: %e", name, v, err)
+	}
+	errInvalid := errors.New("invalid")
+	dao.SetValidator("table", BoValidatorFunc(func(storageId string, bo IGenericBo) error {
+		if bo.GboGetAttrUnsafe("valid", nil) != true {
+			return errInvalid
+		}
+		return nil
+	}))
+	if err := dao.ValidateBo("table", bo); err != errInvalid {
+		t.Fatalf("%s failed - Expected error: %e / Received: %e", name, errInvalid, err)
+	}
+	bo.GboSetAttr("valid", true)
+	if err := dao.ValidateBo("table", bo); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if err := dao.ValidateBo("other", NewGenericBo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	dao.SetValidator("table", nil)
+	if v := dao.GetValidator("table"); v != nil {
+		t.Fatalf("%s failed - Validator: %v", name, v)
+	}
+}
@@ -0,0 +1,59 @@
+package godal
+
+/*
+SortingOrder specifies the direction to sort a field.
+
+Available since v0.3.0
+*/
+type SortingOrder int
+
+const (
+	// SortOrderAsc sorts a field in ascending order.
+	SortOrderAsc SortingOrder = 1
+	// SortOrderDesc sorts a field in descending order.
+	SortOrderDesc SortingOrder = -1
+)
+
+/*
+SortingField is a {field, order} pair of a SortingOpt.
+
+Available since v0.3.0
+*/
+type SortingField struct {
+	Field string       // field to sort on
+	Order SortingOrder // 'order>=0' means 'ascending' and 'order<0' means 'descending'
+}
+
+// Descending returns true if the field is sorted in descending order.
+func (f SortingField) Descending() bool {
+	return f.Order < 0
+}
+
+/*
+SortingOpt is a backend-neutral, ordered sorting specification: items are sorted by the first field, then by the second field, and so on.
+
+SortingOpt can be passed to GdaoFetchMany of all generic DAO implementations. Example:
+
+	// ORDER BY status DESC, name ASC
+	sorting := godal.NewSortingOpt().Add("status", godal.SortOrderDesc).Add("name", godal.SortOrderAsc)
+	boList, err := dao.GdaoFetchMany(storageId, filter, sorting, 0, 0)
+
+Note: Field is the native field name (column name for SQL, document path for MongoDB, attribute path for DynamoDB). Backends that can not
+serve the sorting (e.g. DynamoDB can only sort by the sort key) return error.
+
+Available since v0.3.0
+*/
+type SortingOpt struct {
+	Fields []SortingField
+}
+
+// NewSortingOpt creates a new SortingOpt instance.
+func NewSortingOpt(fields ...SortingField) *SortingOpt {
+	return &SortingOpt{Fields: append([]SortingField{}, fields...)}
+}
+
+// Add appends a field to the sorting list.
+func (o *SortingOpt) Add(field string, order SortingOrder) *SortingOpt {
+	o.Fields = append(o.Fields, SortingField{Field: field, Order: order})
+	return o
+}
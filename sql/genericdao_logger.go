@@ -0,0 +1,238 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+	"github.com/btnguyen2k/prom"
+	"strings"
+	"time"
+)
+
+/*
+SqlLogLevel is the level of a statement log entry, see ISqlStatementLogger.
+
+Available: since v0.3.0
+*/
+type SqlLogLevel int
+
+/*
+Predefined log levels.
+
+Available: since v0.3.0
+*/
+const (
+	// SqlLogLevelInfo is the level of statements that completed within the slow statement threshold.
+	SqlLogLevelInfo SqlLogLevel = iota
+
+	// SqlLogLevelWarn is the level of statements that took longer than the slow statement threshold.
+	SqlLogLevelWarn
+)
+
+// String implements fmt.Stringer.
+func (l SqlLogLevel) String() string {
+	if l == SqlLogLevelWarn {
+		return "WARN"
+	}
+	return "INFO"
+}
+
+/*
+SqlStatementLogEntry describes a SQL statement executed by GenericDaoSql.
+
+Available: since v0.3.0
+*/
+type SqlStatementLogEntry struct {
+	Level     SqlLogLevel   // SqlLogLevelWarn if the statement is slow, SqlLogLevelInfo otherwise
+	Operation string        // "SqlExecute" or "SqlQuery"
+	Flavor    prom.DbFlavor // database flavor of the DAO
+	Statement string        // the final SQL statement, with placeholders
+	Values    []interface{} // values bound to the placeholders, redacted according to the DAO's redaction rules
+	Columns   []string      // columns bound to the placeholders ("" if unknown), nil if the statement was not built by the DAO
+	InTx      bool          // true if the statement was executed inside a transaction
+	Duration  time.Duration // duration of the execution, including retries
+	Err       error         // error returned by the execution
+}
+
+// String returns a one-line description of the entry, e.g. "[INFO] SqlExecute (1.2ms) DELETE FROM t WHERE id=? [1]".
+func (e *SqlStatementLogEntry) String() string {
+	tx := ""
+	if e.InTx {
+		tx = ", tx"
+	}
+	s := fmt.Sprintf("[%s] %s (%s%s) %s %v", e.Level, e.Operation, e.Duration, tx, e.Statement, e.Values)
+	if e.Err != nil {
+		s += " error: " + e.Err.Error()
+	}
+	return s
+}
+
+/*
+ISqlStatementLogger receives the SQL statements executed by GenericDaoSql, see GenericDaoSql.SetStatementLogger.
+
+Available: since v0.3.0
+*/
+type ISqlStatementLogger interface {
+	LogStatement(ctx context.Context, entry *SqlStatementLogEntry)
+}
+
+/*
+SqlStatementLoggerFunc is an adapter to use an ordinary function as ISqlStatementLogger.
+
+Example:
+
+	dao.SetStatementLogger(sql.SqlStatementLoggerFunc(func(ctx context.Context, entry *sql.SqlStatementLogEntry) {
+		log.Println(entry)
+	}))
+
+Available: since v0.3.0
+*/
+type SqlStatementLoggerFunc func(ctx context.Context, entry *SqlStatementLogEntry)
+
+// LogStatement implements ISqlStatementLogger.LogStatement.
+func (f SqlStatementLoggerFunc) LogStatement(ctx context.Context, entry *SqlStatementLogEntry) {
+	f(ctx, entry)
+}
+
+/*
+RedactFunc transforms a value bound to a sensitive column before it is logged, see GenericDaoSql.AddRedactionRule.
+
+Available: since v0.3.0
+*/
+type RedactFunc func(value interface{}) interface{}
+
+/*
+RedactMask is a RedactFunc that replaces non-nil values with "***".
+
+Available: since v0.3.0
+*/
+func RedactMask(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return "***"
+}
+
+/*
+GetStatementLogger returns the logger set by SetStatementLogger.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetStatementLogger() ISqlStatementLogger {
+	return dao.statementLogger
+}
+
+/*
+SetStatementLogger sets the logger receiving every SQL statement executed by SqlExecute/SqlQuery (hence by all Gdao* functions),
+nil to disable logging.
+
+	- the logger receives the statement, the values bound to it (redacted, see AddRedactionRule), the database flavor, the duration
+	  and whether the statement was executed inside a transaction.
+	- statements taking longer than the slow statement threshold (see SetSlowStatementThreshold) are logged at SqlLogLevelWarn.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetStatementLogger(logger ISqlStatementLogger) *GenericDaoSql {
+	dao.statementLogger = logger
+	return dao
+}
+
+/*
+GetSlowStatementThreshold returns the threshold set by SetSlowStatementThreshold.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetSlowStatementThreshold() time.Duration {
+	return dao.slowStatementThreshold
+}
+
+/*
+SetSlowStatementThreshold sets the duration from which statements are logged at SqlLogLevelWarn; zero (default) disables warnings.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetSlowStatementThreshold(threshold time.Duration) *GenericDaoSql {
+	dao.slowStatementThreshold = threshold
+	return dao
+}
+
+/*
+AddRedactionRule adds a rule to redact logged values bound to a column (case-insensitive), see SetStatementLogger.
+
+	- redact: transforms the value before it is logged; RedactMask is used if nil.
+	- column "*" matches all values not matched by other rules, including values whose column is unknown (statements passed to
+	  SqlExecute/SqlQuery directly).
+	- only logged values are redacted, values bound to the executed statement are intact.
+
+Example:
+
+	dao.AddRedactionRule("password", nil).AddRedactionRule("email", func(v interface{}) interface{} { return "<email>" })
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) AddRedactionRule(column string, redact RedactFunc) *GenericDaoSql {
+	if redact == nil {
+		redact = RedactMask
+	}
+	rules := make(map[string]RedactFunc, len(dao.redactionRules)+1)
+	for k, v := range dao.redactionRules {
+		rules[k] = v
+	}
+	rules[strings.ToLower(column)] = redact
+	dao.redactionRules = rules
+	return dao
+}
+
+// redactValues returns a copy of values, redacted according to the DAO's redaction rules; columns[i] is the column bound to values[i].
+func (dao *GenericDaoSql) redactValues(columns []string, values []interface{}) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		column := ""
+		if i < len(columns) {
+			column = strings.ToLower(columns[i])
+		}
+		if redact, ok := dao.redactionRules[column]; ok && column != "" {
+			result[i] = redact(v)
+		} else if redact, ok := dao.redactionRules["*"]; ok {
+			result[i] = redact(v)
+		} else {
+			result[i] = v
+		}
+	}
+	return result
+}
+
+// logStatement reports an executed statement to the DAO's statement logger, if any.
+func (dao *GenericDaoSql) logStatement(ctx context.Context, operation, sqlStm string, columns []string, values []interface{}, inTx bool, duration time.Duration, err error) {
+	logger := dao.statementLogger
+	if logger == nil {
+		return
+	}
+	entry := &SqlStatementLogEntry{
+		Level:     SqlLogLevelInfo,
+		Operation: operation,
+		Flavor:    dao.sqlFlavor,
+		Statement: sqlStm,
+		Values:    dao.redactValues(columns, values),
+		Columns:   columns,
+		InTx:      inTx,
+		Duration:  duration,
+		Err:       err,
+	}
+	if dao.slowStatementThreshold > 0 && duration >= dao.slowStatementThreshold {
+		entry.Level = SqlLogLevelWarn
+	}
+	logger.LogStatement(ctx, entry)
+}
+
+// placeholderGenerator returns the placeholder generator to build statements with: the one created by funcNewPlaceholderGenerator
+// if set, defaultGen otherwise. The column bound to each placeholder is recorded into 'columns', so that logged values can be redacted.
+func (dao *GenericDaoSql) placeholderGenerator(defaultGen PlaceholderGenerator, columns *[]string) PlaceholderGenerator {
+	gen := defaultGen
+	if dao.funcNewPlaceholderGenerator != nil {
+		gen = dao.funcNewPlaceholderGenerator()
+	}
+	return func(field string) string {
+		*columns = append(*columns, field)
+		return gen(field)
+	}
+}
@@ -0,0 +1,115 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"github.com/btnguyen2k/prom"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactMask(t *testing.T) {
+	name := "TestRedactMask"
+	if v := RedactMask("secret"); v != "***" {
+		t.Fatalf("%s failed - Received: %v", name, v)
+	}
+	if v := RedactMask(nil); v != nil {
+		t.Fatalf("%s failed - Received: %v", name, v)
+	}
+}
+
+func TestGenericDaoSql_RedactValues(t *testing.T) {
+	name := "TestGenericDaoSql_RedactValues"
+	dao := &GenericDaoSql{sqlFlavor: prom.FlavorMySql}
+	values := []interface{}{"1", "secret", "a@b.c", nil}
+	columns := []string{"id", "Password", "email", "password"}
+	if redacted := dao.redactValues(columns, values); !reflect.DeepEqual(redacted, values) {
+		t.Fatalf("%s failed - Received: %v", name, redacted)
+	}
+
+	dao.AddRedactionRule("PASSWORD", nil).AddRedactionRule("email", func(v interface{}) interface{} { return "<email>" })
+	expected := []interface{}{"1", "***", "<email>", nil}
+	if redacted := dao.redactValues(columns, values); !reflect.DeepEqual(redacted, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, redacted)
+	}
+	if values[1] != "secret" {
+		t.Fatalf("%s failed - original values must be intact: %v", name, values)
+	}
+
+	// "*" matches values not matched by other rules, including values whose column is unknown
+	dao.AddRedactionRule("*", func(v interface{}) interface{} { return "?" })
+	expected = []interface{}{"?", "***", "<email>", nil, "?"}
+	if redacted := dao.redactValues(columns, append(values, "x")); !reflect.DeepEqual(redacted, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, redacted)
+	}
+}
+
+func TestGenericDaoSql_PlaceholderGenerator(t *testing.T) {
+	name := "TestGenericDaoSql_PlaceholderGenerator"
+	dao := &GenericDaoSql{sqlFlavor: prom.FlavorPgSql}
+	builder := NewUpdateBuilder().WithFlavor(dao.sqlFlavor).WithTable("t").
+		WithValues(map[string]interface{}{"password": "secret"}).AddIncrements(map[string]interface{}{"version": 1}).
+		WithFilter((&FilterAnd{}).Add(&FilterFieldValue{Field: "id", Operation: "=", Value: "1"}).Add(&FilterIn{Field: "status", Values: []interface{}{1, 2}}))
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	sqlStm, values := builder.Build()
+	if expected := []string{"password", "version", "id", "status", "status"}; !reflect.DeepEqual(bindColumns, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, bindColumns)
+	}
+	if len(values) != len(bindColumns) || !strings.Contains(sqlStm, "$5") {
+		t.Fatalf("%s failed - Statement: %s / Values: %v", name, sqlStm, values)
+	}
+
+	// funcNewPlaceholderGenerator takes precedence over the builder's placeholder generator
+	dao.funcNewPlaceholderGenerator = NewPlaceholderGeneratorQuestion
+	bindColumns = nil
+	builder = NewUpdateBuilder().WithFlavor(dao.sqlFlavor).WithTable("t").WithValues(map[string]interface{}{"password": "secret"})
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	if sqlStm, _ := builder.Build(); sqlStm != "UPDATE t SET password=?" || len(bindColumns) != 1 {
+		t.Fatalf("%s failed - Statement: %s / Columns: %v", name, sqlStm, bindColumns)
+	}
+}
+
+func TestGenericDaoSql_LogStatement(t *testing.T) {
+	name := "TestGenericDaoSql_LogStatement"
+	dao := &GenericDaoSql{sqlFlavor: prom.FlavorMySql}
+	entries := make([]*SqlStatementLogEntry, 0)
+	dao.logStatement(nil, "SqlExecute", "DELETE FROM t WHERE id=?", []string{"id"}, []interface{}{"1"}, false, time.Millisecond, nil)
+
+	dao.SetStatementLogger(SqlStatementLoggerFunc(func(ctx context.Context, entry *SqlStatementLogEntry) {
+		entries = append(entries, entry)
+	})).SetSlowStatementThreshold(10*time.Millisecond).AddRedactionRule("password", nil)
+	if dao.GetStatementLogger() == nil || dao.GetSlowStatementThreshold() != 10*time.Millisecond {
+		t.Fatalf("%s failed - unexpected settings", name)
+	}
+	dao.logStatement(nil, "SqlExecute", "UPDATE t SET password=? WHERE id=?", []string{"password", "id"}, []interface{}{"secret", "1"}, true, time.Millisecond, nil)
+	errTest := errors.New("test")
+	dao.logStatement(nil, "SqlQuery", "SELECT * FROM t", nil, nil, false, 20*time.Millisecond, errTest)
+	if len(entries) != 2 {
+		t.Fatalf("%s failed - expected 2 entries but received %d", name, len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != SqlLogLevelInfo || entry.Operation != "SqlExecute" || entry.Flavor != prom.FlavorMySql || !entry.InTx ||
+		!reflect.DeepEqual(entry.Values, []interface{}{"***", "1"}) || entry.Duration != time.Millisecond || entry.Err != nil {
+		t.Fatalf("%s failed - Entry: %v", name, entry)
+	}
+	if expected := "[INFO] SqlExecute (1ms, tx) UPDATE t SET password=? WHERE id=? [*** 1]"; entry.String() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, entry.String())
+	}
+	entry = entries[1]
+	if entry.Level != SqlLogLevelWarn || entry.Operation != "SqlQuery" || entry.InTx || entry.Err != errTest {
+		t.Fatalf("%s failed - Entry: %v", name, entry)
+	}
+	if expected := "[WARN] SqlQuery (20ms) SELECT * FROM t [] error: test"; entry.String() != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, entry.String())
+	}
+
+	// zero threshold disables warnings
+	dao.SetSlowStatementThreshold(0)
+	dao.logStatement(nil, "SqlQuery", "SELECT * FROM t", nil, nil, false, time.Hour, nil)
+	if entries[2].Level != SqlLogLevelInfo {
+		t.Fatalf("%s failed - Entry: %v", name, entries[2])
+	}
+}
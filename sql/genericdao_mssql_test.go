@@ -117,6 +117,26 @@ func TestGenericDaoMssql_GdaoFetchManyWithPaging(t *testing.T) {
 	testGenericDao_GdaoFetchManyWithPaging(dao, dao.tableName, t)
 }
 
+func TestGenericDaoMssql_GdaoFetchIterator(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoFetchIterator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_GdaoFetchPage(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoFetchPage(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_GdaoCountExists(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoCountExists(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_GdaoCreateManySaveMany(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoCreateManySaveMany(dao, dao.tableName, t)
+}
+
 func TestGenericDaoMssql_GdaoUpdateNotExist(t *testing.T) {
 	dao := initDaoMssql()
 	testGenericDao_GdaoUpdateNotExist(dao, dao.tableName, t)
@@ -132,6 +152,69 @@ func TestGenericDaoMssql_GdaoUpdate(t *testing.T) {
 	testGenericDao_GdaoUpdate(dao, dao.tableName, t)
 }
 
+func TestGenericDaoMssql_GdaoPatch(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoPatch(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_GdaoUpdateDirty(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoUpdateDirty(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_Validator(t *testing.T) {
+	dao := initDaoMssql()
+	dao.SetValidator(dao.tableName, myBoValidator)
+	testGenericDao_Validator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_Hooks(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_Hooks(dao, dao.AbstractGenericDao, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_RetryPolicy(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_RetryPolicy(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_Instrumentation(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_Instrumentation(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_StatementLogger(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_StatementLogger(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_StmtCache(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_StmtCache(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_GdaoSaveUpsert(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_GdaoSaveUpsert(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_Returning(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_Returning(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_Transaction(t *testing.T) {
+	dao := initDaoMssql()
+	other := createMssqlConnect()
+	defer other.Close()
+	testGenericDao_Transaction(dao.GenericDaoSql, createDaoMssql(other, dao.tableName).GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMssql_OptimisticLocking(t *testing.T) {
+	dao := initDaoMssql()
+	testGenericDao_OptimisticLocking(dao.GenericDaoSql, dao.tableName, t)
+}
+
 func TestGenericDaoMssql_GdaoSaveDuplicated_TxModeOff(t *testing.T) {
 	dao := initDaoMssql()
 	dao.SetTxModeOnWrite(false).SetTxIsolationLevel(sql.LevelDefault)
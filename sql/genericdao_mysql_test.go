@@ -115,6 +115,26 @@ func TestGenericDaoMysql_GdaoFetchManyWithPaging(t *testing.T) {
 	testGenericDao_GdaoFetchManyWithPaging(dao, dao.tableName, t)
 }
 
+func TestGenericDaoMysql_GdaoFetchIterator(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoFetchIterator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_GdaoFetchPage(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoFetchPage(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_GdaoCountExists(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoCountExists(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_GdaoCreateManySaveMany(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoCreateManySaveMany(dao, dao.tableName, t)
+}
+
 func TestGenericDaoMysql_GdaoUpdateNotExist(t *testing.T) {
 	dao := initDaoMysql()
 	testGenericDao_GdaoUpdateNotExist(dao, dao.tableName, t)
@@ -130,6 +150,69 @@ func TestGenericDaoMysql_GdaoUpdate(t *testing.T) {
 	testGenericDao_GdaoUpdate(dao, dao.tableName, t)
 }
 
+func TestGenericDaoMysql_GdaoPatch(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoPatch(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_GdaoUpdateDirty(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoUpdateDirty(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_Validator(t *testing.T) {
+	dao := initDaoMysql()
+	dao.SetValidator(dao.tableName, myBoValidator)
+	testGenericDao_Validator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_Hooks(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_Hooks(dao, dao.AbstractGenericDao, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_RetryPolicy(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_RetryPolicy(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_Instrumentation(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_Instrumentation(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_StatementLogger(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_StatementLogger(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_StmtCache(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_StmtCache(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_GdaoSaveUpsert(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_GdaoSaveUpsert(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_Returning(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_Returning(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_Transaction(t *testing.T) {
+	dao := initDaoMysql()
+	other := createMysqlConnect()
+	defer other.Close()
+	testGenericDao_Transaction(dao.GenericDaoSql, createDaoMysql(other, dao.tableName).GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoMysql_OptimisticLocking(t *testing.T) {
+	dao := initDaoMysql()
+	testGenericDao_OptimisticLocking(dao.GenericDaoSql, dao.tableName, t)
+}
+
 func TestGenericDaoMysql_GdaoSaveDuplicated_TxModeOff(t *testing.T) {
 	dao := initDaoMysql()
 	dao.SetTxModeOnWrite(false).SetTxIsolationLevel(sql.LevelDefault)
@@ -125,6 +125,26 @@ func TestGenericDaoOracle_GdaoFetchManyWithPaging(t *testing.T) {
 	testGenericDao_GdaoFetchManyWithPaging(dao, dao.tableName, t)
 }
 
+func TestGenericDaoOracle_GdaoFetchIterator(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoFetchIterator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_GdaoFetchPage(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoFetchPage(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_GdaoCountExists(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoCountExists(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_GdaoCreateManySaveMany(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoCreateManySaveMany(dao, dao.tableName, t)
+}
+
 func TestGenericDaoOracle_GdaoUpdateNotExist(t *testing.T) {
 	dao := initDaoOracle()
 	testGenericDao_GdaoUpdateNotExist(dao, dao.tableName, t)
@@ -140,6 +160,69 @@ func TestGenericDaoOracle_GdaoUpdate(t *testing.T) {
 	testGenericDao_GdaoUpdate(dao, dao.tableName, t)
 }
 
+func TestGenericDaoOracle_GdaoPatch(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoPatch(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_GdaoUpdateDirty(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoUpdateDirty(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_Validator(t *testing.T) {
+	dao := initDaoOracle()
+	dao.SetValidator(dao.tableName, myBoValidator)
+	testGenericDao_Validator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_Hooks(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_Hooks(dao, dao.AbstractGenericDao, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_RetryPolicy(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_RetryPolicy(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_Instrumentation(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_Instrumentation(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_StatementLogger(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_StatementLogger(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_StmtCache(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_StmtCache(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_GdaoSaveUpsert(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_GdaoSaveUpsert(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_Returning(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_Returning(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_Transaction(t *testing.T) {
+	dao := initDaoOracle()
+	other := createOracleConnect()
+	defer other.Close()
+	testGenericDao_Transaction(dao.GenericDaoSql, createDaoOracle(other, dao.tableName).GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoOracle_OptimisticLocking(t *testing.T) {
+	dao := initDaoOracle()
+	testGenericDao_OptimisticLocking(dao.GenericDaoSql, dao.tableName, t)
+}
+
 func TestGenericDaoOracle_GdaoSaveDuplicated_TxModeOff(t *testing.T) {
 	dao := initDaoOracle()
 	dao.SetTxModeOnWrite(false).SetTxIsolationLevel(sql.LevelDefault)
@@ -117,6 +117,26 @@ func TestGenericDaoPgsql_GdaoFetchManyWithPaging(t *testing.T) {
 	testGenericDao_GdaoFetchManyWithPaging(dao, dao.tableName, t)
 }
 
+func TestGenericDaoPgsql_GdaoFetchIterator(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoFetchIterator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_GdaoFetchPage(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoFetchPage(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_GdaoCountExists(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoCountExists(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_GdaoCreateManySaveMany(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoCreateManySaveMany(dao, dao.tableName, t)
+}
+
 func TestGenericDaoPgsql_GdaoUpdateNotExist(t *testing.T) {
 	dao := initDaoPgsql()
 	testGenericDao_GdaoUpdateNotExist(dao, dao.tableName, t)
@@ -132,6 +152,69 @@ func TestGenericDaoPgsql_GdaoUpdate(t *testing.T) {
 	testGenericDao_GdaoUpdate(dao, dao.tableName, t)
 }
 
+func TestGenericDaoPgsql_GdaoPatch(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoPatch(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_GdaoUpdateDirty(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoUpdateDirty(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_Validator(t *testing.T) {
+	dao := initDaoPgsql()
+	dao.SetValidator(dao.tableName, myBoValidator)
+	testGenericDao_Validator(dao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_Hooks(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_Hooks(dao, dao.AbstractGenericDao, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_RetryPolicy(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_RetryPolicy(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_Instrumentation(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_Instrumentation(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_StatementLogger(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_StatementLogger(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_StmtCache(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_StmtCache(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_GdaoSaveUpsert(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_GdaoSaveUpsert(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_Returning(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_Returning(dao.GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_Transaction(t *testing.T) {
+	dao := initDaoPgsql()
+	other := createPgsqlConnect()
+	defer other.Close()
+	testGenericDao_Transaction(dao.GenericDaoSql, createDaoPgsql(other, dao.tableName).GenericDaoSql, dao.tableName, t)
+}
+
+func TestGenericDaoPgsql_OptimisticLocking(t *testing.T) {
+	dao := initDaoPgsql()
+	testGenericDao_OptimisticLocking(dao.GenericDaoSql, dao.tableName, t)
+}
+
 func TestGenericDaoPgsql_GdaoSaveDuplicated_TxModeOff(t *testing.T) {
 	dao := initDaoPgsql()
 	dao.SetTxModeOnWrite(false).SetTxIsolationLevel(sql.LevelDefault)
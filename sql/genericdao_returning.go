@@ -0,0 +1,155 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/prom"
+	"strings"
+)
+
+/*
+GetReturningColumns returns the columns read back after writes to a table, nil if not set (see SetReturningColumns).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetReturningColumns(table string) []string {
+	return dao.returningColumns[table]
+}
+
+/*
+SetReturningColumns sets the columns read back after GdaoCreate/GdaoUpdate wrote a row to a table (e.g. auto-increment ids, defaults
+or trigger-populated columns), no column to unset them. The BO passed to GdaoCreate/GdaoUpdate is then populated with the returned
+values: the written row, overlaid with the returned values, is transformed by the row mapper (see IRowMapper.ToBo) and merged into
+the BO. Returned values do not make a clean BO dirty (see godal.IGenericBo.GboIsDirty).
+
+	- PostgreSQL: INSERT/UPDATE ... RETURNING <columns>; "*" reads back all columns.
+	- MSSQL: INSERT/UPDATE ... OUTPUT INSERTED.<column>[,...]; "*" reads back all columns. Note: MSSQL rejects OUTPUT clauses on tables
+	  having enabled triggers.
+	- Oracle: INSERT/UPDATE ... RETURNING <columns> INTO <placeholders>; returned values are strings, "*" is not supported.
+	- MySQL: the first column receives the id generated by an INSERT (sql.Result.LastInsertId), nothing is read back after an UPDATE.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetReturningColumns(table string, columns ...string) *GenericDaoSql {
+	returningColumns := make(map[string][]string, len(dao.returningColumns)+1)
+	for k, v := range dao.returningColumns {
+		returningColumns[k] = v
+	}
+	if len(columns) == 0 {
+		delete(returningColumns, table)
+	} else {
+		returningColumns[table] = append([]string{}, columns...)
+	}
+	dao.returningColumns = returningColumns
+	return dao
+}
+
+// insertReturning inserts a row and reads back the returning columns of the table, see SetReturningColumns.
+func (dao *GenericDaoSql) insertReturning(ctx context.Context, tx *sql.Tx, table string, colsAndVals map[string]interface{}, returning []string) (int64, map[string]interface{}, error) {
+	builder := NewInsertBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithValues(colsAndVals).WithReturning(returning...)
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	sqlStm, values := builder.Build()
+	return dao.execReturning(ctx, tx, sqlStm, bindColumns, values, returning, true)
+}
+
+// updateReturning updates rows and reads back the returning columns of the table, see SetReturningColumns.
+func (dao *GenericDaoSql) updateReturning(ctx context.Context, tx *sql.Tx, table string, colsAndVals map[string]interface{}, filter IFilter, returning []string) (int64, map[string]interface{}, error) {
+	builder := NewUpdateBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithValues(colsAndVals).WithFilter(filter).WithReturning(returning...)
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	sqlStm, values := builder.Build()
+	return dao.execReturning(ctx, tx, sqlStm, bindColumns, values, returning, false)
+}
+
+// execReturning executes an INSERT/UPDATE statement built with returning columns, and returns the number of written rows along with
+// the values read back from the first written row (nil if none).
+func (dao *GenericDaoSql) execReturning(ctx context.Context, tx *sql.Tx, sqlStm string, bindColumns []string, values []interface{}, returning []string, isInsert bool) (int64, map[string]interface{}, error) {
+	switch dao.sqlFlavor {
+	case prom.FlavorPgSql, prom.FlavorMsSql:
+		// returned columns come as a result set
+		dbRows, err := dao.sqlQuery(ctx, tx, sqlStm, bindColumns, values)
+		if err != nil {
+			return 0, nil, err
+		}
+		defer dbRows.Close()
+		var numRows int64
+		var returned map[string]interface{}
+		e := dao.sqlConnect.FetchRowsCallback(dbRows, func(row map[string]interface{}, e error) bool {
+			if e != nil {
+				err = e
+				return false
+			}
+			if numRows++; returned == nil {
+				returned = row
+			}
+			return true
+		})
+		if err == nil {
+			err = e
+		}
+		return numRows, returned, dao.TranslateError(err)
+	}
+
+	result, err := dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values)
+	if err != nil {
+		return 0, nil, err
+	}
+	numRows, err := result.RowsAffected()
+	if err != nil || numRows == 0 {
+		return numRows, nil, err
+	}
+	switch dao.sqlFlavor {
+	case prom.FlavorOracle:
+		// returned columns are bound to the trailing sql.Out values
+		returned := make(map[string]interface{})
+		outValues := values[len(values)-len(returning):]
+		for i, col := range returning {
+			if out, ok := outValues[i].(sql.Out); ok {
+				returned[col] = *out.Dest.(*string)
+			}
+		}
+		return numRows, returned, nil
+	case prom.FlavorMySql:
+		if id, err := result.LastInsertId(); isInsert && err == nil && id > 0 && returning[0] != "*" {
+			return numRows, map[string]interface{}{returning[0]: id}, nil
+		}
+	}
+	return numRows, nil, nil
+}
+
+// populateReturned merges the values read back after a write into bo: the written row, overlaid with the returned values (columns are
+// matched case-insensitively), is transformed by the row mapper. Values read back are not changes made by the caller: if bo was clean
+// (see godal.IGenericBo.GboIsDirty), it is still clean afterwards.
+func (dao *GenericDaoSql) populateReturned(storageId string, bo godal.IGenericBo, colsAndVals, returned map[string]interface{}) error {
+	if len(returned) == 0 {
+		return nil
+	}
+	row := make(map[string]interface{}, len(colsAndVals)+len(returned))
+	for k, v := range colsAndVals {
+		row[k] = v
+	}
+	for k, v := range returned {
+		key := k
+		for col := range colsAndVals {
+			if strings.EqualFold(col, k) {
+				key = col
+				break
+			}
+		}
+		row[key] = v
+	}
+	gbo, err := dao.GetRowMapper().ToBo(storageId, row)
+	if err != nil || gbo == nil {
+		return err
+	}
+	clean := !bo.GboIsDirty()
+	if err := bo.GboMerge(gbo, godal.MergeOverwrite); err != nil {
+		return err
+	}
+	if clean {
+		bo.GboMarkClean()
+	}
+	return nil
+}
@@ -40,6 +40,7 @@ Implementation rules:
 	  - If field is uint (uint8 to uint64): its value is converted to uint64
 	  - If field is float32 or float64: its value is converted to float64
 	  - Field is one of other types: its value is converted to JSON string
+	  - Field is nil, or has been removed via godal.IGenericBo.GboDeleteAttr: its value is converted to nil (NULL)
 	- ToBo: expect input is a map[string]interface{}, transform it to godal.IGenericBo. Column/Field names are transformed according to 'NameTransformation' setting and 'ColNameToGboFieldTranslator'
 	- ColumnsList: lookup column-list from a 'columns-list map', returns []string{"*"} if not found
 */
@@ -130,6 +131,8 @@ func (mapper *GenericRowMapperSql) ToRow(storageId string, gbo godal.IGenericBo)
 		for ; v.Kind() == reflect.Ptr; v = v.Elem() {
 		}
 		switch v.Kind() {
+		case reflect.Invalid:
+			row[colName] = nil
 		case reflect.Bool:
 			row[colName] = v.Bool()
 		case reflect.String:
@@ -152,6 +155,10 @@ func (mapper *GenericRowMapperSql) ToRow(storageId string, gbo godal.IGenericBo)
 			}
 		}
 	})
+	for _, field := range gbo.GboDeletedAttrs() {
+		// removed attributes are set to NULL
+		row[mapper.translateGboFieldToColName(storageId, mapper.transformName(field))] = nil
+	}
 	return row, err
 }
 
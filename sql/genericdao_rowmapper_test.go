@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/godal"
+	"reflect"
 	"testing"
 )
 
@@ -368,6 +369,24 @@ func TestGenericRowMapperSql_ToRow_UpperCase(t *testing.T) {
 	}
 }
 
+func TestGenericRowMapperSql_ToRow_DeletedAttrs(t *testing.T) {
+	name := "TestGenericRowMapperSql_ToRow_DeletedAttrs"
+	rm := &GenericRowMapperSql{NameTransformation: NameTransfLowerCase,
+		GboFieldToColNameTranslator: map[string]map[string]interface{}{"table_name": {"colb": "b"}}}
+	gbo := godal.NewGenericBo()
+	gbo.GboFromJson([]byte(`{"ColA":1,"colb":"a string","COLC":null}`))
+	gbo.GboDeleteAttr("colb")
+
+	row, err := rm.ToRow("table_name", gbo)
+	if err != nil || row == nil {
+		t.Fatalf("%s failed: error: %#v", name, err)
+	}
+	expected := map[string]interface{}{"cola": 1.0, "b": nil, "colc": nil}
+	if !reflect.DeepEqual(row, expected) {
+		t.Fatalf("%s failed: expected %#v but received %#v", name, expected, row)
+	}
+}
+
 func TestGenericRowMapperSql_ToGbo_Intact(t *testing.T) {
 	name := "TestGenericRowMapperSql_ToGbo_Intact"
 	rm := &GenericRowMapperSql{NameTransformation: NameTransfIntact}
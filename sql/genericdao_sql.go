@@ -134,6 +134,11 @@ import (
 	"github.com/btnguyen2k/prom"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 /*
@@ -165,9 +170,19 @@ Function implementations (n = No, y = Yes, i = inherited):
 	(y) GdaoDeleteMany(storageId string, filter interface{}) (int, error)
 	(y) GdaoFetchOne(storageId string, filter interface{}) (godal.IGenericBo, error)
 	(y) GdaoFetchMany(storageId string, filter interface{}, ordering interface{}, fromOffset, numItems int) ([]godal.IGenericBo, error)
+	(y) GdaoFetchIterator(storageId string, filter interface{}, ordering interface{}) (godal.IGenericBoIterator, error)
+	(y) GdaoFetchPage(storageId string, filter interface{}, ordering interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error)
+	(y) GdaoCount(storageId string, filter interface{}) (int64, error)
+	(y) GdaoExists(storageId string, filter interface{}) (bool, error)
 	(y) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
 	(y) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error)
+	(y) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error)
 	(y) GdaoSave(storageId string, bo godal.IGenericBo) (int, error)
+	(y) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error)
+
+GenericDaoSql also implements godal.IGenericDaoWithContext and godal.ITransactionalDao (since v0.3.0).
 */
 type GenericDaoSql struct {
 	*godal.AbstractGenericDao
@@ -177,6 +192,14 @@ type GenericDaoSql struct {
 	txIsolationLevel            sql.IsolationLevel
 	optionOpLiteral             *OptionOpLiteral
 	funcNewPlaceholderGenerator NewPlaceholderGenerator
+	statementLogger             ISqlStatementLogger
+	slowStatementThreshold      time.Duration
+	redactionRules              map[string]RedactFunc
+	stmtCache                   *stmtCache
+	noPrepare                   bool
+	keyColumns                  map[string][]string
+	mysqlUpsertTables           map[string]bool
+	returningColumns            map[string][]string
 }
 
 /*
@@ -192,6 +215,7 @@ SetSqlConnect attaches a '*prom.SqlConnect' instance to this DAO.
 Available since v0.0.2
 */
 func (dao *GenericDaoSql) SetSqlConnect(sqlC *prom.SqlConnect) *GenericDaoSql {
+	dao.ClearStmtCache()
 	dao.sqlConnect = sqlC
 	return dao
 }
@@ -331,15 +355,94 @@ func (dao *GenericDaoSql) SetFuncNewPlaceholderGenerator(funcNewPlaceholderGener
 	return dao
 }
 
+/*
+GetKeyColumns returns the key columns of a table, nil if not set (see SetKeyColumns).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetKeyColumns(table string) []string {
+	return dao.keyColumns[table]
+}
+
+/*
+SetKeyColumns sets the columns of a table's primary (or unique) key, no column to unset them.
+
+When the key columns of a table are known, GdaoSave writes rows to the table with a single "insert-or-update" statement
+(see UpsertBuilder) instead of an UPDATE followed by an INSERT, provided that:
+
+	- the database flavor is PostgreSQL, MSSQL or Oracle. MySQL's ON DUPLICATE KEY UPDATE fires on any unique key of the table,
+	  which would silently overwrite another row violating a secondary unique key: it must be enabled explicitly, see SetMySqlUpsert.
+	- optimistic locking is not enabled on the table (see godal.AbstractGenericDao.SetVersionField).
+	- all key columns are present in the row (see IRowMapper.ToRow).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetKeyColumns(table string, columns ...string) *GenericDaoSql {
+	keyColumns := make(map[string][]string, len(dao.keyColumns)+1)
+	for k, v := range dao.keyColumns {
+		keyColumns[k] = v
+	}
+	if len(columns) == 0 {
+		delete(keyColumns, table)
+	} else {
+		keyColumns[table] = append([]string{}, columns...)
+	}
+	dao.keyColumns = keyColumns
+	return dao
+}
+
+/*
+GetMySqlUpsert returns true if GdaoSave writes rows to a MySQL table with INSERT ... ON DUPLICATE KEY UPDATE (see SetMySqlUpsert).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetMySqlUpsert(table string) bool {
+	return dao.mysqlUpsertTables[table]
+}
+
+/*
+SetMySqlUpsert enables/disables the native "insert-or-update" statement (INSERT ... ON DUPLICATE KEY UPDATE) of GdaoSave on a MySQL
+table whose key columns are known (see SetKeyColumns); it is disabled by default.
+
+Enable it only if the key columns form the only unique key of the table (primary key included): ON DUPLICATE KEY UPDATE fires on any
+unique key, hence a row violating another unique key would silently overwrite the existing row instead of being reported as
+godal.GdaoErrorDuplicatedEntry.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetMySqlUpsert(table string, enabled bool) *GenericDaoSql {
+	mysqlUpsertTables := make(map[string]bool, len(dao.mysqlUpsertTables)+1)
+	for k, v := range dao.mysqlUpsertTables {
+		mysqlUpsertTables[k] = v
+	}
+	if enabled {
+		mysqlUpsertTables[table] = true
+	} else {
+		delete(mysqlUpsertTables, table)
+	}
+	dao.mysqlUpsertTables = mysqlUpsertTables
+	return dao
+}
+
 /*
 BuildFilter builds IFilter instance based on the following rules:
 
 	- If 'filter' is nil: return nil
 	- If 'filter' is IFilter: return 'filter'
+	- If 'filter' is a godal.FilterOpt: translate it to IFilter (since v0.3.0)
 	- If 'filter' is a map: build a FilterAnd combining all map entries, using operation "=", and return it
 	- Otherwise, return error
 */
 func (dao *GenericDaoSql) BuildFilter(filter interface{}) (IFilter, error) {
+	switch f := filter.(type) {
+	case *godal.FilterOpt:
+		if f == nil {
+			return nil, nil
+		}
+		return dao.buildFilterOpt(f)
+	case godal.FilterOpt:
+		return dao.buildFilterOpt(&f)
+	}
 	v := reflect.ValueOf(filter)
 	if filter == nil || v.IsNil() {
 		return nil, nil
@@ -364,18 +467,113 @@ func (dao *GenericDaoSql) BuildFilter(filter interface{}) (IFilter, error) {
 	return nil, errors.New(fmt.Sprintf("cannot build filter from %v", filter))
 }
 
+func (dao *GenericDaoSql) buildFilterOpt(f *godal.FilterOpt) (IFilter, error) {
+	if f == nil {
+		return nil, errors.New("nil filter")
+	}
+	ops := dao.optionOpLiteral
+	if ops == nil {
+		ops = defaultOptionLiteralOperation
+	}
+	switch f.Operator {
+	case godal.FilterOpEq:
+		return &FilterFieldValue{Field: f.Field, Operation: ops.OpEqual, Value: f.Value}, nil
+	case godal.FilterOpNe:
+		return &FilterFieldValue{Field: f.Field, Operation: ops.OpNotEqual, Value: f.Value}, nil
+	case godal.FilterOpGt:
+		return &FilterFieldValue{Field: f.Field, Operation: ">", Value: f.Value}, nil
+	case godal.FilterOpGte:
+		return &FilterFieldValue{Field: f.Field, Operation: ">=", Value: f.Value}, nil
+	case godal.FilterOpLt:
+		return &FilterFieldValue{Field: f.Field, Operation: "<", Value: f.Value}, nil
+	case godal.FilterOpLte:
+		return &FilterFieldValue{Field: f.Field, Operation: "<=", Value: f.Value}, nil
+	case godal.FilterOpIn:
+		if len(f.Values) == 0 {
+			return nil, errors.New("filter IN requires at least one value")
+		}
+		return &FilterIn{Field: f.Field, Values: f.Values}, nil
+	case godal.FilterOpBetween:
+		if len(f.Values) != 2 {
+			return nil, errors.New("filter BETWEEN requires exactly two values")
+		}
+		return &FilterAnd{Operator: ops.OpAnd, Filters: []IFilter{
+			&FilterFieldValue{Field: f.Field, Operation: ">=", Value: f.Values[0]},
+			&FilterFieldValue{Field: f.Field, Operation: "<=", Value: f.Values[1]},
+		}}, nil
+	case godal.FilterOpPrefix:
+		prefix, err := reddo.ToString(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilterPrefix(f.Field, prefix), nil
+	case godal.FilterOpIsNull:
+		return &FilterExpression{Left: f.Field, Operation: "IS", Right: "NULL"}, nil
+	case godal.FilterOpExists:
+		return &FilterExpression{Left: f.Field, Operation: "IS NOT", Right: "NULL"}, nil
+	case godal.FilterOpAnd, godal.FilterOpOr:
+		if len(f.Filters) == 0 {
+			return nil, errors.New("filter AND/OR requires at least one sub-filter")
+		}
+		filters := make([]IFilter, len(f.Filters))
+		for i, sub := range f.Filters {
+			var err error
+			if filters[i], err = dao.buildFilterOpt(sub); err != nil {
+				return nil, err
+			}
+		}
+		if f.Operator == godal.FilterOpAnd {
+			return &FilterAnd{Operator: ops.OpAnd, Filters: filters}, nil
+		}
+		return &FilterOr{Operator: ops.OpOr, Filters: filters}, nil
+	case godal.FilterOpNot:
+		if len(f.Filters) != 1 {
+			return nil, errors.New("filter NOT requires exactly one sub-filter")
+		}
+		filter, err := dao.buildFilterOpt(f.Filters[0])
+		if err != nil {
+			return nil, err
+		}
+		return &FilterNot{Filter: filter}, nil
+	}
+	return nil, errors.New(fmt.Sprintf("unsupported filter operator %d", f.Operator))
+}
+
+func (dao *GenericDaoSql) buildSortingOpt(o *godal.SortingOpt) ISorting {
+	result := &GenericSorting{Flavor: dao.sqlFlavor}
+	for _, f := range o.Fields {
+		if f.Descending() {
+			result.Add(f.Field + ":DESC")
+		} else {
+			result.Add(f.Field + ":ASC")
+		}
+	}
+	return result
+}
+
 /*
 BuildOrdering builds elements for 'ORDER BY' clause, based on the following rules:
 
 	- If 'ordering' is nil: return nil
 	- If 'ordering' is ISorting: return 'ordering'
-	- If 'ordering' is a map: build a GenericSorting combining all map entries, where map key is field name and map value is ordering specification (1 for ASC, -1 for DESC)
+	- If 'ordering' is a godal.SortingOpt: build a GenericSorting with the same field order (since v0.3.0)
+	- If 'ordering' is a map: build a GenericSorting combining all map entries, where map key is field name and map value is ordering specification (1 for ASC, -1 for DESC).
+	  Map has no stable key order, fields are sorted by name to keep the result deterministic; use godal.SortingOpt or a slice to control the order of fields.
 	- If 'ordering' is a slice/array: build a GenericSorting combining all list entries, assuming each entry is a string in the format '<field_name[<:order>]>' ('order>=0' means 'ascending' and 'order<0' means 'descending')
 	- Otherwise, return error
 
 Available since v0.0.2
 */
 func (dao *GenericDaoSql) BuildOrdering(ordering interface{}) (ISorting, error) {
+	switch o := ordering.(type) {
+	case *godal.SortingOpt:
+		if o == nil {
+			return nil, nil
+		}
+		return dao.buildSortingOpt(o), nil
+	case godal.SortingOpt:
+		return dao.buildSortingOpt(&o), nil
+	}
 	v := reflect.ValueOf(ordering)
 	if ordering == nil || v.IsNil() {
 		return nil, nil
@@ -385,12 +583,30 @@ func (dao *GenericDaoSql) BuildOrdering(ordering interface{}) (ISorting, error)
 	}
 	for ; v.Kind() == reflect.Ptr; v = v.Elem() {
 	}
-	if v.Kind() == reflect.Map {
-		result := &GenericSorting{Flavor: dao.sqlFlavor}
+	switch v.Kind() {
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]string, v.Len())
 		for iter := v.MapRange(); iter.Next(); {
 			key, _ := reddo.ToString(iter.Key().Interface())
 			value, _ := reddo.ToString(iter.Value().Interface())
-			result.Add(key + ":" + value)
+			keys = append(keys, key)
+			values[key] = value
+		}
+		sort.Strings(keys)
+		result := &GenericSorting{Flavor: dao.sqlFlavor}
+		for _, key := range keys {
+			result.Add(key + ":" + values[key])
+		}
+		return result, nil
+	case reflect.Array, reflect.Slice:
+		result := &GenericSorting{Flavor: dao.sqlFlavor}
+		for i, n := 0, v.Len(); i < n; i++ {
+			order, err := reddo.ToString(v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			result.Add(order)
 		}
 		return result, nil
 	}
@@ -402,52 +618,134 @@ func (dao *GenericDaoSql) BuildOrdering(ordering interface{}) (ISorting, error)
 SqlExecute executes a non-SELECT SQL statement within a context/transaction.
 
 	- If ctx is nil, SqlExecute creates a new context to use.
+	- If tx is nil and ctx carries a transaction started by GdaoWithTransaction, that transaction is used.
 	- If tx is not nil, SqlExecute uses transaction context to execute the query.
-	- If tx is nil, SqlExecute calls DB.ExecContext to execute the query.
+	- If tx is nil, SqlExecute calls DB.ExecContext to execute the query; the execution is retried according to the DAO's retry policy
+	  (see godal.AbstractGenericDao.SetRetryPolicy, since v0.3.0).
+	- The statement is prepared before execution and closed afterwards, or reused from the prepared statement cache
+	  (see SetStmtCacheCapacity and SetPrepareStatements, since v0.3.0).
+	- The call is reported to the DAO's instrumentation with the statement and the number of bound values
+	  (see godal.AbstractGenericDao.SetInstrumentation, since v0.3.0).
+	- The statement is logged to the DAO's statement logger, if any (see SetStatementLogger, since v0.3.0).
 */
 func (dao *GenericDaoSql) SqlExecute(ctx context.Context, tx *sql.Tx, sqlStm string, values ...interface{}) (sql.Result, error) {
-	if ctx == nil {
-		ctx, _ = dao.sqlConnect.NewContext()
-	}
-	if tx != nil {
-		if pstm, err := tx.PrepareContext(ctx, sqlStm); err != nil {
+	return dao.sqlExecute(ctx, tx, sqlStm, nil, values)
+}
+
+// sqlExecute implements SqlExecute; columns[i] is the column bound to values[i], used to redact logged values.
+func (dao *GenericDaoSql) sqlExecute(ctx context.Context, tx *sql.Tx, sqlStm string, columns []string, values []interface{}) (result sql.Result, err error) {
+	if tx == nil {
+		if tx, err = dao.txFromContext(ctx); err != nil {
 			return nil, err
-		} else {
-			return pstm.ExecContext(ctx, values...)
 		}
 	}
-	db := dao.sqlConnect.GetDB()
-	if pstm, err := db.PrepareContext(ctx, sqlStm); err != nil {
-		return nil, err
-	} else {
-		return pstm.ExecContext(ctx, values...)
+	ctx, op := dao.startSqlOp(ctx, "SqlExecute", sqlStm, values)
+	defer func() {
+		numRows := int64(0)
+		if result != nil && err == nil {
+			numRows, _ = result.RowsAffected()
+		}
+		op.Finish(int(numRows), err)
+	}()
+	if ctx == nil {
+		ctx, _ = dao.sqlConnect.NewContext()
 	}
+	start := time.Now()
+	err = dao.retry(ctx, tx, func() error {
+		var err error
+		switch {
+		case !dao.noPrepare:
+			err = dao.withStmt(ctx, tx, sqlStm, func(stmt *sql.Stmt) error {
+				result, err = stmt.ExecContext(ctx, values...)
+				return err
+			})
+		case tx != nil:
+			result, err = tx.ExecContext(ctx, sqlStm, values...)
+		default:
+			result, err = dao.sqlConnect.GetDB().ExecContext(ctx, sqlStm, values...)
+		}
+		return dao.TranslateError(err)
+	})
+	dao.logStatement(ctx, "SqlExecute", sqlStm, columns, values, tx != nil, time.Since(start), err)
+	return result, err
 }
 
 /*
 SqlQuery executes a SELECT SQL statement within a context/transaction.
 
 	- If ctx is nil, SqlQuery creates a new context to use.
+	- If tx is nil and ctx carries a transaction started by GdaoWithTransaction, that transaction is used.
 	- If tx is not nil, SqlQuery uses transaction context to execute the query.
-	- If tx is nil, SqlQuery calls DB.QueryContext to execute the query.
+	- If tx is nil, SqlQuery calls DB.QueryContext to execute the query; the execution is retried according to the DAO's retry policy
+	  (see godal.AbstractGenericDao.SetRetryPolicy, since v0.3.0).
+	- The statement is prepared before execution and closed afterwards, or reused from the prepared statement cache
+	  (see SetStmtCacheCapacity and SetPrepareStatements, since v0.3.0).
+	- The call is reported to the DAO's instrumentation with the statement and the number of bound values
+	  (see godal.AbstractGenericDao.SetInstrumentation, since v0.3.0).
+	- The statement is logged to the DAO's statement logger, if any (see SetStatementLogger, since v0.3.0).
 */
 func (dao *GenericDaoSql) SqlQuery(ctx context.Context, tx *sql.Tx, sqlStm string, values ...interface{}) (*sql.Rows, error) {
+	return dao.sqlQuery(ctx, tx, sqlStm, nil, values)
+}
+
+// sqlQuery implements SqlQuery; columns[i] is the column bound to values[i], used to redact logged values.
+func (dao *GenericDaoSql) sqlQuery(ctx context.Context, tx *sql.Tx, sqlStm string, columns []string, values []interface{}) (result *sql.Rows, err error) {
+	if tx == nil {
+		if tx, err = dao.txFromContext(ctx); err != nil {
+			return nil, err
+		}
+	}
+	ctx, op := dao.startSqlOp(ctx, "SqlQuery", sqlStm, values)
+	defer func() { op.Finish(0, err) }()
 	if ctx == nil {
 		ctx, _ = dao.sqlConnect.NewContext()
 	}
-	if tx != nil {
-		if pstm, err := tx.PrepareContext(ctx, sqlStm); err != nil {
-			return nil, err
-		} else {
-			return pstm.QueryContext(ctx, values...)
+	start := time.Now()
+	err = dao.retry(ctx, tx, func() error {
+		var err error
+		switch {
+		case !dao.noPrepare:
+			err = dao.withStmt(ctx, tx, sqlStm, func(stmt *sql.Stmt) error {
+				result, err = stmt.QueryContext(ctx, values...)
+				return err
+			})
+		case tx != nil:
+			result, err = tx.QueryContext(ctx, sqlStm, values...)
+		default:
+			result, err = dao.sqlConnect.GetDB().QueryContext(ctx, sqlStm, values...)
 		}
+		return dao.TranslateError(err)
+	})
+	dao.logStatement(ctx, "SqlQuery", sqlStm, columns, values, tx != nil, time.Since(start), err)
+	return result, err
+}
+
+/*
+IsRetryableError is the classifier of retryable errors used with the DAO's retry policy (see godal.AbstractGenericDao.SetRetryPolicy):
+deadlocks, serialization failures and throttling errors (see TranslateError and godal.IsRetryableError).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) IsRetryableError(err error) bool {
+	return godal.IsRetryableError(dao.TranslateError(err))
+}
+
+// startOp reports the start of a Gdao* call to the DAO's instrumentation, see godal.AbstractGenericDao.StartOp.
+func (dao *GenericDaoSql) startOp(ctx context.Context, operation, storageId string) (context.Context, *godal.DaoOperation) {
+	return dao.StartOp(ctx, &godal.DaoOperation{Backend: "sql", Operation: operation, StorageId: storageId})
+}
+
+// startSqlOp reports the start of a SqlExecute/SqlQuery call, with the final SQL statement and the number of bound values.
+func (dao *GenericDaoSql) startSqlOp(ctx context.Context, operation, sqlStm string, values []interface{}) (context.Context, *godal.DaoOperation) {
+	return dao.StartOp(ctx, &godal.DaoOperation{Backend: "sql", Operation: operation, Statement: sqlStm, NumBindings: len(values)})
+}
+
+// retry calls fn under the DAO's retry policy, unless tx is not nil: calls made inside a transaction are not retried individually.
+func (dao *GenericDaoSql) retry(ctx context.Context, tx *sql.Tx, fn func() error) error {
+	if tx != nil {
+		return fn()
 	}
-	db := dao.sqlConnect.GetDB()
-	if pstm, err := db.PrepareContext(ctx, sqlStm); err != nil {
-		return nil, err
-	} else {
-		return pstm.QueryContext(ctx, values...)
-	}
+	return dao.WithRetry(ctx, dao.IsRetryableError, fn)
 }
 
 /*
@@ -455,11 +753,10 @@ SqlDelete constructs a DELETE statement and executes it within a context/transac
 */
 func (dao *GenericDaoSql) SqlDelete(ctx context.Context, tx *sql.Tx, table string, filter IFilter) (sql.Result, error) {
 	builder := NewDeleteBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithFilter(filter)
-	if dao.funcNewPlaceholderGenerator != nil {
-		builder.WithPlaceholderGenerator(dao.funcNewPlaceholderGenerator())
-	}
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
 	sqlStm, values := builder.Build()
-	return dao.SqlExecute(ctx, tx, sqlStm, values...)
+	return dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values)
 }
 
 /*
@@ -467,11 +764,10 @@ SqlInsert constructs a INSERT statement and executes it within a context/transac
 */
 func (dao *GenericDaoSql) SqlInsert(ctx context.Context, tx *sql.Tx, table string, colsAndVals map[string]interface{}) (sql.Result, error) {
 	builder := NewInsertBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithValues(colsAndVals)
-	if dao.funcNewPlaceholderGenerator != nil {
-		builder.WithPlaceholderGenerator(dao.funcNewPlaceholderGenerator())
-	}
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
 	sqlStm, values := builder.Build()
-	return dao.SqlExecute(ctx, tx, sqlStm, values...)
+	return dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values)
 }
 
 /*
@@ -483,11 +779,10 @@ func (dao *GenericDaoSql) SqlSelect(ctx context.Context, tx *sql.Tx, table strin
 		WithFilter(filter).
 		WithSorting(sorting).
 		WithLimit(numItems, fromOffset)
-	if dao.funcNewPlaceholderGenerator != nil {
-		builder.WithPlaceholderGenerator(dao.funcNewPlaceholderGenerator())
-	}
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
 	query, values := builder.Build()
-	return dao.SqlQuery(ctx, tx, query, values...)
+	return dao.sqlQuery(ctx, tx, query, bindColumns, values)
 }
 
 /*
@@ -495,11 +790,23 @@ SqlUpdate constructs an UPDATE query and executes it within a context/transactio
 */
 func (dao *GenericDaoSql) SqlUpdate(ctx context.Context, tx *sql.Tx, table string, colsAndVals map[string]interface{}, filter IFilter) (sql.Result, error) {
 	builder := NewUpdateBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithValues(colsAndVals).WithFilter(filter)
-	if dao.funcNewPlaceholderGenerator != nil {
-		builder.WithPlaceholderGenerator(dao.funcNewPlaceholderGenerator())
-	}
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
 	query, values := builder.Build()
-	return dao.SqlExecute(ctx, tx, query, values...)
+	return dao.sqlExecute(ctx, tx, query, bindColumns, values)
+}
+
+/*
+SqlUpsert constructs an "insert-or-update" statement (see UpsertBuilder) and executes it within a context/transaction.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SqlUpsert(ctx context.Context, tx *sql.Tx, table string, colsAndVals map[string]interface{}, keyColumns []string) (sql.Result, error) {
+	builder := NewUpsertBuilder().WithFlavor(dao.sqlFlavor).WithTable(table).WithValues(colsAndVals).WithKeyColumns(keyColumns...)
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	sqlStm, values := builder.Build()
+	return dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values)
 }
 
 /*
@@ -509,20 +816,25 @@ FetchOne fetches a row from `sql.Rows` and transforms it to godal.IGenericBo.
 	- Caller should not call dbRows.Next(), FetchOne will do that.
 */
 func (dao *GenericDaoSql) FetchOne(storageId string, dbRows *sql.Rows) (godal.IGenericBo, error) {
+	return dao.fetchOne(nil, storageId, dbRows)
+}
+
+// fetchOne is the context-aware implementation of FetchOne, ctx is passed to godal.HookAfterFetch hooks.
+func (dao *GenericDaoSql) fetchOne(ctx context.Context, storageId string, dbRows *sql.Rows) (godal.IGenericBo, error) {
 	var bo godal.IGenericBo
 	var err error
 	e := dao.sqlConnect.FetchRowsCallback(dbRows, func(row map[string]interface{}, e error) bool {
 		if e == nil {
-			bo, err = dao.GetRowMapper().ToBo(storageId, row)
+			bo, err = dao.RowToBo(ctx, storageId, row)
 		} else {
 			err = e
 		}
 		return false
 	})
 	if err != nil {
-		return bo, err
+		return bo, dao.TranslateError(err)
 	}
-	return bo, e
+	return bo, dao.TranslateError(e)
 }
 
 /*
@@ -532,6 +844,11 @@ FetchAll fetches all rows from `sql.Rows` and transforms to []godal.IGenericBo
 	- Caller should not call dbRows.Next(), FetchOne will do that.
 */
 func (dao *GenericDaoSql) FetchAll(storageId string, dbRows *sql.Rows) ([]godal.IGenericBo, error) {
+	return dao.fetchAll(nil, storageId, dbRows)
+}
+
+// fetchAll is the context-aware implementation of FetchAll, ctx is passed to godal.HookAfterFetch hooks.
+func (dao *GenericDaoSql) fetchAll(ctx context.Context, storageId string, dbRows *sql.Rows) ([]godal.IGenericBo, error) {
 	boList := make([]godal.IGenericBo, 0)
 	var err error
 	e := dao.sqlConnect.FetchRowsCallback(dbRows, func(row map[string]interface{}, e error) bool {
@@ -539,7 +856,7 @@ func (dao *GenericDaoSql) FetchAll(storageId string, dbRows *sql.Rows) ([]godal.
 			err = e
 			return false
 		}
-		if bo, e := dao.GetRowMapper().ToBo(storageId, row); e != nil {
+		if bo, e := dao.RowToBo(ctx, storageId, row); e != nil {
 			err = e
 			return false
 		} else {
@@ -548,9 +865,9 @@ func (dao *GenericDaoSql) FetchAll(storageId string, dbRows *sql.Rows) ([]godal.
 		return true
 	})
 	if err != nil {
-		return boList, err
+		return boList, dao.TranslateError(err)
 	}
-	return boList, e
+	return boList, dao.TranslateError(e)
 }
 
 /*----------------------------------------------------------------------*/
@@ -568,9 +885,15 @@ GdaoDeleteWithTx is extended-implementation of godal.IGenericDao.GdaoDelete.
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoSql) GdaoDeleteWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
+func (dao *GenericDaoSql) GdaoDeleteWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDelete", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeDelete, storageId, bo); err != nil {
+		return 0, err
+	}
 	filter := dao.GdaoCreateFilter(storageId, bo)
-	return dao.GdaoDeleteManyWithTx(ctx, tx, storageId, filter)
+	numRows, err = dao.GdaoDeleteManyWithTx(ctx, tx, storageId, filter)
+	return dao.AfterWrite(ctx, godal.HookAfterDelete, storageId, bo, numRows, err)
 }
 
 /*
@@ -585,7 +908,9 @@ GdaoDeleteManyWithTx is extended-implementation of godal.IGenericDao.GdaoDeleteM
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoSql) GdaoDeleteManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (int, error) {
+func (dao *GenericDaoSql) GdaoDeleteManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoDeleteMany", storageId)
+	defer func() { op.Finish(numRows, err) }()
 	if f, err := dao.BuildFilter(filter); err != nil {
 		return 0, err
 	} else if result, err := dao.SqlDelete(ctx, tx, storageId, f); err != nil {
@@ -608,7 +933,15 @@ GdaoFetchOneWithTx is extended-implementation of godal.IGenericDao.GdaoFetchOne.
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoSql) GdaoFetchOneWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (godal.IGenericBo, error) {
+func (dao *GenericDaoSql) GdaoFetchOneWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (bo godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchOne", storageId)
+	defer func() {
+		if bo != nil {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
 	if f, err := dao.BuildFilter(filter); err != nil {
 		return nil, err
 	} else {
@@ -619,7 +952,7 @@ func (dao *GenericDaoSql) GdaoFetchOneWithTx(ctx context.Context, tx *sql.Tx, st
 		if err != nil {
 			return nil, err
 		}
-		return dao.FetchOne(storageId, dbRows)
+		return dao.fetchOne(ctx, storageId, dbRows)
 	}
 }
 
@@ -635,7 +968,9 @@ GdaoFetchManyWithTx is extended-implementation of godal.IGenericDao.GdaoFetchMan
 
 Available: since v0.1.0
 */
-func (dao *GenericDaoSql) GdaoFetchManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}, ordering interface{}, fromOffset, numRows int) ([]godal.IGenericBo, error) {
+func (dao *GenericDaoSql) GdaoFetchManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}, ordering interface{}, fromOffset, numRows int) (boList []godal.IGenericBo, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchMany", storageId)
+	defer func() { op.Finish(len(boList), err) }()
 	if f, err := dao.BuildFilter(filter); err != nil {
 		return nil, err
 	} else {
@@ -647,168 +982,1126 @@ func (dao *GenericDaoSql) GdaoFetchManyWithTx(ctx context.Context, tx *sql.Tx, s
 		if err != nil {
 			return nil, err
 		}
-		return dao.FetchAll(storageId, dbRows)
+		return dao.fetchAll(ctx, storageId, dbRows)
 	}
 }
 
-func (dao *GenericDaoSql) isErrorDuplicatedEntry(err error) bool {
-	if err == nil {
-		return false
-	}
-	switch dao.sqlFlavor {
-	case prom.FlavorMySql:
-		return regexp.MustCompile(`\W1062\W`).FindString(err.Error()) != ""
-	case prom.FlavorPgSql:
-		return regexp.MustCompile(`\W23505\W`).FindString(fmt.Sprintf("%e", err)) != ""
-	case prom.FlavorMsSql:
-		return regexp.MustCompile(`\W2627\W|\W2601\W`).FindString(fmt.Sprintf("%e", err)) != ""
-	case prom.FlavorOracle:
-		return regexp.MustCompile(`\WORA\-00001\W`).FindString(fmt.Sprintf("%v", err)) != ""
-	}
-	return false
-}
-
 /*
-GdaoCreate implements godal.IGenericDao.GdaoCreate.
+GdaoFetchIterator implements godal.IGenericDao.GdaoFetchIterator.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoCreateWithTx(nil, nil, storageId, bo)
+func (dao *GenericDaoSql) GdaoFetchIterator(storageId string, filter interface{}, ordering interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithTx(nil, nil, storageId, filter, ordering)
 }
 
 /*
-GdaoCreateWithTx is extended-implementation of godal.IGenericDao.GdaoCreate.
+GdaoFetchIteratorWithTx is extended-implementation of godal.IGenericDao.GdaoFetchIterator.
 
-Available: since v0.1.0
+	- the returned iterator is backed by the *sql.Rows of the query: rows are fetched from database and transformed to BOs lazily.
+	- if ctx is nil, a context without deadline is used (the default timeout is not applied as the iteration can take long).
+	- the underlying connection (or transaction) is busy until the iterator is closed.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoCreateWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
-	// insert new document
-	if row, err := dao.GetRowMapper().ToRow(storageId, bo); err != nil {
-		return 0, err
-	} else if colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{})); err != nil {
-		return 0, err
-	} else if result, err := dao.SqlInsert(ctx, tx, storageId, colsAndVals.(map[string]interface{})); err != nil {
-		if dao.isErrorDuplicatedEntry(err) {
-			return 0, godal.GdaoErrorDuplicatedEntry
-		} else {
-			return 0, err
+func (dao *GenericDaoSql) GdaoFetchIteratorWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}, ordering interface{}) (it godal.IGenericBoIterator, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchIterator", storageId)
+	defer func() { op.Finish(0, err) }()
+	f, err := dao.BuildFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	o, err := dao.BuildOrdering(ordering)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	dbRows, err := dao.SqlSelect(ctx, tx, storageId, dao.GetRowMapper().ColumnsList(storageId), f, o, 0, 0)
+	if err != nil {
+		if dbRows != nil {
+			_ = dbRows.Close()
 		}
-	} else {
-		numRows, err := result.RowsAffected()
-		return int(numRows), err
+		return nil, err
+	}
+	return &sqlBoIterator{dao: dao, ctx: ctx, storageId: storageId, dbRows: dbRows}, nil
+}
+
+// sqlBoIterator implements godal.IGenericBoIterator.
+type sqlBoIterator struct {
+	dao       *GenericDaoSql
+	ctx       context.Context
+	storageId string
+	dbRows    *sql.Rows
+	closed    bool
+	bo        godal.IGenericBo
+	err       error
+}
+
+// Next implements godal.IGenericBoIterator.Next.
+func (it *sqlBoIterator) Next() bool {
+	if it.err != nil || it.closed {
+		it.bo = nil
+		return false
 	}
+	it.bo, it.err = it.dao.fetchOne(it.ctx, it.storageId, it.dbRows)
+	return it.bo != nil && it.err == nil
+}
+
+// Bo implements godal.IGenericBoIterator.Bo.
+func (it *sqlBoIterator) Bo() godal.IGenericBo {
+	return it.bo
+}
+
+// Err implements godal.IGenericBoIterator.Err.
+func (it *sqlBoIterator) Err() error {
+	return it.err
+}
+
+// Close implements godal.IGenericBoIterator.Close.
+func (it *sqlBoIterator) Close() error {
+	it.closed = true
+	return it.dbRows.Close()
 }
 
 /*
-GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+GdaoFetchPage implements godal.IGenericDao.GdaoFetchPage.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error) {
-	return dao.GdaoUpdateWithTx(nil, nil, storageId, bo)
+func (dao *GenericDaoSql) GdaoFetchPage(storageId string, filter interface{}, ordering interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithTx(nil, nil, storageId, filter, ordering, pageToken, numItems)
 }
 
 /*
-GdaoUpdateWithTx is extended-implementation of godal.IGenericDao.GdaoUpdate.
+GdaoFetchPageWithTx is extended-implementation of godal.IGenericDao.GdaoFetchPage.
 
-Available: since v0.1.0
+	- keyset pagination is used (see godal.KeysetPaging): ordering must be a godal.SortingOpt whose columns combined are unique
+	  (e.g. end the ordering with the primary key), and the page token carries the values of these columns of the last row of the previous page.
+	- the next page is fetched with "WHERE <filter> AND <row comes after the last row of the previous page>", which can use an index on the ordering columns.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoUpdateWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
-	filter, err := dao.BuildFilter(dao.GdaoCreateFilter(storageId, bo))
+func (dao *GenericDaoSql) GdaoFetchPageWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}, ordering interface{}, pageToken string, numItems int) (boList []godal.IGenericBo, nextPageToken string, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoFetchPage", storageId)
+	defer func() { op.Finish(len(boList), err) }()
+	paging, err := godal.NewKeysetPaging(ordering, pageToken)
 	if err != nil {
-		return 0, err
+		return nil, "", err
 	}
-	row, err := dao.GetRowMapper().ToRow(storageId, bo)
+	f, err := dao.BuildFilter(filter)
 	if err != nil {
-		return 0, err
+		return nil, "", err
 	}
-	colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
+	if kf := paging.Filter(); kf != nil {
+		keysetFilter, err := dao.buildFilterOpt(kf)
+		if err != nil {
+			return nil, "", err
+		}
+		ops := dao.optionOpLiteral
+		if ops == nil {
+			ops = defaultOptionLiteralOperation
+		}
+		f = (&FilterAnd{Operator: ops.OpAnd}).Add(f).Add(keysetFilter)
+	}
+	limit := numItems
+	if numItems > 0 {
+		// fetch one more row to know if there is a next page
+		limit++
+	}
+	boList, err = dao.GdaoFetchManyWithTx(ctx, tx, storageId, f, paging.Sorting, 0, limit)
+	if err != nil || numItems <= 0 || len(boList) <= numItems {
+		return boList, "", err
+	}
+	boList = boList[:numItems]
+	row, err := dao.GetRowMapper().ToRow(storageId, boList[numItems-1])
 	if err != nil {
-		return 0, err
+		return nil, "", err
 	}
-	if result, err := dao.SqlUpdate(ctx, tx, storageId, colsAndVals.(map[string]interface{}), filter); err != nil {
-		if dao.isErrorDuplicatedEntry(err) {
-			return 0, godal.GdaoErrorDuplicatedEntry
-		}
-		return 0, err
-	} else {
-		numRows, err := result.RowsAffected()
-		return int(numRows), err
+	rowMap, ok := row.(map[string]interface{})
+	if !ok {
+		return nil, "", errors.New(fmt.Sprintf("cannot build page token from row %v", row))
 	}
+	nextPageToken, err = paging.NextPageToken(rowMap)
+	if err != nil {
+		return nil, "", err
+	}
+	return boList, nextPageToken, nil
 }
 
 /*
-GdaoSave implements godal.IGenericDao.GdaoSave.
+GdaoCount implements godal.IGenericDao.GdaoCount.
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoSave(storageId string, bo godal.IGenericBo) (int, error) {
-	var numRows int
-	var err error
-	if dao.txModeOnWrite {
-		err = dao.WrapTransaction(nil, func(ctx context.Context, tx *sql.Tx) error {
-			var e error
-			numRows, e = dao.GdaoSaveWithTx(ctx, tx, storageId, bo)
-			return e
-		})
-	} else {
-		numRows, err = dao.GdaoSaveWithTx(nil, nil, storageId, bo)
-	}
-	return numRows, err
+func (dao *GenericDaoSql) GdaoCount(storageId string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithTx(nil, nil, storageId, filter)
 }
 
 /*
-GdaoSaveWithTx is extended-implementation of godal.IGenericDao.GdaoSave.
+GdaoCountWithTx is extended-implementation of godal.IGenericDao.GdaoCount.
 
-Available: since v0.1.0
+This function executes "SELECT COUNT(*) FROM <table> WHERE <filter>".
+
+Available: since v0.3.0
 */
-func (dao *GenericDaoSql) GdaoSaveWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
-	filter, err := dao.BuildFilter(dao.GdaoCreateFilter(storageId, bo))
+func (dao *GenericDaoSql) GdaoCountWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (count int64, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCount", storageId)
+	defer func() { op.Finish(int(count), err) }()
+	f, err := dao.BuildFilter(filter)
 	if err != nil {
 		return 0, err
 	}
-	row, err := dao.GetRowMapper().ToRow(storageId, bo)
-	if err != nil {
-		return 0, err
+	dbRows, err := dao.SqlSelect(ctx, tx, storageId, []string{"COUNT(*)"}, f, nil, 0, 0)
+	if dbRows != nil {
+		defer func() { _ = dbRows.Close() }()
 	}
-	colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
 	if err != nil {
 		return 0, err
 	}
-
-	// firstly: try to update row
-	if result, err := dao.SqlUpdate(ctx, tx, storageId, colsAndVals.(map[string]interface{}), filter); err != nil {
-		if dao.isErrorDuplicatedEntry(err) {
-			return 0, godal.GdaoErrorDuplicatedEntry
-		}
+	if dbRows.Next() {
+		err = dbRows.Scan(&count)
+	}
+	if err != nil {
 		return 0, err
-	} else if numRows, err := result.RowsAffected(); err != nil || numRows > 0 {
-		return int(numRows), err
-	} else {
-		// secondly: no row updated, try insert row
-		if result, err := dao.SqlInsert(ctx, tx, storageId, colsAndVals.(map[string]interface{})); err != nil {
-			if dao.isErrorDuplicatedEntry(err) {
-				return 0, godal.GdaoErrorDuplicatedEntry
-			}
-			return 0, err
-		} else {
-			numRows, err := result.RowsAffected()
-			return int(numRows), err
-		}
 	}
+	return count, dao.TranslateError(dbRows.Err())
+}
+
+/*
+GdaoExists implements godal.IGenericDao.GdaoExists.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoExists(storageId string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithTx(nil, nil, storageId, filter)
+}
+
+/*
+GdaoExistsWithTx is extended-implementation of godal.IGenericDao.GdaoExists.
+
+This function executes "SELECT 1 FROM <table> WHERE <filter>" limited to 1 row ("SELECT TOP 1 1 FROM <table> WHERE <filter>" for MSSQL).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoExistsWithTx(ctx context.Context, tx *sql.Tx, storageId string, filter interface{}) (exists bool, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoExists", storageId)
+	defer func() {
+		if exists {
+			op.Finish(1, err)
+		} else {
+			op.Finish(0, err)
+		}
+	}()
+	f, err := dao.BuildFilter(filter)
+	if err != nil {
+		return false, err
+	}
+	columns := []string{"1"}
+	if dao.sqlFlavor == prom.FlavorMsSql {
+		// MSSQL applies limits only to sorted queries, see SelectBuilder
+		columns = []string{"TOP 1 1"}
+	}
+	dbRows, err := dao.SqlSelect(ctx, tx, storageId, columns, f, nil, 0, 1)
+	if dbRows != nil {
+		defer func() { _ = dbRows.Close() }()
+	}
+	if err != nil {
+		return false, err
+	}
+	if dbRows.Next() {
+		return true, nil
+	}
+	return false, dao.TranslateError(dbRows.Err())
+}
+
+func (dao *GenericDaoSql) isErrorDuplicatedEntry(err error) bool {
+	return errors.Is(dao.TranslateError(err), godal.ErrConflict)
+}
+
+// sqlErrorKinds maps native error codes to godal error kinds, per database flavor.
+var sqlErrorKinds = map[prom.DbFlavor]map[string]error{
+	prom.FlavorMySql: {
+		"1062": godal.ErrConflict, "1586": godal.ErrConflict,
+		"1451": godal.ErrConstraintViolation, "1452": godal.ErrConstraintViolation, "1048": godal.ErrConstraintViolation,
+		"1364": godal.ErrConstraintViolation, "3819": godal.ErrConstraintViolation,
+		"1213": godal.ErrDeadlock,
+		"1205": godal.ErrTimeout, "3024": godal.ErrTimeout,
+		"1040": godal.ErrThrottled, "1203": godal.ErrThrottled,
+		"1146": godal.ErrNotFound,
+	},
+	prom.FlavorPgSql: {
+		"23505": godal.ErrConflict,
+		"23503": godal.ErrConstraintViolation, "23502": godal.ErrConstraintViolation, "23514": godal.ErrConstraintViolation,
+		"23P01": godal.ErrConstraintViolation,
+		"40P01": godal.ErrDeadlock,
+		"40001": godal.ErrConcurrentModification,
+		"57014": godal.ErrTimeout, "55P03": godal.ErrTimeout,
+		"53300": godal.ErrThrottled,
+		"42P01": godal.ErrNotFound,
+	},
+	prom.FlavorMsSql: {
+		"2627": godal.ErrConflict, "2601": godal.ErrConflict,
+		"547": godal.ErrConstraintViolation, "515": godal.ErrConstraintViolation,
+		"1205": godal.ErrDeadlock,
+		"3960": godal.ErrConcurrentModification,
+		"1222": godal.ErrTimeout,
+		"208": godal.ErrNotFound,
+	},
+	prom.FlavorOracle: {
+		"00001": godal.ErrConflict,
+		"02291": godal.ErrConstraintViolation, "02292": godal.ErrConstraintViolation, "01400": godal.ErrConstraintViolation,
+		"02290": godal.ErrConstraintViolation,
+		"00060": godal.ErrDeadlock,
+		"08177": godal.ErrConcurrentModification,
+		"01013": godal.ErrTimeout, "00054": godal.ErrTimeout, "30006": godal.ErrTimeout,
+		"00018": godal.ErrThrottled, "00020": godal.ErrThrottled,
+		"00942": godal.ErrNotFound,
+	},
+}
+
+var (
+	reMysqlErrorCode  = regexp.MustCompile(`^Error (\d+)`)
+	reOracleErrorCode = regexp.MustCompile(`ORA-(\d{5})`)
+)
+
+// sqlErrorCode extracts the native error code from a driver error, "" if not found.
+func sqlErrorCode(flavor prom.DbFlavor, err error) string {
+	switch flavor {
+	case prom.FlavorMySql:
+		// *mysql.MySQLError: "Error <number>: <message>"
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			if m := reMysqlErrorCode.FindStringSubmatch(e.Error()); m != nil {
+				return m[1]
+			}
+		}
+	case prom.FlavorPgSql:
+		// *pq.Error: SQLSTATE code is field 'C'
+		var e interface{ Get(k byte) string }
+		if errors.As(err, &e) {
+			return e.Get('C')
+		}
+	case prom.FlavorMsSql:
+		// mssql.Error
+		var e interface{ SQLErrorNumber() int32 }
+		if errors.As(err, &e) {
+			return strconv.Itoa(int(e.SQLErrorNumber()))
+		}
+	case prom.FlavorOracle:
+		// *goracle.OraErr
+		var e interface{ Code() int }
+		if errors.As(err, &e) {
+			return fmt.Sprintf("%05d", e.Code())
+		}
+		if m := reOracleErrorCode.FindStringSubmatch(err.Error()); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+/*
+TranslateError maps a native driver error to a godal error kind (see godal.ErrConflict, godal.ErrDeadlock, etc) according to the
+database flavor, and wraps it into a *godal.DaoError. Errors of unknown kinds are returned as-is. Errors returned by GenericDaoSql's
+functions have already been translated.
+
+	- MySQL: duplicated key (1062, 1586), FK/not-null/check violation (1451, 1452, 1048, 1364, 3819), deadlock (1213),
+	  lock wait/statement timeout (1205, 3024), too many connections (1040, 1203), table not found (1146).
+	- PostgreSQL: unique violation (23505), FK/not-null/check/exclusion violation (23503, 23502, 23514, 23P01), deadlock (40P01),
+	  serialization failure (40001), statement/lock timeout (57014, 55P03), too many connections (53300), table not found (42P01).
+	- MSSQL: duplicated key (2627, 2601), FK/check/not-null violation (547, 515), deadlock (1205), snapshot update conflict (3960),
+	  lock timeout (1222), invalid object (208).
+	- Oracle: unique violation (ORA-00001), FK/not-null/check violation (ORA-02291, 02292, 01400, 02290), deadlock (ORA-00060),
+	  serialization failure (ORA-08177), cancelled/timeout (ORA-01013, 00054, 30006), too many sessions/processes (ORA-00018, 00020),
+	  table not found (ORA-00942).
+	- context.DeadlineExceeded (all flavors) is mapped to godal.ErrTimeout.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) TranslateError(err error) error {
+	var daoErr *godal.DaoError
+	if err == nil || errors.As(err, &daoErr) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return godal.WrapError(godal.ErrTimeout, err)
+	}
+	if kinds := sqlErrorKinds[dao.sqlFlavor]; kinds != nil {
+		return godal.WrapError(kinds[sqlErrorCode(dao.sqlFlavor, err)], err)
+	}
+	return err
+}
+
+/*
+GdaoCreate implements godal.IGenericDao.GdaoCreate.
+*/
+func (dao *GenericDaoSql) GdaoCreate(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithTx(nil, nil, storageId, bo)
+}
+
+/*
+GdaoCreateWithTx is extended-implementation of godal.IGenericDao.GdaoCreate.
+
+If returning columns are set for the table (see SetReturningColumns), bo is populated with the values read back (since v0.3.0).
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoSql) GdaoCreateWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreate", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.createWithTx(ctx, tx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterCreate, storageId, bo, numRows, err)
+}
+
+// createWithTx inserts a new row, see GdaoCreateWithTx.
+func (dao *GenericDaoSql) createWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
+	// insert new document
+	if row, err := dao.GetRowMapper().ToRow(storageId, bo); err != nil {
+		return 0, err
+	} else if colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{})); err != nil {
+		return 0, err
+	} else if returning := dao.GetReturningColumns(storageId); len(returning) > 0 {
+		numRows, returned, err := dao.insertReturning(ctx, tx, storageId, colsAndVals.(map[string]interface{}), returning)
+		if err != nil {
+			if dao.isErrorDuplicatedEntry(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		}
+		return int(numRows), dao.populateReturned(storageId, bo, colsAndVals.(map[string]interface{}), returned)
+	} else if result, err := dao.SqlInsert(ctx, tx, storageId, colsAndVals.(map[string]interface{})); err != nil {
+		if dao.isErrorDuplicatedEntry(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		} else {
+			return 0, err
+		}
+	} else {
+		numRows, err := result.RowsAffected()
+		return int(numRows), err
+	}
+}
+
+/*
+GdaoCreateMany implements godal.IGenericDao.GdaoCreateMany.
+*/
+func (dao *GenericDaoSql) GdaoCreateMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithTx(nil, nil, storageId, boList)
+}
+
+const (
+	// limits of a multi-row INSERT statement, kept below the lowest limits among supported databases (MSSQL: 1000 rows, 2100 parameters)
+	bulkInsertMaxRows   = 1000
+	bulkInsertMaxParams = 2000
+)
+
+/*
+GdaoCreateManyWithTx is extended-implementation of godal.IGenericDao.GdaoCreateMany.
+
+	- consecutive BOs having the same set of columns are inserted using one multi-row INSERT statement (see InsertBuilder.AddRow).
+	- if a multi-row INSERT fails (e.g. one of the rows is a duplicate), rows of that statement are re-inserted one by one to find out
+	  per-row results. PostgreSQL aborts the whole transaction on error, hence when running inside a transaction all rows of the
+	  failed statement are reported with the error.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoCreateManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoCreateMany", storageId)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	rows := make([]map[string]interface{}, len(boList))
+	for i, bo := range boList {
+		if err := dao.BeforeWrite(ctx, godal.HookBeforeCreate, storageId, bo); err != nil {
+			results[i].Error = err
+		} else if row, err := dao.GetRowMapper().ToRow(storageId, bo); err != nil {
+			results[i].Error = err
+		} else if colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{})); err != nil {
+			results[i].Error = err
+		} else {
+			rows[i] = colsAndVals.(map[string]interface{})
+		}
+	}
+	ctxTx, ctxErr := dao.txFromContext(ctx)
+	inTx := tx != nil || ctxTx != nil || ctxErr != nil
+	for start := 0; start < len(rows); {
+		if rows[start] == nil {
+			start++
+			continue
+		}
+		cols := columnsKey(rows[start])
+		end := start + 1
+		for end < len(rows) && rows[end] != nil && end-start < bulkInsertMaxRows &&
+			(end-start+1)*len(rows[start]) <= bulkInsertMaxParams && columnsKey(rows[end]) == cols {
+			end++
+		}
+		dao.bulkInsert(ctx, tx, inTx, storageId, rows[start:end], results[start:end])
+		start = end
+	}
+	for i, bo := range boList {
+		if rows[i] != nil {
+			results[i].NumRows, results[i].Error = dao.AfterWrite(ctx, godal.HookAfterCreate, storageId, bo, results[i].NumRows, results[i].Error)
+		}
+	}
+	return results, bulkError(results)
+}
+
+// columnsKey returns the sorted, comma-separated list of columns of a row.
+func columnsKey(row map[string]interface{}) string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	return strings.Join(cols, ",")
+}
+
+// bulkInsert inserts rows having the same set of columns and stores per-row results into 'results'.
+func (dao *GenericDaoSql) bulkInsert(ctx context.Context, tx *sql.Tx, inTx bool, table string, rows []map[string]interface{}, results []godal.GdaoBulkResult) {
+	var err error
+	if len(rows) > 1 {
+		builder := NewInsertBuilder().WithFlavor(dao.sqlFlavor).WithTable(table)
+		var bindColumns []string
+		builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+		for _, row := range rows {
+			builder.AddRow(row)
+		}
+		sqlStm, values := builder.Build()
+		if _, err = dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values); err == nil {
+			for i := range results {
+				results[i].NumRows = 1
+			}
+			return
+		}
+		if dao.isErrorDuplicatedEntry(err) {
+			err = godal.GdaoErrorDuplicatedEntry
+		}
+		if inTx && dao.sqlFlavor == prom.FlavorPgSql {
+			for i := range results {
+				results[i].Error = err
+			}
+			return
+		}
+	}
+	// single row, or multi-row INSERT failed: insert rows one by one
+	for i, row := range rows {
+		if result, err := dao.SqlInsert(ctx, tx, table, row); err != nil {
+			if dao.isErrorDuplicatedEntry(err) {
+				err = godal.GdaoErrorDuplicatedEntry
+			}
+			results[i].Error = err
+		} else {
+			numRows, err := result.RowsAffected()
+			results[i].NumRows, results[i].Error = int(numRows), err
+		}
+	}
+}
+
+// bulkError returns the first error of the bulk results.
+func bulkError(results []godal.GdaoBulkResult) error {
+	for _, r := range results {
+		if r.Error != nil {
+			return r.Error
+		}
+	}
+	return nil
+}
+
+/*
+GdaoUpdate implements godal.IGenericDao.GdaoUpdate.
+*/
+func (dao *GenericDaoSql) GdaoUpdate(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithTx(nil, nil, storageId, bo)
+}
+
+/*
+GdaoUpdateWithTx is extended-implementation of godal.IGenericDao.GdaoUpdate.
+
+If returning columns are set for the table (see SetReturningColumns), bo is populated with the values read back (since v0.3.0).
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoSql) GdaoUpdateWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdate", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.updateWithTx(ctx, tx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, storageId, bo, numRows, err)
+}
+
+// updateWithTx updates an existing row, see GdaoUpdateWithTx.
+func (dao *GenericDaoSql) updateWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
+	row, err := dao.GetRowMapper().ToRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return 0, err
+	}
+	filter, versioned, err := dao.versionedFilter(storageId, bo, colsAndVals.(map[string]interface{}))
+	if err != nil {
+		return 0, err
+	}
+	if returning := dao.GetReturningColumns(storageId); len(returning) > 0 {
+		numRows, returned, err := dao.updateReturning(ctx, tx, storageId, colsAndVals.(map[string]interface{}), filter, returning)
+		if err != nil {
+			if dao.isErrorDuplicatedEntry(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		} else if numRows == 0 && versioned {
+			return 0, dao.versionMismatchError(ctx, tx, storageId, bo)
+		} else if err := dao.setWrittenVersion(storageId, bo, colsAndVals.(map[string]interface{}), versioned && numRows > 0); err != nil {
+			return int(numRows), err
+		}
+		return int(numRows), dao.populateReturned(storageId, bo, colsAndVals.(map[string]interface{}), returned)
+	}
+	if result, err := dao.SqlUpdate(ctx, tx, storageId, colsAndVals.(map[string]interface{}), filter); err != nil {
+		if dao.isErrorDuplicatedEntry(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	} else if numRows, err := result.RowsAffected(); err != nil || numRows > 0 || !versioned {
+		if err == nil {
+			err = dao.setWrittenVersion(storageId, bo, colsAndVals.(map[string]interface{}), versioned)
+		}
+		return int(numRows), err
+	} else {
+		return 0, dao.versionMismatchError(ctx, tx, storageId, bo)
+	}
+}
+
+/*
+versionedFilter builds the filter matching the stored row of bo. If optimistic locking is enabled on the storage
+(see godal.AbstractGenericDao.SetVersionField), the filter also matches the BO's version and the version in colsAndVals is incremented.
+*/
+func (dao *GenericDaoSql) versionedFilter(storageId string, bo godal.IGenericBo, colsAndVals map[string]interface{}) (IFilter, bool, error) {
+	filter, err := dao.BuildFilter(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return nil, false, err
+	}
+	field := dao.GetVersionField(storageId)
+	if field == "" {
+		return filter, false, nil
+	}
+	current, next, err := godal.NextVersion(colsAndVals, field)
+	if err != nil {
+		return nil, false, err
+	}
+	versionFilter, err := dao.BuildFilter(godal.VersionFilter(field, current))
+	if err != nil {
+		return nil, false, err
+	}
+	colsAndVals[field] = next
+	return &FilterAnd{Filters: []IFilter{filter, versionFilter}}, true, nil
+}
+
+// setWrittenVersion sets the version written to the stored row (found in colsAndVals, see versionedFilter) to bo if 'written' is true.
+func (dao *GenericDaoSql) setWrittenVersion(storageId string, bo godal.IGenericBo, colsAndVals map[string]interface{}, written bool) error {
+	if !written {
+		return nil
+	}
+	version, err := reddo.ToInt(colsAndVals[dao.GetVersionField(storageId)])
+	if err != nil {
+		return err
+	}
+	return dao.SetBoVersion(storageId, bo, version)
+}
+
+// versionMismatchError is called when a versioned write matches no row: it returns godal.GdaoErrorConcurrentModification if the BO exists
+// (i.e. the version did not match), nil otherwise.
+func (dao *GenericDaoSql) versionMismatchError(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) error {
+	exists, err := dao.GdaoExistsWithTx(ctx, tx, storageId, dao.GdaoCreateFilter(storageId, bo))
+	if err == nil && exists {
+		err = godal.GdaoErrorConcurrentModification
+	}
+	return err
+}
+
+/*
+GdaoPatch implements godal.IGenericDao.GdaoPatch.
+*/
+func (dao *GenericDaoSql) GdaoPatch(storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithTx(nil, nil, storageId, bo, patch)
+}
+
+/*
+GdaoPatchWithTx is extended-implementation of godal.IGenericDao.GdaoPatch.
+
+	- PatchOpSet sets the column to the value, PatchOpUnset sets the column to NULL, PatchOpIncrement sets the column to "COALESCE(column,0)+delta".
+	- PatchOpAppend is not supported.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoPatchWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoPatch", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforePatch(ctx, storageId, bo, patch); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.patchWithTx(ctx, tx, storageId, bo, patch)
+	return dao.AfterPatch(ctx, storageId, bo, patch, numRows, err)
+}
+
+// patchWithTx patches an existing row, see GdaoPatchWithTx.
+func (dao *GenericDaoSql) patchWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	if patch == nil || len(patch.Fields) == 0 {
+		return 0, errors.New("patch must have at least one field")
+	}
+	filter, err := dao.BuildFilter(dao.GdaoCreateFilter(storageId, bo))
+	if err != nil {
+		return 0, err
+	}
+	colsAndVals := make(map[string]interface{})
+	increments := make(map[string]interface{})
+	for _, f := range patch.Fields {
+		switch f.Operator {
+		case godal.PatchOpSet:
+			colsAndVals[f.Field] = f.Value
+		case godal.PatchOpUnset:
+			colsAndVals[f.Field] = nil
+		case godal.PatchOpIncrement:
+			increments[f.Field] = f.Value
+		default:
+			return 0, errors.New(fmt.Sprintf("unsupported patch operator %d on column [%s]", f.Operator, f.Field))
+		}
+	}
+	builder := NewUpdateBuilder().WithFlavor(dao.sqlFlavor).WithTable(storageId).WithValues(colsAndVals).AddIncrements(increments).WithFilter(filter)
+	var bindColumns []string
+	builder.WithPlaceholderGenerator(dao.placeholderGenerator(builder.PlaceholderGenerator, &bindColumns))
+	sqlStm, values := builder.Build()
+	if result, err := dao.sqlExecute(ctx, tx, sqlStm, bindColumns, values); err != nil {
+		if dao.isErrorDuplicatedEntry(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	} else {
+		numRows, err := result.RowsAffected()
+		return int(numRows), err
+	}
+}
+
+/*
+GdaoUpdateDirty implements godal.IGenericDao.GdaoUpdateDirty.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoUpdateDirty(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithTx(nil, nil, storageId, bo)
+}
+
+/*
+GdaoUpdateDirtyWithTx is extended-implementation of godal.IGenericDao.GdaoUpdateDirty.
+
+	- only columns of the BO's dirty attributes (see godal.DirtyAttrs) are updated, columns of attributes removed via
+	  godal.IGenericBo.GboDeleteAttr are set to NULL.
+	- columns of the dirty attributes are those the row mapper returns for a BO containing only these attributes; their values are taken
+	  from the row of the whole BO (hence row mappers that pack several attributes into one column still write the complete column).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoUpdateDirtyWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoUpdateDirty", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if !bo.GboIsDirty() {
+		return 0, nil
+	}
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeUpdate, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.updateDirtyWithTx(ctx, tx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterUpdate, storageId, bo, numRows, err)
+}
+
+// updateDirtyWithTx updates columns of dirty attributes of an existing row, see GdaoUpdateDirtyWithTx.
+func (dao *GenericDaoSql) updateDirtyWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
+	row, err := dao.GetRowMapper().ToRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return 0, err
+	}
+	dirtyBo := godal.NewGenericBo()
+	for _, attr := range godal.DirtyAttrs(bo) {
+		dirtyBo.GboSetAttr(attr, bo.GboGetAttrUnsafe(attr, nil))
+	}
+	for _, attr := range bo.GboDeletedAttrs() {
+		dirtyBo.GboSetAttr(attr, nil)
+		dirtyBo.GboDeleteAttr(attr)
+	}
+	dirtyRow, err := dao.GetRowMapper().ToRow(storageId, dirtyBo)
+	if err != nil {
+		return 0, err
+	}
+	dirtyCols, err := reddo.ToMap(dirtyRow, reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return 0, err
+	}
+	filter, versioned, err := dao.versionedFilter(storageId, bo, colsAndVals.(map[string]interface{}))
+	if err != nil {
+		return 0, err
+	}
+	toUpdate := make(map[string]interface{})
+	for col := range dirtyCols.(map[string]interface{}) {
+		if v, ok := colsAndVals.(map[string]interface{})[col]; ok {
+			toUpdate[col] = v
+		}
+	}
+	if versioned {
+		field := dao.GetVersionField(storageId)
+		toUpdate[field] = colsAndVals.(map[string]interface{})[field]
+	}
+	if len(toUpdate) == 0 {
+		return 0, nil
+	}
+	if result, err := dao.SqlUpdate(ctx, tx, storageId, toUpdate, filter); err != nil {
+		if dao.isErrorDuplicatedEntry(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	} else if numRows, err := result.RowsAffected(); err != nil || numRows > 0 || !versioned {
+		if err == nil {
+			err = dao.setWrittenVersion(storageId, bo, colsAndVals.(map[string]interface{}), versioned)
+		}
+		return int(numRows), err
+	} else {
+		return 0, dao.versionMismatchError(ctx, tx, storageId, bo)
+	}
+}
+
+/*
+GdaoSave implements godal.IGenericDao.GdaoSave.
+*/
+func (dao *GenericDaoSql) GdaoSave(storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoSaveCtx(nil, storageId, bo)
+}
+
+/*
+GdaoSaveWithTx is extended-implementation of godal.IGenericDao.GdaoSave.
+
+If the key columns of the table are known (see SetKeyColumns), the row is written with a single "insert-or-update" statement (since v0.3.0).
+
+Available: since v0.1.0
+*/
+func (dao *GenericDaoSql) GdaoSaveWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (numRows int, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSave", storageId)
+	defer func() { op.Finish(numRows, err) }()
+	if err := dao.BeforeWrite(ctx, godal.HookBeforeSave, storageId, bo); err != nil {
+		return 0, err
+	}
+	numRows, err = dao.saveWithTx(ctx, tx, storageId, bo)
+	return dao.AfterWrite(ctx, godal.HookAfterSave, storageId, bo, numRows, err)
+}
+
+// saveWithTx updates an existing row or inserts a new one, see GdaoSaveWithTx.
+func (dao *GenericDaoSql) saveWithTx(ctx context.Context, tx *sql.Tx, storageId string, bo godal.IGenericBo) (int, error) {
+	row, err := dao.GetRowMapper().ToRow(storageId, bo)
+	if err != nil {
+		return 0, err
+	}
+	colsAndVals, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return 0, err
+	}
+	filter, versioned, err := dao.versionedFilter(storageId, bo, colsAndVals.(map[string]interface{}))
+	if err != nil {
+		return 0, err
+	}
+
+	if keyColumns := dao.upsertKeyColumns(storageId, colsAndVals.(map[string]interface{})); keyColumns != nil && !versioned {
+		// native "insert-or-update" in one statement
+		if result, err := dao.SqlUpsert(ctx, tx, storageId, colsAndVals.(map[string]interface{}), keyColumns); err != nil {
+			if dao.isErrorDuplicatedEntry(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		} else {
+			numRows, err := result.RowsAffected()
+			if err == nil && (dao.sqlFlavor == prom.FlavorMySql || numRows == 0) {
+				// ON DUPLICATE KEY UPDATE reports 1 for an inserted row, 2 for an updated row and 0 for an unchanged row;
+				// Oracle's MERGE reports 0 for an existing row if all columns are key columns (see UpsertBuilder.Build)
+				numRows = 1
+			}
+			return int(numRows), err
+		}
+	}
+
+	// firstly: try to update row
+	if result, err := dao.SqlUpdate(ctx, tx, storageId, colsAndVals.(map[string]interface{}), filter); err != nil {
+		if dao.isErrorDuplicatedEntry(err) {
+			return 0, godal.GdaoErrorDuplicatedEntry
+		}
+		return 0, err
+	} else if numRows, err := result.RowsAffected(); err != nil || numRows > 0 {
+		if err == nil {
+			err = dao.setWrittenVersion(storageId, bo, colsAndVals.(map[string]interface{}), versioned)
+		}
+		return int(numRows), err
+	} else {
+		if versioned {
+			// the row exists but its version does not match
+			if err := dao.versionMismatchError(ctx, tx, storageId, bo); err != nil {
+				return 0, err
+			}
+		}
+		// secondly: no row updated, try insert row
+		if result, err := dao.SqlInsert(ctx, tx, storageId, colsAndVals.(map[string]interface{})); err != nil {
+			if dao.isErrorDuplicatedEntry(err) {
+				return 0, godal.GdaoErrorDuplicatedEntry
+			}
+			return 0, err
+		} else {
+			numRows, err := result.RowsAffected()
+			if err == nil {
+				err = dao.setWrittenVersion(storageId, bo, colsAndVals.(map[string]interface{}), versioned && numRows > 0)
+			}
+			return int(numRows), err
+		}
+	}
+}
+
+// upsertKeyColumns returns the key columns to save a row to the table with a native "insert-or-update" statement, nil if not applicable
+// (see SetKeyColumns).
+func (dao *GenericDaoSql) upsertKeyColumns(table string, row map[string]interface{}) []string {
+	switch dao.sqlFlavor {
+	case prom.FlavorPgSql, prom.FlavorMsSql, prom.FlavorOracle:
+	case prom.FlavorMySql:
+		if !dao.mysqlUpsertTables[table] {
+			return nil
+		}
+	default:
+		return nil
+	}
+	keyColumns := dao.keyColumns[table]
+	for _, col := range keyColumns {
+		if _, ok := row[col]; !ok {
+			return nil
+		}
+	}
+	return keyColumns
+}
+
+/*
+GdaoSaveMany implements godal.IGenericDao.GdaoSaveMany.
+*/
+func (dao *GenericDaoSql) GdaoSaveMany(storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoSaveManyCtx(nil, storageId, boList)
+}
+
+/*
+GdaoSaveManyWithTx is extended-implementation of godal.IGenericDao.GdaoSaveMany.
+
+There is no portable multi-row "update-or-insert" statement, BOs are saved one by one using GdaoSaveWithTx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoSaveManyWithTx(ctx context.Context, tx *sql.Tx, storageId string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSaveMany", storageId)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	for i, bo := range boList {
+		results[i].NumRows, results[i].Error = dao.GdaoSaveWithTx(ctx, tx, storageId, bo)
+	}
+	return results, bulkError(results)
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+GdaoDeleteCtx implements godal.IGenericDaoWithContext.GdaoDeleteCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoDeleteCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoDeleteWithTx(ctx, nil, storageId, bo)
+}
+
+/*
+GdaoDeleteManyCtx implements godal.IGenericDaoWithContext.GdaoDeleteManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoDeleteManyCtx(ctx context.Context, storageId string, filter interface{}) (int, error) {
+	return dao.GdaoDeleteManyWithTx(ctx, nil, storageId, filter)
+}
+
+/*
+GdaoFetchOneCtx implements godal.IGenericDaoWithContext.GdaoFetchOneCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoFetchOneCtx(ctx context.Context, storageId string, filter interface{}) (godal.IGenericBo, error) {
+	return dao.GdaoFetchOneWithTx(ctx, nil, storageId, filter)
+}
+
+/*
+GdaoFetchManyCtx implements godal.IGenericDaoWithContext.GdaoFetchManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoFetchManyCtx(ctx context.Context, storageId string, filter interface{}, ordering interface{}, fromOffset, numRows int) ([]godal.IGenericBo, error) {
+	return dao.GdaoFetchManyWithTx(ctx, nil, storageId, filter, ordering, fromOffset, numRows)
+}
+
+/*
+GdaoFetchIteratorCtx implements godal.IGenericDaoWithContext.GdaoFetchIteratorCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoFetchIteratorCtx(ctx context.Context, storageId string, filter interface{}, ordering interface{}) (godal.IGenericBoIterator, error) {
+	return dao.GdaoFetchIteratorWithTx(ctx, nil, storageId, filter, ordering)
+}
+
+/*
+GdaoFetchPageCtx implements godal.IGenericDaoWithContext.GdaoFetchPageCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoFetchPageCtx(ctx context.Context, storageId string, filter interface{}, ordering interface{}, pageToken string, numItems int) ([]godal.IGenericBo, string, error) {
+	return dao.GdaoFetchPageWithTx(ctx, nil, storageId, filter, ordering, pageToken, numItems)
+}
+
+/*
+GdaoCountCtx implements godal.IGenericDaoWithContext.GdaoCountCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoCountCtx(ctx context.Context, storageId string, filter interface{}) (int64, error) {
+	return dao.GdaoCountWithTx(ctx, nil, storageId, filter)
+}
+
+/*
+GdaoExistsCtx implements godal.IGenericDaoWithContext.GdaoExistsCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoExistsCtx(ctx context.Context, storageId string, filter interface{}) (bool, error) {
+	return dao.GdaoExistsWithTx(ctx, nil, storageId, filter)
+}
+
+/*
+GdaoCreateCtx implements godal.IGenericDaoWithContext.GdaoCreateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoCreateCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoCreateWithTx(ctx, nil, storageId, bo)
+}
+
+/*
+GdaoCreateManyCtx implements godal.IGenericDaoWithContext.GdaoCreateManyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoCreateManyCtx(ctx context.Context, storageId string, boList []godal.IGenericBo) ([]godal.GdaoBulkResult, error) {
+	return dao.GdaoCreateManyWithTx(ctx, nil, storageId, boList)
+}
+
+/*
+GdaoUpdateCtx implements godal.IGenericDaoWithContext.GdaoUpdateCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoUpdateCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateWithTx(ctx, nil, storageId, bo)
+}
+
+/*
+GdaoPatchCtx implements godal.IGenericDaoWithContext.GdaoPatchCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoPatchCtx(ctx context.Context, storageId string, bo godal.IGenericBo, patch *godal.PatchOpt) (int, error) {
+	return dao.GdaoPatchWithTx(ctx, nil, storageId, bo, patch)
+}
+
+/*
+GdaoUpdateDirtyCtx implements godal.IGenericDaoWithContext.GdaoUpdateDirtyCtx.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoUpdateDirtyCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	return dao.GdaoUpdateDirtyWithTx(ctx, nil, storageId, bo)
+}
+
+/*
+GdaoSaveCtx implements godal.IGenericDaoWithContext.GdaoSaveCtx.
+
+	- if txModeOnWrite is enabled, the "update-or-insert" is wrapped inside a transaction started with ctx (or joins the transaction ctx is carrying).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoSaveCtx(ctx context.Context, storageId string, bo godal.IGenericBo) (int, error) {
+	var numRows int
+	var err error
+	if dao.txModeOnWrite {
+		err = dao.WrapTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+			var e error
+			numRows, e = dao.GdaoSaveWithTx(ctx, tx, storageId, bo)
+			return e
+		})
+	} else {
+		numRows, err = dao.GdaoSaveWithTx(ctx, nil, storageId, bo)
+	}
+	return numRows, err
+}
+
+/*
+GdaoSaveManyCtx implements godal.IGenericDaoWithContext.GdaoSaveManyCtx.
+
+	- BOs are saved one by one using GdaoSaveCtx, i.e. if txModeOnWrite is enabled each "update-or-insert" is wrapped inside its own transaction
+	  (or joins the transaction ctx is carrying).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoSaveManyCtx(ctx context.Context, storageId string, boList []godal.IGenericBo) (results []godal.GdaoBulkResult, err error) {
+	ctx, op := dao.startOp(ctx, "GdaoSaveMany", storageId)
+	defer func() { op.FinishBulk(results, err) }()
+	results = make([]godal.GdaoBulkResult, len(boList))
+	for i, bo := range boList {
+		results[i].NumRows, results[i].Error = dao.GdaoSaveCtx(ctx, storageId, bo)
+	}
+	return results, bulkError(results)
 }
 
 /*
 WrapTransaction wraps a function inside a transaction.
 
 	- txFunc: the function to wrap. If the function returns error, the transaction will be aborted, otherwise transaction is committed.
+	- if ctx is carrying a transaction started by the dao's GdaoWithTransaction, txFunc joins that transaction (no new transaction is
+	  started); godal.ErrTxEnded is returned if that transaction has ended.
+	- if the transaction fails with a retryable error, a new transaction is started and txFunc is re-run according to the DAO's retry
+	  policy (see godal.AbstractGenericDao.SetRetryPolicy, since v0.3.0); txFunc should have no side effects outside the transaction.
 
 Available: since v0.1.0
 */
 func (dao *GenericDaoSql) WrapTransaction(ctx context.Context, txFunc func(ctx context.Context, tx *sql.Tx) error) error {
+	if tx, err := dao.txFromContext(ctx); err != nil {
+		return err
+	} else if tx != nil {
+		return txFunc(ctx, tx)
+	}
+	return dao.retry(ctx, nil, func() error {
+		return dao.wrapTransaction(ctx, txFunc)
+	})
+}
+
+// wrapTransaction runs txFunc inside a new transaction, see WrapTransaction.
+func (dao *GenericDaoSql) wrapTransaction(ctx context.Context, txFunc func(ctx context.Context, tx *sql.Tx) error) (err error) {
 	var tx *sql.Tx
-	var err error
 	defer func() {
 		if tx != nil {
 			if err != nil {
 				tx.Rollback()
 			} else {
-				err = tx.Commit()
+				err = dao.TranslateError(tx.Commit())
 			}
 		}
 	}()
@@ -816,8 +2109,75 @@ func (dao *GenericDaoSql) WrapTransaction(ctx context.Context, txFunc func(ctx c
 		ctx, _ = dao.sqlConnect.NewContext()
 	}
 	if tx, err = dao.sqlConnect.GetDB().BeginTx(ctx, &sql.TxOptions{Isolation: dao.txIsolationLevel}); err != nil {
-		return err
+		return dao.TranslateError(err)
 	}
 	err = txFunc(ctx, tx)
 	return err
 }
+
+type ctxKeyTx struct{}
+
+// sqlTx is a transaction started by GdaoWithTransaction, carried by the context passed to txFunc.
+type sqlTx struct {
+	dao   *GenericDaoSql
+	tx    *sql.Tx
+	lock  sync.RWMutex
+	ended bool // txFunc has returned, the transaction is being (or has been) committed or rolled back
+}
+
+// end marks the transaction as ended.
+func (tx *sqlTx) end() {
+	tx.lock.Lock()
+	defer tx.lock.Unlock()
+	tx.ended = true
+}
+
+// txFromContext returns the transaction of this dao carried by ctx, nil if ctx is not carrying any; godal.ErrTxEnded is returned if
+// the transaction has ended.
+func (dao *GenericDaoSql) txFromContext(ctx context.Context) (*sql.Tx, error) {
+	if ctx == nil {
+		return nil, nil
+	}
+	tx, ok := ctx.Value(ctxKeyTx{}).(*sqlTx)
+	if !ok || tx.dao != dao {
+		return nil, nil
+	}
+	tx.lock.RLock()
+	defer tx.lock.RUnlock()
+	if tx.ended {
+		return nil, godal.ErrTxEnded
+	}
+	return tx.tx, nil
+}
+
+// boundDao returns the DAO (the outer one if possible) bound to ctx.
+func (dao *GenericDaoSql) boundDao(ctx context.Context) godal.IGenericDao {
+	if gdao, ok := dao.IGenericDao.(godal.IGenericDaoWithContext); ok {
+		return godal.NewContextBoundGenericDao(ctx, gdao)
+	}
+	return godal.NewContextBoundGenericDao(ctx, dao)
+}
+
+/*
+GdaoWithTransaction implements godal.ITransactionalDao.GdaoWithTransaction.
+
+	- the transaction is started with the dao's transaction isolation level (see SetTxIsolationLevel).
+	- all Gdao* calls made via the 'dao' passed to txFunc, as well as GdaoXxxCtx/SqlExecute/SqlQuery calls made with txCtx, join the transaction.
+	- the whole txFunc is re-run on retryable errors, see WrapTransaction (since v0.3.0).
+	- contexts carrying a transaction of another DAO do not join it; once txFunc returns, operations made with txCtx return godal.ErrTxEnded.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GdaoWithTransaction(ctx context.Context, txFunc func(txCtx context.Context, dao godal.IGenericDao) error) error {
+	if tx, err := dao.txFromContext(ctx); err != nil {
+		return err
+	} else if tx != nil {
+		return txFunc(ctx, dao.boundDao(ctx))
+	}
+	return dao.WrapTransaction(ctx, func(ctx context.Context, tx *sql.Tx) error {
+		ctxTx := &sqlTx{dao: dao, tx: tx}
+		defer ctxTx.end()
+		txCtx := context.WithValue(ctx, ctxKeyTx{}, ctxTx)
+		return txFunc(txCtx, dao.boundDao(txCtx))
+	})
+}
@@ -1,14 +1,24 @@
 package sql
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/btnguyen2k/consu/reddo"
 	"github.com/btnguyen2k/consu/semita"
 	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/prom"
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 const timeZone = "Asia/Ho_Chi_Minh"
@@ -371,6 +381,159 @@ func testGenericDao_GdaoFetchManyWithPaging(dao godal.IGenericDao, tableName str
 	}
 }
 
+func testGenericDao_GdaoFetchIterator(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoFetchIterator"
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       fmt.Sprintf("%03d", i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	iter, err := dao.GdaoFetchIterator(tableName, &FilterFieldValue{Field: colId, Operation: ">=", Value: "080"}, godal.NewSortingOpt().Add(colId, godal.SortOrderDesc))
+	if err != nil || iter == nil {
+		t.Fatalf("%s failed - Iterator: %v / Error: %e", name, iter, err)
+	}
+	defer func() { _ = iter.Close() }()
+	count := 0
+	for ; iter.Next(); count++ {
+		if bo := fromGbo(iter.Bo()); bo.Id != fmt.Sprintf("%03d", numItems-count-1) {
+			t.Fatalf("%s failed - Expected: %v / Received: %v", name, numItems-count-1, bo)
+		}
+	}
+	if err := iter.Err(); err != nil || count != 20 {
+		t.Fatalf("%s failed - NumItems: %v / Error: %e", name, count, err)
+	}
+}
+
+func testGenericDao_GdaoFetchPage(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoFetchPage"
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       fmt.Sprintf("%03d", i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	filter := &FilterFieldValue{Field: colId, Operation: ">=", Value: "020"}
+	sorting := godal.NewSortingOpt().Add(colUsername, godal.SortOrderDesc).Add(colId, godal.SortOrderAsc)
+	pageToken, fetched := "", make([]*MyBo, 0)
+	for numPages := 1; ; numPages++ {
+		boList, nextPageToken, err := dao.GdaoFetchPage(tableName, filter, sorting, pageToken, 7)
+		if err != nil || len(boList) > 7 || numPages > 12 {
+			t.Fatalf("%s failed - Page: %v / NumItems: %v / Error: %e", name, numPages, len(boList), err)
+		}
+		for _, gbo := range boList {
+			fetched = append(fetched, fromGbo(gbo))
+		}
+		if pageToken = nextPageToken; pageToken == "" {
+			break
+		}
+	}
+	if len(fetched) != 80 {
+		t.Fatalf("%s failed - Expected %v items / Received: %v", name, 80, len(fetched))
+	}
+	for i := 1; i < len(fetched); i++ {
+		prev, cur := fetched[i-1], fetched[i]
+		if prev.Username < cur.Username || (prev.Username == cur.Username && prev.Id >= cur.Id) {
+			t.Fatalf("%s failed - Items out of order: %v / %v", name, prev, cur)
+		}
+	}
+
+	if _, _, err := dao.GdaoFetchPage(tableName, filter, sorting, "invalid-token", 7); err != godal.GdaoErrorInvalidPageToken {
+		t.Fatalf("%s failed - Expected error %e / Received: %e", name, godal.GdaoErrorInvalidPageToken, err)
+	}
+}
+
+func testGenericDao_GdaoCountExists(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoCountExists"
+	numItems := 100
+	for i := 0; i < numItems; i++ {
+		bo := &MyBo{
+			Id:       fmt.Sprintf("%03d", i),
+			Username: strconv.Itoa(i),
+			Name:     "BO - " + strconv.Itoa(i),
+			Version:  i,
+		}
+		if numRows, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != int64(numItems) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, numItems, count, err)
+	}
+	if count, err := dao.GdaoCount(tableName, godal.FilterGte(colId, "080")); err != nil || count != 20 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 20, count, err)
+	}
+	if exists, err := dao.GdaoExists(tableName, godal.FilterEq(colUsername, "99")); err != nil || !exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, true, exists, err)
+	}
+	if exists, err := dao.GdaoExists(tableName, godal.FilterEq(colUsername, "100")); err != nil || exists {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, false, exists, err)
+	}
+}
+
+func testGenericDao_GdaoCreateManySaveMany(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoCreateManySaveMany"
+	for i := 0; i < 4; i += 2 {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		if numRows, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+	}
+
+	// BOs "0" and "2" already exist
+	boList := make([]godal.IGenericBo, 5)
+	for i := range boList {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: "user" + strconv.Itoa(i), Name: "BO - " + strconv.Itoa(i), Version: i}
+		boList[i] = bo.ToGbo()
+	}
+	results, err := dao.GdaoCreateMany(tableName, boList)
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if i < 4 && i%2 == 0 && (r.Error != godal.GdaoErrorDuplicatedEntry || r.NumRows != 0) {
+			t.Fatalf("%s failed - Item: %d / Expected error: %e / Result: %v", name, i, godal.GdaoErrorDuplicatedEntry, r)
+		}
+		if (i >= 4 || i%2 != 0) && (r.Error != nil || r.NumRows != 1) {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+
+	results, err = dao.GdaoSaveMany(tableName, boList)
+	if err != nil || len(results) != len(boList) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	for i, r := range results {
+		if r.Error != nil || r.NumRows != 1 {
+			t.Fatalf("%s failed - Item: %d / Result: %v", name, i, r)
+		}
+	}
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 5 {
+		t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 5, count, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "2"}); err != nil || gbo == nil || fromGbo(gbo).Username != "user2" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
 func testGenericDao_GdaoUpdateNotExist(dao godal.IGenericDao, tableName string, t *testing.T) {
 	name := "TestGenericDao_GdaoUpdateNotExist"
 	bo := &MyBo{
@@ -447,6 +610,601 @@ func testGenericDao_GdaoUpdate(dao godal.IGenericDao, tableName string, t *testi
 	}
 }
 
+func testGenericDao_GdaoPatch(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoPatch"
+	myBo := &MyBo{Id: "1", Username: "1", Name: "BO - 1", Version: 1}
+	if numRows, err := dao.GdaoCreate(tableName, myBo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+
+	patch := godal.NewPatchOpt().Set(colUsername, "patched")
+	if numRows, err := dao.GdaoPatch(tableName, (&MyBo{Id: "9"}).ToGbo(), patch); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoPatch(tableName, (&MyBo{Id: "1"}).ToGbo(), patch); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"})
+	if err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	// only the username column is patched, data column is left untouched
+	if username := gbo.GboGetAttrUnsafe(fieldGboUsername, reddo.TypeString); username != "patched" {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, "patched", username)
+	}
+	if bo := fromGbo(gbo); bo.Username != "1" || bo.Name != "BO - 1" || bo.Version != 1 {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	if _, err := dao.GdaoPatch(tableName, (&MyBo{Id: "1"}).ToGbo(), godal.NewPatchOpt().Append(colData, "x")); err == nil {
+		t.Fatalf("%s failed - Expected error for unsupported operator", name)
+	}
+}
+
+func testGenericDao_GdaoUpdateDirty(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoUpdateDirty"
+	myBo := &MyBo{Id: "1", Username: "1", Name: "BO - 1", Version: 1}
+	if numRows, err := dao.GdaoCreate(tableName, myBo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"})
+	if err != nil || gbo == nil || gbo.GboIsDirty() {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoUpdateDirty(tableName, gbo); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo.GboSetAttr(fieldGboUsername, "changed")
+	if numRows, err := dao.GdaoUpdateDirty(tableName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err = dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil || gbo == nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if username := gbo.GboGetAttrUnsafe(fieldGboUsername, reddo.TypeString); username != "changed" {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, "changed", username)
+	}
+	if bo := fromGbo(gbo); bo.Name != "BO - 1" {
+		t.Fatalf("%s failed - Received: %v", name, bo)
+	}
+
+	gbo = (&MyBo{Id: "9"}).ToGbo()
+	gbo.GboSetAttr(fieldGboUsername, "changed")
+	if numRows, err := dao.GdaoUpdateDirty(tableName, gbo); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+}
+
+// myBoValidator rejects BOs with empty name.
+var myBoValidator = godal.BoValidatorFunc(func(storageId string, gbo godal.IGenericBo) error {
+	if fromGbo(gbo).Name == "" {
+		return &godal.ValidationError{StorageId: storageId, Violations: []godal.ValidationViolation{{Path: fieldGboData, Message: "empty name"}}}
+	}
+	return nil
+})
+
+// testGenericDao_Validator expects myBoValidator to be attached to the table.
+func testGenericDao_Validator(dao godal.IGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_Validator"
+	isValidationError := func(err error) bool {
+		_, ok := err.(*godal.ValidationError)
+		return ok
+	}
+	if numRows, err := dao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	invalidBo := (&MyBo{Id: "2", Username: "2"}).ToGbo()
+	if _, err := dao.GdaoCreate(tableName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoSave(tableName, invalidBo); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	if _, err := dao.GdaoUpdate(tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); !isValidationError(err) {
+		t.Fatalf("%s failed - expected ValidationError, received: %e", name, err)
+	}
+	results, err := dao.GdaoCreateMany(tableName, []godal.IGenericBo{(&MyBo{Id: "3", Username: "3", Name: "BO"}).ToGbo(), invalidBo})
+	if !isValidationError(err) || len(results) != 2 || results[0].Error != nil || results[0].NumRows != 1 || !isValidationError(results[1].Error) {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if exists, err := dao.GdaoExists(tableName, map[string]interface{}{colId: "2"}); err != nil || exists {
+		t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+	}
+	if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil || gbo == nil || fromGbo(gbo).Name != "BO" {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+// testGenericDao_Hooks registers hooks to agdao, which must be the AbstractGenericDao of dao.
+func testGenericDao_Hooks(dao godal.IGenericDao, agdao *godal.AbstractGenericDao, tableName string, t *testing.T) {
+	name := "TestGenericDao_Hooks"
+	agdao.SetValidator(tableName, myBoValidator)
+	counters := make(map[godal.HookPoint]int)
+	errAbort := errors.New("abort")
+	for _, point := range []godal.HookPoint{godal.HookBeforeCreate, godal.HookAfterCreate, godal.HookBeforeUpdate, godal.HookAfterUpdate,
+		godal.HookBeforeSave, godal.HookAfterSave, godal.HookBeforeDelete, godal.HookAfterDelete, godal.HookAfterFetch} {
+		point := point
+		agdao.AddHook(point, func(ctx context.Context, storageId string, gbo godal.IGenericBo) error {
+			if ctx == nil || storageId != tableName {
+				return errors.New("invalid hook arguments")
+			}
+			counters[point]++
+			return nil
+		})
+	}
+	stamp := func(ctx context.Context, storageId string, gbo godal.IGenericBo) error {
+		if bo := fromGbo(gbo); bo.Name == "" {
+			bo.Name = "stamped"
+			js, _ := json.Marshal(bo)
+			return gbo.GboSetAttr(fieldGboData, string(js))
+		}
+		return nil
+	}
+	agdao.AddHook(godal.HookBeforeCreate, stamp).AddHook(godal.HookBeforeUpdate, stamp).AddHook(godal.HookBeforeSave, stamp)
+	agdao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, gbo godal.IGenericBo) error {
+		return gbo.GboSetAttr("fetched", true)
+	})
+	agdao.AddHook(godal.HookBeforeDelete, func(ctx context.Context, storageId string, gbo godal.IGenericBo) error {
+		if gbo.GboGetAttrUnsafe(fieldGboId, reddo.TypeString) == "locked" {
+			return errAbort
+		}
+		return nil
+	})
+
+	// "before" hooks run before validation, changes made by hooks are written
+	if numRows, err := dao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"})
+	if err != nil || gbo == nil || fromGbo(gbo).Name != "stamped" || gbo.GboGetAttrUnsafe("fetched", nil) != true || gbo.GboIsDirty() {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	if numRows, err := dao.GdaoUpdate(tableName, (&MyBo{Id: "1", Username: "1"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoUpdate(tableName, (&MyBo{Id: "0", Username: "0"}).ToGbo()); err != nil || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoSave(tableName, (&MyBo{Id: "locked", Username: "locked"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	results, err := dao.GdaoCreateMany(tableName, []godal.IGenericBo{(&MyBo{Id: "2", Username: "2"}).ToGbo(), (&MyBo{Id: "1", Username: "1"}).ToGbo()})
+	if err != godal.GdaoErrorDuplicatedEntry || len(results) != 2 || results[0].NumRows != 1 || results[1].Error != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if numRows, err := dao.GdaoDelete(tableName, (&MyBo{Id: "locked"}).ToGbo()); err != errAbort || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if numRows, err := dao.GdaoDelete(tableName, (&MyBo{Id: "2"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if boList, err := dao.GdaoFetchMany(tableName, nil, nil, 0, 0); err != nil || len(boList) != 2 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	expected := map[godal.HookPoint]int{godal.HookBeforeCreate: 3, godal.HookAfterCreate: 2, godal.HookBeforeUpdate: 2, godal.HookAfterUpdate: 1,
+		godal.HookBeforeSave: 1, godal.HookAfterSave: 1, godal.HookBeforeDelete: 2, godal.HookAfterDelete: 1, godal.HookAfterFetch: 3}
+	if !reflect.DeepEqual(counters, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, counters)
+	}
+
+	// hooks can abort the operation
+	agdao.AddHook(godal.HookAfterFetch, func(ctx context.Context, storageId string, gbo godal.IGenericBo) error {
+		return errAbort
+	})
+	if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != errAbort || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	agdao.ClearHooks(godal.HookAfterFetch)
+	if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil || gbo == nil || gbo.GboGetAttrUnsafe("fetched", nil) != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+}
+
+func testGenericDao_RetryPolicy(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_RetryPolicy"
+	attempts := make([]godal.RetryAttempt, 0)
+	policy := godal.NewRetryPolicy(3)
+	policy.InitialBackoff = time.Millisecond
+	policy.OnAttempt = func(a godal.RetryAttempt) { attempts = append(attempts, a) }
+	dao.SetRetryPolicy(policy)
+
+	// the whole transaction is re-run on retryable errors
+	errDeadlock := godal.WrapError(godal.ErrDeadlock, errors.New("deadlock"))
+	numCalls := 0
+	err := dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		if _, err := txDao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+			return err
+		}
+		if numCalls < 2 {
+			return errDeadlock
+		}
+		return nil
+	})
+	if err != nil || numCalls != 2 || len(attempts) != 2 || !attempts[0].Retry || attempts[0].Err != errDeadlock || attempts[1].Err != nil {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 1 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+
+	// non-retryable errors are returned immediately
+	numCalls, attempts = 0, attempts[:0]
+	err = dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		numCalls++
+		_, err := txDao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo())
+		return err
+	})
+	if err != godal.GdaoErrorDuplicatedEntry || numCalls != 1 || len(attempts) != 1 {
+		t.Fatalf("%s failed - NumCalls: %d / Attempts: %v / Error: %e", name, numCalls, attempts, err)
+	}
+
+	// calls made outside of transactions are observed too
+	attempts = attempts[:0]
+	if _, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil || len(attempts) != 1 {
+		t.Fatalf("%s failed - Attempts: %v / Error: %e", name, attempts, err)
+	}
+}
+
+type ctxKeyTestOp struct{}
+
+type testInstrumentation struct {
+	lock     sync.Mutex
+	parents  map[*godal.DaoOperation]string
+	finished []*godal.DaoOperation
+}
+
+func (ti *testInstrumentation) OnStart(ctx context.Context, op *godal.DaoOperation) context.Context {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	parent, _ := ctx.Value(ctxKeyTestOp{}).(string)
+	ti.parents[op] = parent
+	return context.WithValue(ctx, ctxKeyTestOp{}, op.Operation)
+}
+
+func (ti *testInstrumentation) OnFinish(ctx context.Context, op *godal.DaoOperation) {
+	ti.lock.Lock()
+	defer ti.lock.Unlock()
+	ti.finished = append(ti.finished, op)
+}
+
+func testGenericDao_Instrumentation(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_Instrumentation"
+	instrumentation := &testInstrumentation{parents: make(map[*godal.DaoOperation]string)}
+	dao.SetInstrumentation(instrumentation)
+	defer dao.SetInstrumentation(nil)
+
+	// SqlExecute is reported with the final statement, nested in the Gdao* call
+	if _, err := dao.GdaoCreateCtx(context.Background(), tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if len(instrumentation.finished) != 2 {
+		t.Fatalf("%s failed - expected 2 operations but received %d", name, len(instrumentation.finished))
+	}
+	op := instrumentation.finished[0]
+	if op.Backend != "sql" || op.Operation != "SqlExecute" || !strings.HasPrefix(strings.ToUpper(op.Statement), "INSERT INTO") ||
+		op.NumBindings != 3 || op.NumRows != 1 || op.Err != nil || instrumentation.parents[op] != "GdaoCreate" {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+	op = instrumentation.finished[1]
+	if op.Operation != "GdaoCreate" || op.StorageId != tableName || op.NumRows != 1 || op.Err != nil || instrumentation.parents[op] != "" {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+
+	// errors are reported
+	instrumentation.finished = instrumentation.finished[:0]
+	_, err := dao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo())
+	if err != godal.GdaoErrorDuplicatedEntry || len(instrumentation.finished) == 0 {
+		t.Fatalf("%s failed - Operations: %v / Error: %e", name, instrumentation.finished, err)
+	}
+	if op = instrumentation.finished[len(instrumentation.finished)-1]; op.Operation != "GdaoCreate" || op.NumRows != 0 || op.Err != err {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+
+	// SqlQuery is reported for fetch operations
+	instrumentation.finished = instrumentation.finished[:0]
+	if boList, err := dao.GdaoFetchMany(tableName, map[string]interface{}{colId: "1"}, nil, 0, 0); err != nil || len(boList) != 1 {
+		t.Fatalf("%s failed - BoList: %v / Error: %e", name, boList, err)
+	}
+	if len(instrumentation.finished) != 2 || instrumentation.finished[0].Operation != "SqlQuery" ||
+		!strings.HasPrefix(strings.ToUpper(instrumentation.finished[0].Statement), "SELECT") || instrumentation.finished[0].NumBindings != 1 {
+		t.Fatalf("%s failed - Operations: %v", name, instrumentation.finished)
+	}
+	if op = instrumentation.finished[1]; op.Operation != "GdaoFetchMany" || op.NumRows != 1 || op.Duration < instrumentation.finished[0].Duration {
+		t.Fatalf("%s failed - Operation: %v", name, op)
+	}
+}
+
+func testGenericDao_StatementLogger(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_StatementLogger"
+	var lock sync.Mutex
+	entries := make([]*SqlStatementLogEntry, 0)
+	dao.SetStatementLogger(SqlStatementLoggerFunc(func(ctx context.Context, entry *SqlStatementLogEntry) {
+		lock.Lock()
+		defer lock.Unlock()
+		entries = append(entries, entry)
+	})).AddRedactionRule(colData, nil)
+	defer dao.SetStatementLogger(nil)
+
+	bo := &MyBo{Id: "1", Username: "1", Name: "BO"}
+	if _, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	bo.Name = "BO - updated"
+	entries = entries[:0]
+	if numRows, err := dao.GdaoSave(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	var update *SqlStatementLogEntry
+	for _, entry := range entries {
+		if strings.HasPrefix(strings.ToUpper(entry.Statement), "UPDATE") {
+			update = entry
+		}
+	}
+	if update == nil || update.Operation != "SqlExecute" || update.Flavor != dao.sqlFlavor || update.InTx != dao.txModeOnWrite ||
+		update.Level != SqlLogLevelInfo || update.Err != nil || len(update.Values) != 4 || len(update.Columns) != 4 {
+		t.Fatalf("%s failed - Entries: %v", name, entries)
+	}
+	for i, col := range update.Columns {
+		if (strings.EqualFold(col, colData) && update.Values[i] != "***") || (strings.EqualFold(col, colId) && update.Values[i] != "1") {
+			t.Fatalf("%s failed - Entry: %v", name, update)
+		}
+	}
+
+	// slow statements are logged at warn level
+	dao.SetSlowStatementThreshold(time.Nanosecond)
+	defer dao.SetSlowStatementThreshold(0)
+	entries = entries[:0]
+	if _, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if len(entries) != 1 || entries[0].Operation != "SqlQuery" || entries[0].Level != SqlLogLevelWarn || entries[0].InTx {
+		t.Fatalf("%s failed - Entries: %v", name, entries)
+	}
+}
+
+func testGenericDao_StmtCache(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_StmtCache"
+	dao.SetStmtCacheCapacity(2)
+	defer dao.SetStmtCacheCapacity(0)
+
+	for i := 1; i <= 3; i++ {
+		bo := &MyBo{Id: strconv.Itoa(i), Username: strconv.Itoa(i), Name: "BO"}
+		if numRows, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		bo.Name = "BO - updated"
+		if numRows, err := dao.GdaoSave(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: strconv.Itoa(i)}); err != nil || gbo == nil {
+			t.Fatalf("%s failed - Bo: %v / Error: %e", name, gbo, err)
+		} else if myBo := fromGbo(gbo); myBo == nil || myBo.Name != bo.Name {
+			t.Fatalf("%s failed - Bo: %v", name, myBo)
+		}
+		if n := dao.stmtCache.len(); n < 1 || n > 2 {
+			t.Fatalf("%s failed - unexpected number of cached statements: %d", name, n)
+		}
+	}
+
+	// statements are executed directly if preparation is disabled
+	dao.SetPrepareStatements(false)
+	defer dao.SetPrepareStatements(true)
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 3 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+}
+
+func testGenericDao_GdaoSaveUpsert(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_GdaoSaveUpsert"
+	var lock sync.Mutex
+	statements := make([]string, 0)
+	dao.SetStatementLogger(SqlStatementLoggerFunc(func(ctx context.Context, entry *SqlStatementLogEntry) {
+		lock.Lock()
+		defer lock.Unlock()
+		statements = append(statements, strings.ToUpper(entry.Statement))
+	})).SetKeyColumns(tableName, colId).SetMySqlUpsert(tableName, true)
+	defer dao.SetStatementLogger(nil).SetKeyColumns(tableName).SetMySqlUpsert(tableName, false)
+
+	// inserted, updated then unchanged: one statement each, reporting 1 row
+	for _, bo := range []*MyBo{{Id: "1", Username: "1", Name: "BO"}, {Id: "1", Username: "1", Name: "BO - updated"}, {Id: "1", Username: "1", Name: "BO - updated"}} {
+		statements = statements[:0]
+		if numRows, err := dao.GdaoSave(tableName, bo.ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		if len(statements) != 1 || strings.HasPrefix(statements[0], "UPDATE") {
+			t.Fatalf("%s failed - Statements: %v", name, statements)
+		}
+		if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: bo.Id}); err != nil || gbo == nil {
+			t.Fatalf("%s failed - Bo: %v / Error: %e", name, gbo, err)
+		} else if myBo := fromGbo(gbo); myBo == nil || myBo.Name != bo.Name {
+			t.Fatalf("%s failed - Bo: %v", name, myBo)
+		}
+	}
+
+	// the test table has another unique key (username): native upsert must not be enabled on MySQL
+	dao.SetMySqlUpsert(tableName, false)
+	statements = statements[:0]
+	if numRows, err := dao.GdaoSave(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO"}).ToGbo()); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if native := dao.sqlFlavor != prom.FlavorMySql; len(statements) == 0 || strings.HasPrefix(statements[0], "UPDATE") == native {
+		t.Fatalf("%s failed - Statements: %v", name, statements)
+	}
+
+	// violating another unique key is reported as a duplicate
+	bo := &MyBo{Id: "2", Username: "1", Name: "BO"}
+	if _, err := dao.GdaoSave(tableName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Expected: %e / Received: %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+}
+
+func testGenericDao_Returning(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_Returning"
+	var lock sync.Mutex
+	entries := make([]*SqlStatementLogEntry, 0)
+	dao.SetStatementLogger(SqlStatementLoggerFunc(func(ctx context.Context, entry *SqlStatementLogEntry) {
+		lock.Lock()
+		defer lock.Unlock()
+		entries = append(entries, entry)
+	})).SetReturningColumns(tableName, colId, colUsername)
+	defer dao.SetStatementLogger(nil).SetReturningColumns(tableName)
+	// PostgreSQL and MSSQL return the columns as a result set, hence execute the statement as a query
+	expectedOp := "SqlExecute"
+	if dao.sqlFlavor == prom.FlavorPgSql || dao.sqlFlavor == prom.FlavorMsSql {
+		expectedOp = "SqlQuery"
+	}
+
+	bo := &MyBo{Id: "1", Username: "1", Name: "BO"}
+	gbo := bo.ToGbo()
+	if numRows, err := dao.GdaoCreate(tableName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if len(entries) != 1 || entries[0].Operation != expectedOp {
+		t.Fatalf("%s failed - Entries: %v", name, entries)
+	}
+	if myBo := fromGbo(gbo); myBo.Id != bo.Id || myBo.Username != bo.Username || myBo.Name != bo.Name {
+		t.Fatalf("%s failed - Bo: %v", name, myBo)
+	}
+
+	bo.Name = "BO - updated"
+	gbo = bo.ToGbo()
+	entries = entries[:0]
+	if numRows, err := dao.GdaoUpdate(tableName, gbo); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if len(entries) != 1 || entries[0].Operation != expectedOp {
+		t.Fatalf("%s failed - Entries: %v", name, entries)
+	}
+	if myBo := fromGbo(gbo); myBo.Id != bo.Id || myBo.Name != bo.Name {
+		t.Fatalf("%s failed - Bo: %v", name, myBo)
+	}
+	if gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: bo.Id}); err != nil || gbo == nil || fromGbo(gbo).Name != bo.Name {
+		t.Fatalf("%s failed - Bo: %v / Error: %e", name, gbo, err)
+	}
+
+	if _, err := dao.GdaoCreate(tableName, bo.ToGbo()); err != godal.GdaoErrorDuplicatedEntry {
+		t.Fatalf("%s failed - Expected: %e / Received: %e", name, godal.GdaoErrorDuplicatedEntry, err)
+	}
+}
+
+func testGenericDao_Transaction(dao, other *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_Transaction"
+
+	// committed: changes are visible once GdaoWithTransaction returns; the nested transaction joins the outer one
+	var staleCtx context.Context
+	err := dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		staleCtx = txCtx
+		if _, err := txDao.GdaoCreate(tableName, (&MyBo{Id: "1", Username: "1", Name: "BO - 1"}).ToGbo()); err != nil {
+			return err
+		}
+		return dao.GdaoWithTransaction(txCtx, func(nestedCtx context.Context, txDao godal.IGenericDao) error {
+			if nestedCtx != txCtx {
+				t.Fatalf("%s failed - nested transaction is expected to join the outer one", name)
+			}
+			if gbo, err := txDao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"}); err != nil || gbo == nil {
+				t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+			}
+			_, err := txDao.GdaoCreate(tableName, (&MyBo{Id: "2", Username: "2", Name: "BO - 2"}).ToGbo())
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 2 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+
+	// rolled back on error; the second dao does not join the transaction, its write is committed on its own
+	errAbort := errors.New("abort")
+	err = dao.GdaoWithTransaction(nil, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		if _, err := txDao.GdaoCreate(tableName, (&MyBo{Id: "3", Username: "3", Name: "BO - 3"}).ToGbo()); err != nil {
+			return err
+		}
+		if numRows, err := other.GdaoCreateCtx(txCtx, tableName, (&MyBo{Id: "4", Username: "4", Name: "BO - 4"}).ToGbo()); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		return errAbort
+	})
+	if err != errAbort {
+		t.Fatalf("%s failed: expected error %e but received %e", name, errAbort, err)
+	}
+	for id, expected := range map[string]bool{"1": true, "2": true, "3": false, "4": true} {
+		if exists, err := dao.GdaoExists(tableName, map[string]interface{}{colId: id}); err != nil || exists != expected {
+			t.Fatalf("%s failed - Id: %s / Exists: %v / Error: %e", name, id, exists, err)
+		}
+	}
+
+	// txCtx is rejected once the transaction has ended
+	if numRows, err := dao.GdaoCreateCtx(staleCtx, tableName, (&MyBo{Id: "5", Username: "5", Name: "BO - 5"}).ToGbo()); err != godal.ErrTxEnded || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	if gbo, err := dao.GdaoFetchOneCtx(staleCtx, tableName, map[string]interface{}{colId: "1"}); err != godal.ErrTxEnded || gbo != nil {
+		t.Fatalf("%s failed - Gbo: %v / Error: %e", name, gbo, err)
+	}
+	err = dao.GdaoWithTransaction(staleCtx, func(txCtx context.Context, txDao godal.IGenericDao) error {
+		t.Fatalf("%s failed - txFunc is not expected to be called", name)
+		return nil
+	})
+	if err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if count, err := dao.GdaoCount(tableName, nil); err != nil || count != 3 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+}
+
+func testGenericDao_OptimisticLocking(dao *GenericDaoSql, tableName string, t *testing.T) {
+	name := "TestGenericDao_OptimisticLocking"
+	if _, err := dao.sqlConnect.GetDB().Exec(fmt.Sprintf("ALTER TABLE %s ADD ver INT", tableName)); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	dao.SetRowMapper(&GenericRowMapperSql{NameTransformation: NameTransfLowerCase})
+	dao.SetVersionField(tableName, "ver")
+	newBo := func(name string, version int) godal.IGenericBo {
+		gbo := (&MyBo{Id: "1", Username: "1", Name: name}).ToGbo()
+		gbo.GboSetAttr("ver", version)
+		return gbo
+	}
+	verify := func(expectedName string, expectedVersion int64) {
+		gbo, err := dao.GdaoFetchOne(tableName, map[string]interface{}{colId: "1"})
+		if err != nil || gbo == nil {
+			t.Fatalf("%s failed - Bo: %v / Error: %e", name, gbo, err)
+		}
+		if myBo, version := fromGbo(gbo), gbo.GboGetAttrUnsafe("ver", reddo.TypeInt); myBo.Name != expectedName || version != expectedVersion {
+			t.Fatalf("%s failed - Expected: %v/%v / Received: %v/%v", name, expectedName, expectedVersion, myBo.Name, version)
+		}
+	}
+
+	if numRows, err := dao.GdaoCreate(tableName, newBo("BO", 1)); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	verify("BO", 1)
+	current := newBo("BO - updated", 1)
+	if numRows, err := dao.GdaoUpdate(tableName, current); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	verify("BO - updated", 2)
+
+	// writes made with a stale version fail and leave the stored row unchanged
+	if numRows, err := dao.GdaoUpdate(tableName, newBo("BO - stale update", 1)); err != godal.GdaoErrorConcurrentModification || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	verify("BO - updated", 2)
+	if numRows, err := dao.GdaoSave(tableName, newBo("BO - stale save", 1)); err != godal.GdaoErrorConcurrentModification || numRows != 0 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	verify("BO - updated", 2)
+
+	// the written BO carries the new version, hence can be written again
+	current.GboSetAttr(fieldGboData, newBo("BO - saved", 0).GboGetAttrUnsafe(fieldGboData, nil))
+	if numRows, err := dao.GdaoSave(tableName, current); err != nil || numRows != 1 {
+		t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+	}
+	verify("BO - saved", 3)
+}
+
 func testGenericDao_GdaoSaveDuplicated_TxModeOff(dao godal.IGenericDao, tableName string, t *testing.T) {
 	name := "TestGenericDao_GdaoSaveDuplicated_TxModeOff"
 	for i := 1; i <= 3; i++ {
@@ -600,3 +1358,462 @@ func testGenericDao_GdaoSave_TxModeOn(dao godal.IGenericDao, tableName string, t
 		t.Fatalf("%s failed - Expected: %v / Received: %v", name, bo, myBo)
 	}
 }
+
+func TestGenericDaoSql_IGenericDaoWithContext(t *testing.T) {
+	name := "TestGenericDaoSql_IGenericDaoWithContext"
+	var dao interface{} = &GenericDaoSql{}
+	if _, ok := dao.(godal.IGenericDaoWithContext); !ok {
+		t.Fatalf("%s failed: GenericDaoSql does not implement godal.IGenericDaoWithContext", name)
+	}
+}
+
+func TestGenericDaoSql_ITransactionalDao(t *testing.T) {
+	name := "TestGenericDaoSql_ITransactionalDao"
+	var dao interface{} = &GenericDaoSql{}
+	if _, ok := dao.(godal.ITransactionalDao); !ok {
+		t.Fatalf("%s failed: GenericDaoSql does not implement godal.ITransactionalDao", name)
+	}
+}
+
+func TestGenericDaoSql_TranslateError(t *testing.T) {
+	name := "TestGenericDaoSql_TranslateError"
+	testCases := []struct {
+		flavor   prom.DbFlavor
+		err      error
+		expected error
+	}{
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, godal.ErrConflict},
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}, godal.ErrConstraintViolation},
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, godal.ErrDeadlock},
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}, godal.ErrTimeout},
+		{prom.FlavorPgSql, &pq.Error{Code: "23505"}, godal.ErrConflict},
+		{prom.FlavorPgSql, &pq.Error{Code: "23502"}, godal.ErrConstraintViolation},
+		{prom.FlavorPgSql, &pq.Error{Code: "40P01"}, godal.ErrDeadlock},
+		{prom.FlavorPgSql, &pq.Error{Code: "40001"}, godal.ErrConcurrentModification},
+		{prom.FlavorPgSql, &pq.Error{Code: "53300"}, godal.ErrThrottled},
+		{prom.FlavorPgSql, &pq.Error{Code: "42P01"}, godal.ErrNotFound},
+		{prom.FlavorMsSql, mssql.Error{Number: 2627}, godal.ErrConflict},
+		{prom.FlavorMsSql, mssql.Error{Number: 547}, godal.ErrConstraintViolation},
+		{prom.FlavorMsSql, mssql.Error{Number: 1205}, godal.ErrDeadlock},
+		{prom.FlavorOracle, errors.New("ORA-00001: unique constraint violated"), godal.ErrConflict},
+		{prom.FlavorOracle, errors.New("ORA-00060: deadlock detected"), godal.ErrDeadlock},
+		{prom.FlavorOracle, errors.New("ORA-00942: table or view does not exist"), godal.ErrNotFound},
+		{prom.FlavorDefault, context.DeadlineExceeded, godal.ErrTimeout},
+		{prom.FlavorMySql, fmt.Errorf("wrapped: %w", context.DeadlineExceeded), godal.ErrTimeout},
+	}
+	for _, tc := range testCases {
+		dao := &GenericDaoSql{sqlFlavor: tc.flavor}
+		err := dao.TranslateError(tc.err)
+		if !errors.Is(err, tc.expected) || !errors.Is(err, tc.err) {
+			t.Fatalf("%s failed - Input: %v / Expected: %v / Received: %v", name, tc.err, tc.expected, err)
+		}
+		if dao.TranslateError(err) != err {
+			t.Fatalf("%s failed - translated error must be returned as-is: %v", name, err)
+		}
+		if !dao.isErrorDuplicatedEntry(err) != (tc.expected != godal.ErrConflict) {
+			t.Fatalf("%s failed - Input: %v / isErrorDuplicatedEntry: %v", name, tc.err, dao.isErrorDuplicatedEntry(err))
+		}
+	}
+
+	dao := &GenericDaoSql{sqlFlavor: prom.FlavorMySql}
+	unknown := &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}
+	if err := dao.TranslateError(unknown); err != unknown {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, unknown, err)
+	}
+	if err := dao.TranslateError(nil); err != nil {
+		t.Fatalf("%s failed - Expected nil / Received: %v", name, err)
+	}
+}
+
+func TestGenericDaoSql_IsRetryableError(t *testing.T) {
+	name := "TestGenericDaoSql_IsRetryableError"
+	testCases := []struct {
+		flavor    prom.DbFlavor
+		err       error
+		retryable bool
+	}{
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}, true},
+		{prom.FlavorMySql, &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}, false},
+		{prom.FlavorPgSql, &pq.Error{Code: "40001"}, true},
+		{prom.FlavorPgSql, &pq.Error{Code: "40P01"}, true},
+		{prom.FlavorPgSql, &pq.Error{Code: "53300"}, true},
+		{prom.FlavorPgSql, &pq.Error{Code: "57014"}, false},
+		{prom.FlavorMsSql, mssql.Error{Number: 1205}, true},
+		{prom.FlavorOracle, errors.New("ORA-08177: can't serialize access for this transaction"), true},
+		{prom.FlavorMySql, godal.GdaoErrorConcurrentModification, false},
+		{prom.FlavorMySql, nil, false},
+	}
+	for _, tc := range testCases {
+		dao := &GenericDaoSql{sqlFlavor: tc.flavor}
+		if dao.IsRetryableError(tc.err) != tc.retryable {
+			t.Fatalf("%s failed - Input: %v / Expected retryable: %v", name, tc.err, tc.retryable)
+		}
+	}
+}
+
+func TestGenericDaoSql_BuildFilter_FilterOpt(t *testing.T) {
+	name := "TestGenericDaoSql_BuildFilter_FilterOpt"
+	dao := NewGenericDaoSql(nil, godal.NewAbstractGenericDao(nil))
+	filter := godal.FilterAnd(
+		godal.FilterEq("a", 1),
+		godal.FilterOr(godal.FilterGt("b", 2), godal.FilterLte("c", 3)),
+		godal.FilterIn("d", "x", "y"),
+		godal.FilterBetween("e", 4, 5),
+		godal.FilterPrefix("f", "50%_off"),
+		godal.FilterNot(godal.FilterIsNull("g")),
+		godal.FilterExists("h"),
+	)
+	f, err := dao.BuildFilter(filter)
+	if err != nil || f == nil {
+		t.Fatalf("%s failed - Filter: %v / Error: %e", name, f, err)
+	}
+	clause, values := f.Build(NewPlaceholderGeneratorDollarN())
+	expected := "(a = $1 AND (b > $2 OR c <= $3) AND d IN ($4,$5) AND (e >= $6 AND e <= $7) AND f LIKE $8 ESCAPE '!' AND NOT (g IS NULL) AND h IS NOT NULL)"
+	if clause != expected {
+		t.Fatalf("%s failed - Expected: %s / Received: %s", name, expected, clause)
+	}
+	expectedValues := []interface{}{1, 2, 3, "x", "y", 4, 5, "50!%!_off%"}
+	if fmt.Sprintf("%v", values) != fmt.Sprintf("%v", expectedValues) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expectedValues, values)
+	}
+
+	for _, invalid := range []*godal.FilterOpt{godal.FilterIn("a"), godal.FilterAnd(), godal.FilterNot(godal.FilterOr())} {
+		if _, err := dao.BuildFilter(invalid); err == nil {
+			t.Fatalf("%s failed - Expected error for filter %v", name, invalid)
+		}
+	}
+}
+
+func TestGenericDaoSql_BuildOrdering(t *testing.T) {
+	name := "TestGenericDaoSql_BuildOrdering"
+	dao := NewGenericDaoSql(nil, godal.NewAbstractGenericDao(nil))
+	testCases := []struct {
+		ordering interface{}
+		expected string
+	}{
+		{godal.NewSortingOpt().Add("b", godal.SortOrderDesc).Add("a", godal.SortOrderAsc), "b DESC,a ASC"},
+		{map[string]int{"b": -1, "a": 1, "c": 1}, "a,b DESC,c"},
+		{[]string{"b:-1", "a"}, "b DESC,a"},
+	}
+	for _, testCase := range testCases {
+		sorting, err := dao.BuildOrdering(testCase.ordering)
+		if err != nil || sorting == nil {
+			t.Fatalf("%s failed - Sorting: %v / Error: %e", name, sorting, err)
+		}
+		if clause := sorting.Build(); clause != testCase.expected {
+			t.Fatalf("%s failed - Expected: %s / Received: %s", name, testCase.expected, clause)
+		}
+	}
+}
+
+func TestInsertBuilder_AddRow(t *testing.T) {
+	name := "TestInsertBuilder_AddRow"
+	rows := []map[string]interface{}{{"a": 1, "b": "x"}, {"a": 2, "b": "y"}, {"a": 3}}
+
+	builder := NewInsertBuilder().WithTable("tbl").WithPlaceholderGenerator(NewPlaceholderGeneratorDollarN())
+	for _, row := range rows {
+		builder.AddRow(row)
+	}
+	sqlStm, values := builder.Build()
+	expected := []string{"INSERT INTO tbl (a,b) VALUES ($1,$2),($3,$4),($5,$6)", "INSERT INTO tbl (b,a) VALUES ($1,$2),($3,$4),($5,$6)"}
+	if (sqlStm != expected[0] && sqlStm != expected[1]) || len(values) != 6 || (values[4] != nil && values[5] != nil) {
+		t.Fatalf("%s failed - SQL: %s / Values: %v", name, sqlStm, values)
+	}
+
+	builder = NewInsertBuilder().WithFlavor(prom.FlavorOracle).WithTable("tbl").WithPlaceholderGenerator(NewPlaceholderGeneratorColonN())
+	builder.AddRow(map[string]interface{}{"a": 1}).AddRow(map[string]interface{}{"a": 2})
+	sqlStm, values = builder.Build()
+	if sqlStm != "INSERT ALL INTO tbl (a) VALUES (:1) INTO tbl (a) VALUES (:2) SELECT 1 FROM DUAL" || !reflect.DeepEqual(values, []interface{}{1, 2}) {
+		t.Fatalf("%s failed - SQL: %s / Values: %v", name, sqlStm, values)
+	}
+}
+
+func TestUpdateBuilder_AddIncrements(t *testing.T) {
+	name := "TestUpdateBuilder_AddIncrements"
+	builder := NewUpdateBuilder().WithTable("tbl").WithPlaceholderGenerator(NewPlaceholderGeneratorDollarN()).
+		WithValues(map[string]interface{}{"a": nil}).AddIncrements(map[string]interface{}{"b": 2})
+	sqlStm, values := builder.Build()
+	if sqlStm != "UPDATE tbl SET a=$1,b=COALESCE(b,0)+$2" || !reflect.DeepEqual(values, []interface{}{nil, 2}) {
+		t.Fatalf("%s failed - SQL: %s / Values: %v", name, sqlStm, values)
+	}
+}
+
+func TestInsertBuilder_WithReturning(t *testing.T) {
+	name := "TestInsertBuilder_WithReturning"
+	testCases := []struct {
+		flavor   prom.DbFlavor
+		expected string
+	}{
+		{prom.FlavorMySql, "INSERT INTO tbl (a) VALUES (?)"},
+		{prom.FlavorPgSql, "INSERT INTO tbl (a) VALUES ($1) RETURNING id,b"},
+		{prom.FlavorMsSql, "INSERT INTO tbl (a) OUTPUT INSERTED.id,INSERTED.b VALUES (@p1)"},
+		{prom.FlavorOracle, "INSERT INTO tbl (a) VALUES (:1) RETURNING id,b INTO :2,:3"},
+	}
+	for _, testCase := range testCases {
+		sqlStm, values := NewInsertBuilder().WithFlavor(testCase.flavor).WithTable("tbl").
+			WithValues(map[string]interface{}{"a": 1}).WithReturning("id", "b").Build()
+		if sqlStm != testCase.expected {
+			t.Fatalf("%s failed - Expected: %s / Received: %s", name, testCase.expected, sqlStm)
+		}
+		if testCase.flavor != prom.FlavorOracle && !reflect.DeepEqual(values, []interface{}{1}) {
+			t.Fatalf("%s failed - Values: %v", name, values)
+		}
+		if testCase.flavor == prom.FlavorOracle {
+			if _, ok := values[2].(sql.Out); len(values) != 3 || !ok {
+				t.Fatalf("%s failed - Values: %v", name, values)
+			}
+		}
+	}
+
+	builder := NewInsertBuilder().WithFlavor(prom.FlavorPgSql).WithTable("tbl").WithReturning("*")
+	builder.AddRow(map[string]interface{}{"a": 1}).AddRow(map[string]interface{}{"a": 2})
+	if sqlStm, _ := builder.Build(); sqlStm != "INSERT INTO tbl (a) VALUES ($1),($2) RETURNING *" {
+		t.Fatalf("%s failed - SQL: %s", name, sqlStm)
+	}
+}
+
+func TestUpdateBuilder_WithReturning(t *testing.T) {
+	name := "TestUpdateBuilder_WithReturning"
+	testCases := []struct {
+		flavor   prom.DbFlavor
+		expected string
+	}{
+		{prom.FlavorMySql, "UPDATE tbl SET a=? WHERE id = ?"},
+		{prom.FlavorPgSql, "UPDATE tbl SET a=$1 WHERE id = $2 RETURNING b"},
+		{prom.FlavorMsSql, "UPDATE tbl SET a=@p1 OUTPUT INSERTED.b WHERE id = @p2"},
+		{prom.FlavorOracle, "UPDATE tbl SET a=:1 WHERE id = :2 RETURNING b INTO :3"},
+	}
+	for _, testCase := range testCases {
+		sqlStm, values := NewUpdateBuilder().WithFlavor(testCase.flavor).WithTable("tbl").WithValues(map[string]interface{}{"a": 1}).
+			WithFilter(&FilterFieldValue{Field: "id", Operation: "=", Value: 2}).WithReturning("b").Build()
+		if sqlStm != testCase.expected || !reflect.DeepEqual(values[:2], []interface{}{1, 2}) {
+			t.Fatalf("%s failed - Expected: %s / Received: %s %v", name, testCase.expected, sqlStm, values)
+		}
+	}
+}
+
+func TestGenericDaoSql_PopulateReturned(t *testing.T) {
+	name := "TestGenericDaoSql_PopulateReturned"
+	dao := &GenericDaoSql{AbstractGenericDao: godal.NewAbstractGenericDao(nil), sqlFlavor: prom.FlavorPgSql}
+	dao.SetRowMapper(&GenericRowMapperSql{NameTransformation: NameTransfIntact})
+	if dao.SetReturningColumns("tbl", "id", "created").GetReturningColumns("tbl") == nil || dao.GetReturningColumns("other") != nil {
+		t.Fatalf("%s failed - unexpected returning columns", name)
+	}
+
+	bo := godal.NewGenericBo()
+	bo.GboSetAttr("name", "n")
+	bo.GboSetAttr("extra", "x")
+	colsAndVals := map[string]interface{}{"id": nil, "name": "n"}
+	if err := dao.populateReturned("tbl", bo, colsAndVals, nil); err != nil || bo.GboGetAttrUnsafe("id", nil) != nil {
+		t.Fatalf("%s failed - Bo: %v / Error: %e", name, bo, err)
+	}
+	if err := dao.populateReturned("tbl", bo, colsAndVals, map[string]interface{}{"ID": int64(5), "created": "now"}); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if bo.GboGetAttrUnsafe("id", nil) != int64(5) || bo.GboGetAttrUnsafe("created", nil) != "now" ||
+		bo.GboGetAttrUnsafe("name", nil) != "n" || bo.GboGetAttrUnsafe("extra", nil) != "x" || bo.GboHasAttr("ID") {
+		t.Fatalf("%s failed - Bo: %v", name, bo)
+	}
+
+	// returned values do not make a clean bo dirty
+	bo.GboMarkClean()
+	if err := dao.populateReturned("tbl", bo, colsAndVals, map[string]interface{}{"id": int64(6), "created": "later"}); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if bo.GboGetAttrUnsafe("id", nil) != int64(6) || bo.GboIsDirty() {
+		t.Fatalf("%s failed - Bo: %v / Dirty paths: %v", name, bo, bo.GboDirtyPaths())
+	}
+	if dao.SetReturningColumns("tbl").GetReturningColumns("tbl") != nil {
+		t.Fatalf("%s failed - returning columns are expected to be unset", name)
+	}
+}
+
+func TestUpsertBuilder(t *testing.T) {
+	name := "TestUpsertBuilder"
+	values := map[string]interface{}{"id": "1", "name": "n", "data": "d"}
+	testCases := []struct {
+		flavor   prom.DbFlavor
+		expected string
+	}{
+		{prom.FlavorMySql, "INSERT INTO tbl (data,id,name) VALUES (?,?,?) ON DUPLICATE KEY UPDATE data=VALUES(data),name=VALUES(name)"},
+		{prom.FlavorPgSql, "INSERT INTO tbl (data,id,name) VALUES ($1,$2,$3) ON CONFLICT (id) DO UPDATE SET data=EXCLUDED.data,name=EXCLUDED.name"},
+		{prom.FlavorMsSql, "MERGE INTO tbl WITH (HOLDLOCK) AS t USING (SELECT @p1 AS data,@p2 AS id,@p3 AS name) AS s ON (t.id=s.id) " +
+			"WHEN MATCHED THEN UPDATE SET data=s.data,name=s.name WHEN NOT MATCHED THEN INSERT (data,id,name) VALUES (s.data,s.id,s.name);"},
+		{prom.FlavorOracle, "MERGE INTO tbl t USING (SELECT :1 AS data,:2 AS id,:3 AS name FROM DUAL) s ON (t.id=s.id) " +
+			"WHEN MATCHED THEN UPDATE SET t.data=s.data,t.name=s.name WHEN NOT MATCHED THEN INSERT (data,id,name) VALUES (s.data,s.id,s.name)"},
+	}
+	for _, testCase := range testCases {
+		sqlStm, vals := NewUpsertBuilder().WithFlavor(testCase.flavor).WithTable("tbl").WithValues(values).WithKeyColumns("id").Build()
+		if sqlStm != testCase.expected || !reflect.DeepEqual(vals, []interface{}{"d", "1", "n"}) {
+			t.Fatalf("%s failed - Expected: %s / Received: %s %v", name, testCase.expected, sqlStm, vals)
+		}
+	}
+
+	// key columns only
+	builder := NewUpsertBuilder().WithTable("tbl").WithValues(map[string]interface{}{"a": 1, "b": 2}).WithKeyColumns("a", "b")
+	testCases = []struct {
+		flavor   prom.DbFlavor
+		expected string
+	}{
+		{prom.FlavorMySql, "INSERT INTO tbl (a,b) VALUES (?,?) ON DUPLICATE KEY UPDATE a=VALUES(a)"},
+		{prom.FlavorPgSql, "INSERT INTO tbl (a,b) VALUES ($1,$2) ON CONFLICT (a,b) DO UPDATE SET a=EXCLUDED.a"},
+		{prom.FlavorMsSql, "MERGE INTO tbl WITH (HOLDLOCK) AS t USING (SELECT @p1 AS a,@p2 AS b) AS s ON (t.a=s.a AND t.b=s.b) " +
+			"WHEN MATCHED THEN UPDATE SET a=s.a WHEN NOT MATCHED THEN INSERT (a,b) VALUES (s.a,s.b);"},
+		{prom.FlavorOracle, "MERGE INTO tbl t USING (SELECT :1 AS a,:2 AS b FROM DUAL) s ON (t.a=s.a AND t.b=s.b) WHEN NOT MATCHED THEN INSERT (a,b) VALUES (s.a,s.b)"},
+	}
+	for _, testCase := range testCases {
+		if sqlStm, _ := builder.WithFlavor(testCase.flavor).Build(); sqlStm != testCase.expected {
+			t.Fatalf("%s failed - Expected: %s / Received: %s", name, testCase.expected, sqlStm)
+		}
+	}
+}
+
+func TestGenericDaoSql_UpsertKeyColumns(t *testing.T) {
+	name := "TestGenericDaoSql_UpsertKeyColumns"
+	dao := &GenericDaoSql{sqlFlavor: prom.FlavorPgSql}
+	row := map[string]interface{}{"id": "1", "data": "d"}
+	if keyColumns := dao.upsertKeyColumns("tbl", row); keyColumns != nil {
+		t.Fatalf("%s failed - Received: %v", name, keyColumns)
+	}
+	if dao.SetKeyColumns("tbl", "id").SetKeyColumns("other", "a", "b"); !reflect.DeepEqual(dao.GetKeyColumns("tbl"), []string{"id"}) {
+		t.Fatalf("%s failed - Received: %v", name, dao.GetKeyColumns("tbl"))
+	}
+	if keyColumns := dao.upsertKeyColumns("tbl", row); !reflect.DeepEqual(keyColumns, []string{"id"}) {
+		t.Fatalf("%s failed - Received: %v", name, keyColumns)
+	}
+	if keyColumns := dao.upsertKeyColumns("other", row); keyColumns != nil {
+		t.Fatalf("%s failed - key columns missing from the row, received: %v", name, keyColumns)
+	}
+	dao.sqlFlavor = prom.FlavorMySql
+	if keyColumns := dao.upsertKeyColumns("tbl", row); keyColumns != nil {
+		t.Fatalf("%s failed - MySQL is not expected to use native upsert unless enabled, received: %v", name, keyColumns)
+	}
+	if dao.SetMySqlUpsert("tbl", true); !dao.GetMySqlUpsert("tbl") || dao.GetMySqlUpsert("other") {
+		t.Fatalf("%s failed - unexpected MySQL upsert settings", name)
+	}
+	if keyColumns := dao.upsertKeyColumns("tbl", row); !reflect.DeepEqual(keyColumns, []string{"id"}) {
+		t.Fatalf("%s failed - Received: %v", name, keyColumns)
+	}
+	if dao.SetMySqlUpsert("tbl", false); dao.upsertKeyColumns("tbl", row) != nil {
+		t.Fatalf("%s failed - MySQL upsert is expected to be disabled", name)
+	}
+	if dao.SetKeyColumns("tbl").GetKeyColumns("tbl") != nil {
+		t.Fatalf("%s failed - Received: %v", name, dao.GetKeyColumns("tbl"))
+	}
+}
+
+type testVersionDao struct {
+	*GenericDaoSql
+}
+
+// GdaoCreateFilter implements godal.IGenericDao.GdaoCreateFilter.
+func (dao *testVersionDao) GdaoCreateFilter(storageId string, bo godal.IGenericBo) interface{} {
+	return map[string]interface{}{"id": bo.GboGetAttrUnsafe("id", nil)}
+}
+
+func TestGenericDaoSql_VersionWriteBack(t *testing.T) {
+	name := "TestGenericDaoSql_VersionWriteBack"
+	sqlc := newTestStmtDao(t).sqlConnect
+	defer sqlc.Close()
+	dao := &testVersionDao{}
+	dao.GenericDaoSql = NewGenericDaoSql(sqlc, godal.NewAbstractGenericDao(dao))
+	dao.SetRowMapper(&GenericRowMapperSql{
+		GboFieldToColNameTranslator: map[string]map[string]interface{}{"tbl": {"rev": "ver"}},
+		ColNameToGboFieldTranslator: map[string]map[string]interface{}{"tbl": {"ver": "rev"}},
+		ColumnsListMap:              map[string][]string{"tbl": {"id", "ver"}},
+	})
+	dao.SetVersionField("tbl", "ver")
+
+	// the fake driver reports 1 affected row for every statement: the new version is written back to the BO after each write
+	bo := godal.NewGenericBo()
+	bo.GboSetAttr("id", "1")
+	bo.GboSetAttr("rev", 1)
+	for i := 1; i <= 2; i++ {
+		if numRows, err := dao.GdaoUpdate("tbl", bo); err != nil || numRows != 1 {
+			t.Fatalf("%s failed - NumRows: %v / Error: %e", name, numRows, err)
+		}
+		if v, err := bo.GboGetAttr("rev", reddo.TypeInt); err != nil || v != int64(1+i) {
+			t.Fatalf("%s failed - Expected: %v / Received: %v / Error: %e", name, 1+i, v, err)
+		}
+	}
+	if numRows, err := dao.GdaoSave("tbl", bo); err != nil || numRows != 1 || bo.GboGetAttrUnsafe("rev", reddo.TypeInt) != int64(4) {
+		t.Fatalf("%s failed - NumRows: %v / Version: %v / Error: %e", name, numRows, bo.GboGetAttrUnsafe("rev", nil), err)
+	}
+	boList := []godal.IGenericBo{bo, godal.NewGenericBo()}
+	boList[1].GboSetAttr("id", "2")
+	if results, err := dao.GdaoSaveMany("tbl", boList); err != nil || len(results) != 2 {
+		t.Fatalf("%s failed - Results: %v / Error: %e", name, results, err)
+	}
+	if v1, v2 := boList[0].GboGetAttrUnsafe("rev", reddo.TypeInt), boList[1].GboGetAttrUnsafe("rev", reddo.TypeInt); v1 != int64(5) || v2 != int64(1) {
+		t.Fatalf("%s failed - Versions: %v / %v", name, v1, v2)
+	}
+	if bo.GboGetAttrUnsafe("ver", nil) != nil {
+		t.Fatalf("%s failed - version is expected to be written to the mapped attribute only", name)
+	}
+}
+
+func TestGenericDaoSql_GdaoExistsLimit(t *testing.T) {
+	name := "TestGenericDaoSql_GdaoExistsLimit"
+	dao := newTestStmtDao(t)
+	defer dao.sqlConnect.Close()
+	var statement string
+	dao.SetStatementLogger(SqlStatementLoggerFunc(func(ctx context.Context, entry *SqlStatementLogEntry) {
+		statement = entry.Statement
+	}))
+	expected := map[prom.DbFlavor]string{
+		prom.FlavorMySql:  "SELECT 1 FROM tbl WHERE (id = ?) LIMIT 0,1",
+		prom.FlavorPgSql:  "SELECT 1 FROM tbl WHERE (id = $1) LIMIT 1 OFFSET 0",
+		prom.FlavorMsSql:  "SELECT TOP 1 1 FROM tbl WHERE (id = @p1)",
+		prom.FlavorOracle: "SELECT 1 FROM tbl WHERE (id = :1) OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+	}
+	for flavor, sqlStm := range expected {
+		dao.SetSqlFlavor(flavor)
+		if exists, err := dao.GdaoExists("tbl", map[string]interface{}{"id": 1}); err != nil || exists {
+			t.Fatalf("%s failed - Exists: %v / Error: %e", name, exists, err)
+		}
+		if statement != sqlStm {
+			t.Fatalf("%s failed - Expected: %s / Received: %s", name, sqlStm, statement)
+		}
+	}
+}
+
+func TestGenericDaoSql_GdaoWithTransaction_TxCtx(t *testing.T) {
+	name := "TestGenericDaoSql_GdaoWithTransaction_TxCtx"
+	dao, other := newTestStmtDao(t), newTestStmtDao(t)
+	defer dao.sqlConnect.Close()
+	defer other.sqlConnect.Close()
+
+	var staleCtx context.Context
+	err := dao.GdaoWithTransaction(context.Background(), func(txCtx context.Context, txDao godal.IGenericDao) error {
+		staleCtx = txCtx
+		if tx, err := dao.txFromContext(txCtx); err != nil || tx == nil {
+			t.Fatalf("%s failed - Tx: %v / Error: %e", name, tx, err)
+		}
+		// the transaction of another dao is not joined
+		if tx, err := other.txFromContext(txCtx); err != nil || tx != nil {
+			t.Fatalf("%s failed - Tx: %v / Error: %e", name, tx, err)
+		}
+		_, err := dao.SqlExecute(txCtx, nil, "STM")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+
+	// txCtx is rejected once the transaction has ended, except by other daos
+	if _, err := dao.SqlExecute(staleCtx, nil, "STM"); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if count, err := dao.GdaoCountWithTx(staleCtx, nil, "tbl", nil); err != godal.ErrTxEnded || count != 0 {
+		t.Fatalf("%s failed - Count: %v / Error: %e", name, count, err)
+	}
+	if err := dao.WrapTransaction(staleCtx, func(ctx context.Context, tx *sql.Tx) error { return nil }); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if err := dao.GdaoWithTransaction(staleCtx, func(txCtx context.Context, txDao godal.IGenericDao) error { return nil }); err != godal.ErrTxEnded {
+		t.Fatalf("%s failed: expected error %e but received %e", name, godal.ErrTxEnded, err)
+	}
+	if _, err := other.SqlExecute(staleCtx, nil, "STM"); err != nil {
+		t.Fatalf("%s failed: %e", name, err)
+	}
+}
@@ -0,0 +1,204 @@
+package sql
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// stmtCacheEntry is a prepared statement cached by stmtCache.
+type stmtCacheEntry struct {
+	sqlStm  string
+	stmt    *sql.Stmt
+	refs    int  // number of on-going executions using the statement
+	evicted bool // the statement is closed when the last execution releases it
+}
+
+// stmtCache is a LRU cache of prepared statements, keyed by SQL text.
+type stmtCache struct {
+	lock     sync.Mutex
+	capacity int
+	lru      *list.List // front = most recently used
+	entries  map[string]*list.Element
+}
+
+func newStmtCache(capacity int) *stmtCache {
+	return &stmtCache{capacity: capacity, lru: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// acquire returns the cached statement for sqlStm (nil if not cached); the returned entry must be released after use.
+func (c *stmtCache) acquire(sqlStm string) *stmtCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[sqlStm]; ok {
+		c.lru.MoveToFront(e)
+		entry := e.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry
+	}
+	return nil
+}
+
+// add caches stmt and returns its entry, acquired; the least recently used statements are evicted if the cache is full.
+// If sqlStm has been cached meanwhile, stmt is closed and the cached entry is returned instead.
+func (c *stmtCache) add(sqlStm string, stmt *sql.Stmt) *stmtCacheEntry {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.entries[sqlStm]; ok {
+		stmt.Close()
+		c.lru.MoveToFront(e)
+		entry := e.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry
+	}
+	entry := &stmtCacheEntry{sqlStm: sqlStm, stmt: stmt, refs: 1}
+	c.entries[sqlStm] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.capacity {
+		c.evictLocked(c.lru.Back())
+	}
+	return entry
+}
+
+// release marks the end of an execution using entry's statement, closing it if it has been evicted meanwhile.
+func (c *stmtCache) release(entry *stmtCacheEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if entry.refs--; entry.refs <= 0 && entry.evicted {
+		entry.stmt.Close()
+	}
+}
+
+// clear evicts all cached statements.
+func (c *stmtCache) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for c.lru.Len() > 0 {
+		c.evictLocked(c.lru.Back())
+	}
+}
+
+// len returns the number of cached statements.
+func (c *stmtCache) len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.lru.Len()
+}
+
+func (c *stmtCache) evictLocked(e *list.Element) {
+	entry := c.lru.Remove(e).(*stmtCacheEntry)
+	delete(c.entries, entry.sqlStm)
+	entry.evicted = true
+	if entry.refs <= 0 {
+		entry.stmt.Close()
+	}
+}
+
+/*
+GetStmtCacheCapacity returns the capacity of the prepared statement cache, 0 if the cache is disabled.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetStmtCacheCapacity() int {
+	if dao.stmtCache == nil {
+		return 0
+	}
+	return dao.stmtCache.capacity
+}
+
+/*
+SetStmtCacheCapacity sets the capacity of the LRU cache of prepared statements (keyed by SQL text), 0 (default) to disable the cache.
+
+	- cached statements are prepared on the DB and reused by SqlExecute/SqlQuery (hence by all Gdao* functions); inside
+	  transactions, they are bound to the transaction with tx.StmtContext.
+	- when the cache is full, the least recently used statement is evicted and closed (once executions using it complete).
+	- changing the capacity clears the cache; see also ClearStmtCache.
+	- the cache is not used if statement preparation is disabled, see SetPrepareStatements.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetStmtCacheCapacity(capacity int) *GenericDaoSql {
+	if dao.stmtCache != nil {
+		dao.stmtCache.clear()
+	}
+	dao.stmtCache = nil
+	if capacity > 0 {
+		dao.stmtCache = newStmtCache(capacity)
+	}
+	return dao
+}
+
+/*
+ClearStmtCache closes all statements in the prepared statement cache, e.g. after schema changes that invalidate them.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) ClearStmtCache() *GenericDaoSql {
+	if dao.stmtCache != nil {
+		dao.stmtCache.clear()
+	}
+	return dao
+}
+
+/*
+GetPrepareStatements returns true if SqlExecute/SqlQuery prepare statements before executing them (default), see SetPrepareStatements.
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) GetPrepareStatements() bool {
+	return !dao.noPrepare
+}
+
+/*
+SetPrepareStatements enables/disables statement preparation.
+
+When disabled, SqlExecute/SqlQuery pass statements directly to DB.ExecContext/QueryContext (or Tx.ExecContext/QueryContext)
+and the prepared statement cache is bypassed. This suits databases or connection poolers that do not support prepared statements
+well (e.g. PgBouncer in transaction pooling mode).
+
+Available: since v0.3.0
+*/
+func (dao *GenericDaoSql) SetPrepareStatements(enabled bool) *GenericDaoSql {
+	dao.noPrepare = !enabled
+	return dao
+}
+
+// withStmt prepares sqlStm (or reuses it from the prepared statement cache) and calls fn with the statement, bound to tx if not nil.
+//
+// Statements not cached are closed once fn returns, except those prepared inside a transaction that are closed when the transaction
+// ends (rows returned by a query must be read before the statement is closed).
+func (dao *GenericDaoSql) withStmt(ctx context.Context, tx *sql.Tx, sqlStm string, fn func(stmt *sql.Stmt) error) error {
+	cache := dao.stmtCache
+	if cache == nil {
+		if tx != nil {
+			stmt, err := tx.PrepareContext(ctx, sqlStm)
+			if err != nil {
+				return err
+			}
+			return fn(stmt)
+		}
+		stmt, err := dao.sqlConnect.GetDB().PrepareContext(ctx, sqlStm)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+		return fn(stmt)
+	}
+
+	entry := cache.acquire(sqlStm)
+	if entry == nil {
+		stmt, err := dao.sqlConnect.GetDB().PrepareContext(ctx, sqlStm)
+		if err != nil {
+			return err
+		}
+		entry = cache.add(sqlStm, stmt)
+	}
+	defer cache.release(entry)
+	if tx != nil {
+		// the transaction-bound statement shares the driver statement of the cached one, closing it does not close the latter
+		stmt := tx.StmtContext(ctx, entry.stmt)
+		defer stmt.Close()
+		return fn(stmt)
+	}
+	return fn(entry.stmt)
+}
@@ -0,0 +1,234 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/btnguyen2k/godal"
+	"github.com/btnguyen2k/prom"
+	"io"
+	"sync"
+	"testing"
+)
+
+// testStmtDriver is a minimal database/sql driver that counts prepared, closed and directly executed statements.
+type testStmtDriver struct {
+	lock                              sync.Mutex
+	numPrepared, numClosed, numDirect int
+}
+
+func (d *testStmtDriver) counters() (int, int, int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.numPrepared, d.numClosed, d.numDirect
+}
+
+func (d *testStmtDriver) Open(name string) (driver.Conn, error) {
+	return &testStmtConn{driver: d}, nil
+}
+
+type testStmtConn struct {
+	driver *testStmtDriver
+}
+
+func (c *testStmtConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.lock.Lock()
+	defer c.driver.lock.Unlock()
+	c.driver.numPrepared++
+	return &testStmtStmt{driver: c.driver}, nil
+}
+
+func (c *testStmtConn) Close() error {
+	return nil
+}
+
+func (c *testStmtConn) Begin() (driver.Tx, error) {
+	return c, nil
+}
+
+func (c *testStmtConn) Commit() error {
+	return nil
+}
+
+func (c *testStmtConn) Rollback() error {
+	return nil
+}
+
+func (c *testStmtConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.driver.lock.Lock()
+	defer c.driver.lock.Unlock()
+	c.driver.numDirect++
+	return driver.RowsAffected(1), nil
+}
+
+func (c *testStmtConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.driver.lock.Lock()
+	defer c.driver.lock.Unlock()
+	c.driver.numDirect++
+	return &testStmtRows{}, nil
+}
+
+type testStmtStmt struct {
+	driver *testStmtDriver
+}
+
+func (s *testStmtStmt) Close() error {
+	s.driver.lock.Lock()
+	defer s.driver.lock.Unlock()
+	s.driver.numClosed++
+	return nil
+}
+
+func (s *testStmtStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmtStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s *testStmtStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &testStmtRows{}, nil
+}
+
+type testStmtRows struct{}
+
+func (r *testStmtRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (r *testStmtRows) Close() error {
+	return nil
+}
+
+func (r *testStmtRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
+
+var testStmtDriverInstance = &testStmtDriver{}
+
+func init() {
+	sql.Register("godal-test-stmt", testStmtDriverInstance)
+}
+
+func newTestStmtDao(t *testing.T) *GenericDaoSql {
+	sqlc, err := prom.NewSqlConnect("godal-test-stmt", "", 10000, &prom.SqlPoolOptions{MaxOpenConns: 1, MaxIdleConns: 1})
+	if err != nil {
+		t.Fatalf("%s failed - Error: %e", t.Name(), err)
+	}
+	return NewGenericDaoSql(sqlc, godal.NewAbstractGenericDao(nil))
+}
+
+func TestStmtCache(t *testing.T) {
+	name := "TestStmtCache"
+	db := newTestStmtDao(t).sqlConnect.GetDB()
+	defer db.Close()
+	prepare := func(sqlStm string) *sql.Stmt {
+		stmt, err := db.Prepare(sqlStm)
+		if err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		return stmt
+	}
+	isClosed := func(stmt *sql.Stmt) bool {
+		_, err := stmt.Exec()
+		return err != nil
+	}
+
+	cache := newStmtCache(2)
+	a, b := cache.add("a", prepare("a")), cache.add("b", prepare("b"))
+	cache.release(a)
+	cache.release(b)
+	if cache.len() != 2 || cache.acquire("c") != nil {
+		t.Fatalf("%s failed - Len: %d", name, cache.len())
+	}
+
+	// "a" is in use and most recently used: "b" is evicted and closed when "c" is added
+	if cache.acquire("a") != a || a.refs != 1 {
+		t.Fatalf("%s failed - Entry: %v", name, a)
+	}
+	c := cache.add("c", prepare("c"))
+	cache.release(c)
+	if cache.len() != 2 || cache.acquire("b") != nil || !isClosed(b.stmt) || isClosed(a.stmt) {
+		t.Fatalf("%s failed - Len: %d", name, cache.len())
+	}
+
+	// statement cached meanwhile: the new one is closed
+	stmt := prepare("c")
+	if entry := cache.add("c", stmt); entry != c || !isClosed(stmt) {
+		t.Fatalf("%s failed - Entry: %v", name, entry)
+	}
+	cache.release(c)
+
+	// evicted statements in use are closed once released
+	cache.clear()
+	if cache.len() != 0 || !isClosed(c.stmt) || isClosed(a.stmt) {
+		t.Fatalf("%s failed - Len: %d", name, cache.len())
+	}
+	cache.release(a)
+	if !isClosed(a.stmt) {
+		t.Fatalf("%s failed - statement is expected to be closed", name)
+	}
+}
+
+func TestGenericDaoSql_StmtCache(t *testing.T) {
+	name := "TestGenericDaoSql_StmtCache"
+	dao := newTestStmtDao(t)
+	defer dao.sqlConnect.Close()
+	d := testStmtDriverInstance
+	execQuery := func(ctx context.Context, tx *sql.Tx, sqlStm string) {
+		if _, err := dao.SqlExecute(ctx, tx, sqlStm); err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		rows, err := dao.SqlQuery(ctx, tx, sqlStm)
+		if err != nil {
+			t.Fatalf("%s failed - Error: %e", name, err)
+		}
+		rows.Close()
+	}
+
+	// without cache, statements are prepared and closed on every call
+	if dao.GetStmtCacheCapacity() != 0 || !dao.GetPrepareStatements() {
+		t.Fatalf("%s failed - unexpected default settings", name)
+	}
+	prepared0, closed0, _ := d.counters()
+	execQuery(nil, nil, "STM 1")
+	if prepared, closed, _ := d.counters(); prepared-prepared0 != 2 || closed-closed0 != 2 {
+		t.Fatalf("%s failed - Prepared: %d / Closed: %d", name, prepared-prepared0, closed-closed0)
+	}
+
+	// with cache, statements are prepared once and reused, also inside transactions
+	if dao.SetStmtCacheCapacity(1).GetStmtCacheCapacity() != 1 {
+		t.Fatalf("%s failed - unexpected capacity", name)
+	}
+	prepared0, closed0, _ = d.counters()
+	execQuery(nil, nil, "STM 1")
+	execQuery(nil, nil, "STM 1")
+	tx, err := dao.sqlConnect.GetDB().Begin()
+	if err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	execQuery(nil, tx, "STM 1")
+	tx.Commit()
+	if prepared, closed, _ := d.counters(); prepared-prepared0 != 1 || closed-closed0 != 0 {
+		t.Fatalf("%s failed - Prepared: %d / Closed: %d", name, prepared-prepared0, closed-closed0)
+	}
+
+	// least recently used statement is evicted and closed
+	execQuery(nil, nil, "STM 2")
+	if prepared, closed, _ := d.counters(); prepared-prepared0 != 2 || closed-closed0 != 1 {
+		t.Fatalf("%s failed - Prepared: %d / Closed: %d", name, prepared-prepared0, closed-closed0)
+	}
+	dao.ClearStmtCache()
+	if _, closed, _ := d.counters(); closed-closed0 != 2 {
+		t.Fatalf("%s failed - Closed: %d", name, closed-closed0)
+	}
+
+	// statement preparation disabled: statements are executed directly, the cache is bypassed
+	dao.SetPrepareStatements(false)
+	prepared0, _, direct0 := d.counters()
+	execQuery(context.Background(), nil, "STM 3")
+	if prepared, _, direct := d.counters(); prepared != prepared0 || direct-direct0 != 2 || dao.stmtCache.len() != 0 {
+		t.Fatalf("%s failed - Prepared: %d / Direct: %d", name, prepared-prepared0, direct-direct0)
+	}
+}
@@ -1,9 +1,11 @@
 package sql
 
 import (
+	"database/sql"
 	"fmt"
 	"github.com/btnguyen2k/prom"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -278,6 +280,100 @@ func (f *FilterExpression) Build(placeholderGenerator PlaceholderGenerator) (str
 	return clause, make([]interface{}, 0)
 }
 
+/*
+FilterNot negates a filter using NOT clause.
+
+Available since v0.3.0
+*/
+type FilterNot struct {
+	Filter   IFilter
+	Operator string // literal form or the 'not' operator, default is "NOT"
+}
+
+/*
+Build implements IFilter.Build()
+*/
+func (f *FilterNot) Build(placeholderGenerator PlaceholderGenerator) (string, []interface{}) {
+	if f.Filter == nil {
+		return "", make([]interface{}, 0)
+	}
+	op := "NOT"
+	if f.Operator != "" {
+		op = f.Operator
+	}
+	clause, values := f.Filter.Build(placeholderGenerator)
+	return op + " (" + clause + ")", values
+}
+
+/*
+FilterIn represents single filter <field> IN (<value1>, <value2>,...).
+
+Available since v0.3.0
+*/
+type FilterIn struct {
+	Field     string        // field to check
+	Values    []interface{} // values to test against
+	Operation string        // literal form or the 'in' operator, default is "IN"
+}
+
+/*
+Build implements IFilter.Build()
+*/
+func (f *FilterIn) Build(placeholderGenerator PlaceholderGenerator) (string, []interface{}) {
+	op := "IN"
+	if f.Operation != "" {
+		op = f.Operation
+	}
+	placeholders := make([]string, len(f.Values))
+	for i := range f.Values {
+		placeholders[i] = placeholderGenerator(f.Field)
+	}
+	values := append(make([]interface{}, 0, len(f.Values)), f.Values...)
+	return f.Field + " " + op + " (" + strings.Join(placeholders, ",") + ")", values
+}
+
+/*
+FilterLike represents single filter <field> LIKE <pattern> [ESCAPE '<escape>'].
+
+Available since v0.3.0
+*/
+type FilterLike struct {
+	Field     string // field to check
+	Pattern   string // pattern to test against
+	Escape    string // escape character used in pattern, if any
+	Operation string // literal form or the 'like' operator, default is "LIKE"
+}
+
+/*
+Build implements IFilter.Build()
+*/
+func (f *FilterLike) Build(placeholderGenerator PlaceholderGenerator) (string, []interface{}) {
+	op := "LIKE"
+	if f.Operation != "" {
+		op = f.Operation
+	}
+	clause := f.Field + " " + op + " " + placeholderGenerator(f.Field)
+	if f.Escape != "" {
+		clause += " ESCAPE '" + f.Escape + "'"
+	}
+	return clause, []interface{}{f.Pattern}
+}
+
+// likeEscapeChar is used to escape wildcards when building LIKE patterns.
+// '!' is used instead of '\' because the latter is itself an escape character in MySQL string literals.
+const likeEscapeChar = "!"
+
+var likePatternEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_", "[", "![")
+
+/*
+NewFilterPrefix builds a FilterLike that matches values starting with 'prefix' (wildcards in 'prefix' are escaped).
+
+Available since v0.3.0
+*/
+func NewFilterPrefix(field, prefix string) *FilterLike {
+	return &FilterLike{Field: field, Pattern: likePatternEscaper.Replace(prefix) + "%", Escape: likeEscapeChar}
+}
+
 /*----------------------------------------------------------------------*/
 
 type ISqlBuilder interface {
@@ -574,6 +670,51 @@ func (b *SelectBuilder) Build() (string, []interface{}) {
 
 /*----------------------------------------------------------------------*/
 
+/*
+ReturningClause builds the clause appended to INSERT/UPDATE statements to read back columns of the written rows, and the values to
+bind to its placeholders (if any):
+
+	- PostgreSQL: " RETURNING <columns>", the columns are returned as a result set.
+	- Oracle: " RETURNING <columns> INTO <placeholders>", one sql.Out value (with a *string destination) is returned per column;
+	  "*" is not supported and a single row can be written.
+	- MSSQL: "", columns are returned as a result set by an OUTPUT clause placed before VALUES/WHERE.
+	- other flavors (e.g. MySQL): "", columns cannot be read back (generated ids are available via sql.Result.LastInsertId).
+
+Available: since v0.3.0
+*/
+func ReturningClause(flavor prom.DbFlavor, columns []string, placeholderGenerator PlaceholderGenerator) (string, []interface{}) {
+	if len(columns) == 0 {
+		return "", nil
+	}
+	switch flavor {
+	case prom.FlavorPgSql:
+		return " RETURNING " + strings.Join(columns, ","), nil
+	case prom.FlavorOracle:
+		placeholders := make([]string, len(columns))
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			placeholders[i] = placeholderGenerator(col)
+			values[i] = sql.Out{Dest: new(string)}
+		}
+		return " RETURNING " + strings.Join(columns, ",") + " INTO " + strings.Join(placeholders, ","), values
+	}
+	return "", nil
+}
+
+// outputClause builds the OUTPUT clause of MSSQL INSERT/UPDATE statements to read back columns of the written rows.
+func outputClause(flavor prom.DbFlavor, columns []string) string {
+	if len(columns) == 0 || flavor != prom.FlavorMsSql {
+		return ""
+	}
+	output := make([]string, len(columns))
+	for i, col := range columns {
+		output[i] = "INSERTED." + col
+	}
+	return " OUTPUT " + strings.Join(output, ",")
+}
+
+/*----------------------------------------------------------------------*/
+
 /*
 InsertBuilder is a builder that helps building INSERT sql statement.
 */
@@ -581,6 +722,8 @@ type InsertBuilder struct {
 	Flavor               prom.DbFlavor
 	Table                string
 	Values               map[string]interface{}
+	Rows                 []map[string]interface{} // additional rows inserted by the same statement (since v0.3.0)
+	ReturningColumns     []string                 // columns to read back from the inserted rows, see WithReturning (since v0.3.0)
 	PlaceholderGenerator PlaceholderGenerator
 }
 
@@ -649,6 +792,36 @@ func (b *InsertBuilder) AddValues(values map[string]interface{}) *InsertBuilder
 	return b
 }
 
+/*
+AddRow appends a row of column/value pairs, so that many rows are inserted by one statement.
+
+The first row is stored in Values, the others in Rows. All rows should have the same columns as Values; missing columns are inserted as NULL.
+
+Available: since v0.3.0
+*/
+func (b *InsertBuilder) AddRow(values map[string]interface{}) *InsertBuilder {
+	if len(b.Values) == 0 && len(b.Rows) == 0 {
+		return b.WithValues(values)
+	}
+	row := make(map[string]interface{})
+	for k, v := range values {
+		row[k] = v
+	}
+	b.Rows = append(b.Rows, row)
+	return b
+}
+
+/*
+WithReturning sets the columns to read back from the inserted rows (e.g. generated ids, defaults), "*" for all columns.
+See ReturningClause for the generated clause.
+
+Available: since v0.3.0
+*/
+func (b *InsertBuilder) WithReturning(columns ...string) *InsertBuilder {
+	b.ReturningColumns = append([]string{}, columns...)
+	return b
+}
+
 /*
 WithPlaceholderGenerator sets the placeholder generator used to generate placeholders in the SQL statement.
 */
@@ -661,6 +834,17 @@ func (b *InsertBuilder) WithPlaceholderGenerator(placeholderGenerator Placeholde
 Build constructs the INSERT sql statement, in the following format:
 
 	INSERT INTO <table> (<columns>) VALUES (<placeholders>)
+
+If there are additional rows (see AddRow), the statement inserts all of them (since v0.3.0):
+
+	INSERT INTO <table> (<columns>) VALUES (<placeholders>),(<placeholders>),...
+	INSERT ALL INTO <table> (<columns>) VALUES (<placeholders>) INTO <table> (<columns>) VALUES (<placeholders>)... SELECT 1 FROM DUAL (Oracle)
+
+If there are returning columns (see WithReturning), the returning clause is added (since v0.3.0), except for multi-row INSERT on Oracle:
+
+	INSERT INTO <table> (<columns>) VALUES (<placeholders>) RETURNING <columns> (PostgreSQL)
+	INSERT INTO <table> (<columns>) OUTPUT INSERTED.<column>[,...] VALUES (<placeholders>) (MSSQL)
+	INSERT INTO <table> (<columns>) VALUES (<placeholders>) RETURNING <columns> INTO <placeholders> (Oracle)
 */
 func (b *InsertBuilder) Build() (string, []interface{}) {
 	cols := make([]string, 0)
@@ -671,8 +855,30 @@ func (b *InsertBuilder) Build() (string, []interface{}) {
 		values = append(values, v)
 		placeholders = append(placeholders, b.PlaceholderGenerator(k))
 	}
-	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", b.Table, strings.Join(cols, ","), strings.Join(placeholders, ","))
-	return sql, values
+	output := outputClause(b.Flavor, b.ReturningColumns)
+	if len(b.Rows) == 0 {
+		sql := fmt.Sprintf("INSERT INTO %s (%s)%s VALUES (%s)", b.Table, strings.Join(cols, ","), output, strings.Join(placeholders, ","))
+		returning, outValues := ReturningClause(b.Flavor, b.ReturningColumns, b.PlaceholderGenerator)
+		return sql + returning, append(values, outValues...)
+	}
+
+	tuples := []string{"(" + strings.Join(placeholders, ",") + ")"}
+	for _, row := range b.Rows {
+		placeholders = make([]string, len(cols))
+		for i, col := range cols {
+			values = append(values, row[col])
+			placeholders[i] = b.PlaceholderGenerator(col)
+		}
+		tuples = append(tuples, "("+strings.Join(placeholders, ",")+")")
+	}
+	if b.Flavor == prom.FlavorOracle {
+		// Oracle does not support multi-row VALUES
+		into := fmt.Sprintf("INTO %s (%s) VALUES ", b.Table, strings.Join(cols, ","))
+		return "INSERT ALL " + into + strings.Join(tuples, " "+into) + " SELECT 1 FROM DUAL", values
+	}
+	sql := fmt.Sprintf("INSERT INTO %s (%s)%s VALUES %s", b.Table, strings.Join(cols, ","), output, strings.Join(tuples, ","))
+	returning, outValues := ReturningClause(b.Flavor, b.ReturningColumns, b.PlaceholderGenerator)
+	return sql + returning, append(values, outValues...)
 }
 
 /*----------------------------------------------------------------------*/
@@ -684,7 +890,9 @@ type UpdateBuilder struct {
 	Flavor               prom.DbFlavor
 	Table                string
 	Values               map[string]interface{}
+	Increments           map[string]interface{} // columns to increment and their deltas (since v0.3.0)
 	Filter               IFilter
+	ReturningColumns     []string // columns to read back from the updated rows, see WithReturning (since v0.3.0)
 	PlaceholderGenerator PlaceholderGenerator
 }
 
@@ -753,6 +961,21 @@ func (b *UpdateBuilder) AddValues(values map[string]interface{}) *UpdateBuilder
 	return b
 }
 
+/*
+AddIncrements adds column/delta pairs: each column is set to its current value (NULL is treated as 0) plus delta.
+
+Available: since v0.3.0
+*/
+func (b *UpdateBuilder) AddIncrements(deltas map[string]interface{}) *UpdateBuilder {
+	if b.Increments == nil {
+		b.Increments = make(map[string]interface{})
+	}
+	for k, v := range deltas {
+		b.Increments[k] = v
+	}
+	return b
+}
+
 /*
 WithFilter sets the filter used to generate the WHERE clause.
 */
@@ -761,6 +984,17 @@ func (b *UpdateBuilder) WithFilter(filter IFilter) *UpdateBuilder {
 	return b
 }
 
+/*
+WithReturning sets the columns to read back from the updated rows (e.g. trigger-populated columns), "*" for all columns.
+See ReturningClause for the generated clause.
+
+Available: since v0.3.0
+*/
+func (b *UpdateBuilder) WithReturning(columns ...string) *UpdateBuilder {
+	b.ReturningColumns = append([]string{}, columns...)
+	return b
+}
+
 /*
 WithPlaceholderGenerator sets the placeholder generator used to generate placeholders in the SQL statement.
 */
@@ -773,6 +1007,14 @@ func (b *UpdateBuilder) WithPlaceholderGenerator(placeholderGenerator Placeholde
 Build constructs the UPDATE sql statement, in the following format:
 
 	UPDATE <table> SET <col=value>[,<col=value>...] [WHERE <filter>]
+
+Increments (see AddIncrements) are rendered as <col=COALESCE(col,0)+delta> (since v0.3.0).
+
+If there are returning columns (see WithReturning), the returning clause is added (since v0.3.0):
+
+	UPDATE <table> SET <col=value>[,<col=value>...] [WHERE <filter>] RETURNING <columns> (PostgreSQL)
+	UPDATE <table> SET <col=value>[,<col=value>...] OUTPUT INSERTED.<column>[,...] [WHERE <filter>] (MSSQL)
+	UPDATE <table> SET <col=value>[,<col=value>...] [WHERE <filter>] RETURNING <columns> INTO <placeholders> (Oracle)
 */
 func (b *UpdateBuilder) Build() (string, []interface{}) {
 	sql := fmt.Sprintf("UPDATE %s", b.Table)
@@ -784,7 +1026,11 @@ func (b *UpdateBuilder) Build() (string, []interface{}) {
 		values = append(values, v)
 		setList = append(setList, k+"="+b.PlaceholderGenerator(k))
 	}
-	sql += " SET " + strings.Join(setList, ",")
+	for k, v := range b.Increments {
+		values = append(values, v)
+		setList = append(setList, k+"=COALESCE("+k+",0)+"+b.PlaceholderGenerator(k))
+	}
+	sql += " SET " + strings.Join(setList, ",") + outputClause(b.Flavor, b.ReturningColumns)
 
 	whereClause := ""
 	if b.Filter != nil {
@@ -795,5 +1041,202 @@ func (b *UpdateBuilder) Build() (string, []interface{}) {
 		sql += " WHERE " + whereClause
 	}
 
-	return sql, values
+	returning, outValues := ReturningClause(b.Flavor, b.ReturningColumns, b.PlaceholderGenerator)
+	return sql + returning, append(values, outValues...)
+}
+
+/*----------------------------------------------------------------------*/
+
+/*
+UpsertBuilder is a builder that helps building "insert-or-update" sql statement, see Build.
+
+Available: since v0.3.0
+*/
+type UpsertBuilder struct {
+	Flavor               prom.DbFlavor
+	Table                string
+	Values               map[string]interface{}
+	KeyColumns           []string // columns identifying the row, the unique key the conflict is detected on
+	PlaceholderGenerator PlaceholderGenerator
+}
+
+/*
+NewUpsertBuilder constructs a new UpsertBuilder.
+
+Available: since v0.3.0
+*/
+func NewUpsertBuilder() *UpsertBuilder {
+	return &UpsertBuilder{}
+}
+
+/*
+WithFlavor sets the SqlFlavor that affect the generated SQL statement.
+
+Note: WithFlavor will reset the PlaceholderGenerator
+*/
+func (b *UpsertBuilder) WithFlavor(flavor prom.DbFlavor) *UpsertBuilder {
+	b.Flavor = flavor
+	switch flavor {
+	case prom.FlavorMySql:
+		b.PlaceholderGenerator = NewPlaceholderGeneratorQuestion()
+	case prom.FlavorPgSql:
+		b.PlaceholderGenerator = NewPlaceholderGeneratorDollarN()
+	case prom.FlavorMsSql:
+		b.PlaceholderGenerator = NewPlaceholderGeneratorAtpiN()
+	case prom.FlavorOracle:
+		b.PlaceholderGenerator = NewPlaceholderGeneratorColonN()
+	default:
+		b.PlaceholderGenerator = NewPlaceholderGeneratorQuestion()
+	}
+	return b
+}
+
+/*
+WithTable sets name of the database table used to generate the SQL statement.
+*/
+func (b *UpsertBuilder) WithTable(table string) *UpsertBuilder {
+	b.Table = table
+	return b
+}
+
+/*
+WithValues sets list of column/value pairs used to generate the SQL statement.
+*/
+func (b *UpsertBuilder) WithValues(values map[string]interface{}) *UpsertBuilder {
+	b.Values = make(map[string]interface{})
+	if values != nil {
+		for k, v := range values {
+			b.Values[k] = v
+		}
+	}
+	return b
+}
+
+/*
+AddValues adds column/value pairs to the existing list.
+*/
+func (b *UpsertBuilder) AddValues(values map[string]interface{}) *UpsertBuilder {
+	if b.Values == nil {
+		b.Values = make(map[string]interface{})
+	}
+	if values != nil {
+		for k, v := range values {
+			b.Values[k] = v
+		}
+	}
+	return b
+}
+
+/*
+WithKeyColumns sets the columns identifying the row: the row is updated if a row with the same key values exists, inserted otherwise.
+Key columns must be among the values' columns.
+*/
+func (b *UpsertBuilder) WithKeyColumns(columns ...string) *UpsertBuilder {
+	b.KeyColumns = append([]string{}, columns...)
+	return b
+}
+
+/*
+WithPlaceholderGenerator sets the placeholder generator used to generate placeholders in the SQL statement.
+*/
+func (b *UpsertBuilder) WithPlaceholderGenerator(placeholderGenerator PlaceholderGenerator) *UpsertBuilder {
+	b.PlaceholderGenerator = placeholderGenerator
+	return b
+}
+
+/*
+Build constructs the "insert-or-update" sql statement; non-key columns are updated if the row exists. Format depends on the flavor:
+
+	INSERT INTO <table> (<columns>) VALUES (<placeholders>) ON DUPLICATE KEY UPDATE <col=VALUES(col)>[,...] (MySQL)
+	INSERT INTO <table> (<columns>) VALUES (<placeholders>) ON CONFLICT (<key columns>) DO UPDATE SET <col=EXCLUDED.col>[,...] (PostgreSQL & default)
+	MERGE INTO <table> WITH (HOLDLOCK) AS t USING (SELECT <placeholder AS col>[,...]) AS s ON (<t.key=s.key>[ AND ...])
+	  WHEN MATCHED THEN UPDATE SET <col=s.col>[,...] WHEN NOT MATCHED THEN INSERT (<columns>) VALUES (<s.col>[,...]); (MSSQL)
+	MERGE INTO <table> t USING (SELECT <placeholder AS col>[,...] FROM DUAL) s ON (<t.key=s.key>[ AND ...])
+	  WHEN MATCHED THEN UPDATE SET <t.col=s.col>[,...] WHEN NOT MATCHED THEN INSERT (<columns>) VALUES (<s.col>[,...]) (Oracle)
+
+Notes:
+
+	- MySQL's ON DUPLICATE KEY UPDATE fires on any unique key of the table, not only on the key columns.
+	- PostgreSQL's ON CONFLICT requires a unique index on the key columns.
+	- columns are sorted by name, so that the same set of columns always generates the same statement.
+	- if all columns are key columns, the first key column is updated to its own value (no-op update), except for Oracle whose MERGE
+	  has no WHEN MATCHED clause in that case.
+*/
+func (b *UpsertBuilder) Build() (string, []interface{}) {
+	cols := make([]string, 0, len(b.Values))
+	for k := range b.Values {
+		cols = append(cols, k)
+	}
+	sort.Strings(cols)
+	isKey := make(map[string]bool)
+	for _, k := range b.KeyColumns {
+		isKey[k] = true
+	}
+	placeholders := make([]string, len(cols))
+	values := make([]interface{}, len(cols))
+	updateCols := make([]string, 0, len(cols))
+	for i, col := range cols {
+		values[i] = b.Values[col]
+		placeholders[i] = b.PlaceholderGenerator(col)
+		if !isKey[col] {
+			updateCols = append(updateCols, col)
+		}
+	}
+	if len(updateCols) == 0 && len(b.KeyColumns) > 0 && b.Flavor != prom.FlavorOracle {
+		// no-op update, so that existing rows are reported as written (Oracle does not allow updating columns of the ON clause)
+		updateCols = b.KeyColumns[:1]
+	}
+
+	switch b.Flavor {
+	case prom.FlavorMsSql, prom.FlavorOracle:
+		selectList := make([]string, len(cols))
+		sourceCols := make([]string, len(cols))
+		for i, col := range cols {
+			selectList[i] = placeholders[i] + " AS " + col
+			sourceCols[i] = "s." + col
+		}
+		onList := make([]string, len(b.KeyColumns))
+		for i, col := range b.KeyColumns {
+			onList[i] = "t." + col + "=s." + col
+		}
+		setList := make([]string, len(updateCols))
+		for i, col := range updateCols {
+			setList[i] = col + "=s." + col
+			if b.Flavor == prom.FlavorOracle {
+				setList[i] = "t." + setList[i]
+			}
+		}
+		var sql string
+		if b.Flavor == prom.FlavorOracle {
+			sql = fmt.Sprintf("MERGE INTO %s t USING (SELECT %s FROM DUAL) s ON (%s)", b.Table, strings.Join(selectList, ","), strings.Join(onList, " AND "))
+		} else {
+			sql = fmt.Sprintf("MERGE INTO %s WITH (HOLDLOCK) AS t USING (SELECT %s) AS s ON (%s)", b.Table, strings.Join(selectList, ","), strings.Join(onList, " AND "))
+		}
+		if len(setList) > 0 {
+			sql += " WHEN MATCHED THEN UPDATE SET " + strings.Join(setList, ",")
+		}
+		sql += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(cols, ","), strings.Join(sourceCols, ","))
+		if b.Flavor == prom.FlavorMsSql {
+			// MSSQL requires MERGE statements to be terminated by a semicolon
+			sql += ";"
+		}
+		return sql, values
+	}
+
+	sql := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", b.Table, strings.Join(cols, ","), strings.Join(placeholders, ","))
+	if b.Flavor == prom.FlavorMySql {
+		setList := make([]string, len(updateCols))
+		for i, col := range updateCols {
+			setList[i] = col + "=VALUES(" + col + ")"
+		}
+		return sql + " ON DUPLICATE KEY UPDATE " + strings.Join(setList, ","), values
+	}
+	if len(updateCols) == 0 {
+		return sql + " ON CONFLICT (" + strings.Join(b.KeyColumns, ",") + ") DO NOTHING", values
+	}
+	setList := make([]string, len(updateCols))
+	for i, col := range updateCols {
+		setList[i] = col + "=EXCLUDED." + col
+	}
+	return sql + " ON CONFLICT (" + strings.Join(b.KeyColumns, ",") + ") DO UPDATE SET " + strings.Join(setList, ","), values
 }
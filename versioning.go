@@ -0,0 +1,140 @@
+package godal
+
+import (
+	"errors"
+	"fmt"
+	"github.com/btnguyen2k/consu/reddo"
+	"reflect"
+)
+
+/*
+SetVersionField enables optimistic locking on a storage; pass an empty 'field' to disable it.
+
+'field' is the native name of the version field: column name for SQL, document path for MongoDB, attribute name for DynamoDB,
+top-level field name for the in-memory DAO. The BO's version is read from the row returned by IRowMapper.ToRow.
+
+When optimistic locking is enabled:
+
+	- GdaoUpdate and GdaoSave overwrite the stored BO only if the stored version equals the BO's version, and store the BO
+	  with its version incremented by 1. If the stored BO has another version, (0, GdaoErrorConcurrentModification) is returned.
+	  GdaoSave creates the BO if it does not exist (with version incremented by 1, too).
+	- once written, the BO passed to GdaoUpdate/GdaoSave/GdaoSaveMany carries the new version (see SetBoVersion), hence can be
+	  modified and written again without being re-fetched.
+	- an absent/null version is treated as 0.
+	- GdaoCreate, GdaoPatch and GdaoDelete neither check nor change the version.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) SetVersionField(storageId, field string) *AbstractGenericDao {
+	dao.versionLock.Lock()
+	defer dao.versionLock.Unlock()
+	if dao.versionFields == nil {
+		dao.versionFields = make(map[string]string)
+	}
+	if field == "" {
+		delete(dao.versionFields, storageId)
+	} else {
+		dao.versionFields[storageId] = field
+	}
+	return dao
+}
+
+/*
+GetVersionField returns the version field of a storage, "" if optimistic locking is not enabled on the storage (see SetVersionField).
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) GetVersionField(storageId string) string {
+	dao.versionLock.RLock()
+	defer dao.versionLock.RUnlock()
+	return dao.versionFields[storageId]
+}
+
+/*
+SetBoVersion sets the version stored by a versioned write (see SetVersionField) to the BO. The version field being a native name, the
+BO's row form (see IRowMapper.ToRow) with the version field set to 'version' is transformed back by the row mapper (see IRowMapper.ToBo)
+and the resulting changes are applied to the BO; without row mapper, the version is set to the BO's attribute 'field'.
+
+This function does nothing if optimistic locking is not enabled on the storage.
+
+Available since v0.3.0
+*/
+func (dao *AbstractGenericDao) SetBoVersion(storageId string, bo IGenericBo, version int64) error {
+	field := dao.GetVersionField(storageId)
+	if field == "" || bo == nil {
+		return nil
+	}
+	mapper := dao.GetRowMapper()
+	if mapper == nil {
+		return bo.GboSetAttr(field, version)
+	}
+	row, err := mapper.ToRow(storageId, bo)
+	if err != nil {
+		return err
+	}
+	rowMap, err := reddo.ToMap(row, reflect.TypeOf(map[string]interface{}{}))
+	if err != nil {
+		return err
+	}
+	before := make(map[string]interface{})
+	after := map[string]interface{}{field: version}
+	for k, v := range rowMap.(map[string]interface{}) {
+		before[k] = v
+		if k != field {
+			after[k] = v
+		}
+	}
+	boBefore, err := mapper.ToBo(storageId, before)
+	if err != nil || boBefore == nil {
+		return err
+	}
+	boAfter, err := mapper.ToBo(storageId, after)
+	if err != nil || boAfter == nil {
+		return err
+	}
+	changes, err := boBefore.GboDiff(boAfter)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		switch {
+		case change.Path == "":
+			// the whole BO is replaced, can not be applied as a change of attribute
+			return bo.GboSetAttr(field, version)
+		case change.Type == GboChangeRemoved:
+			err = bo.GboDeleteAttr(change.Path)
+		default:
+			err = bo.GboSetAttr(change.Path, change.NewValue)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/*
+NextVersion returns the version of a BO in its row form (see IRowMapper.ToRow) and the version to store when the BO is written.
+
+An absent/null version is treated as 0.
+
+Available since v0.3.0
+*/
+func NextVersion(row map[string]interface{}, field string) (current, next int64, err error) {
+	if current, err = reddo.ToInt(row[field]); err != nil {
+		return 0, 0, errors.New(fmt.Sprintf("invalid version value [%v] of field [%s]: %s", row[field], field, err))
+	}
+	return current, current + 1, nil
+}
+
+/*
+VersionFilter builds the filter matching items whose version field equals 'version'. Version 0 also matches items without version.
+
+Available since v0.3.0
+*/
+func VersionFilter(field string, version int64) *FilterOpt {
+	if version == 0 {
+		return FilterOr(FilterIsNull(field), FilterEq(field, version))
+	}
+	return FilterEq(field, version)
+}
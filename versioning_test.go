@@ -0,0 +1,106 @@
+package godal
+
+import (
+	"github.com/btnguyen2k/consu/reddo"
+	"reflect"
+	"testing"
+)
+
+func TestAbstractGenericDao_SetVersionField(t *testing.T) {
+	name := "TestAbstractGenericDao_SetVersionField"
+	dao := NewAbstractGenericDao(nil)
+	if field := dao.GetVersionField("table"); field != "" {
+		t.Fatalf("%s failed - Expected: %#v / Received: %#v", name, "", field)
+	}
+	dao.SetVersionField("table", "version")
+	if field := dao.GetVersionField("table"); field != "version" {
+		t.Fatalf("%s failed - Expected: %#v / Received: %#v", name, "version", field)
+	}
+	dao.SetVersionField("table", "")
+	if field := dao.GetVersionField("table"); field != "" {
+		t.Fatalf("%s failed - Expected: %#v / Received: %#v", name, "", field)
+	}
+}
+
+func TestNextVersion(t *testing.T) {
+	name := "TestNextVersion"
+	testData := []struct {
+		row           map[string]interface{}
+		current, next int64
+	}{
+		{map[string]interface{}{}, 0, 1},
+		{map[string]interface{}{"v": nil}, 0, 1},
+		{map[string]interface{}{"v": 3}, 3, 4},
+		{map[string]interface{}{"v": 5.0}, 5, 6},
+		{map[string]interface{}{"v": "7"}, 7, 8},
+	}
+	for _, td := range testData {
+		if current, next, err := NextVersion(td.row, "v"); err != nil || current != td.current || next != td.next {
+			t.Fatalf("%s failed - Row: %v / Current: %v / Next: %v / Error: %e", name, td.row, current, next, err)
+		}
+	}
+	if _, _, err := NextVersion(map[string]interface{}{"v": "x"}, "v"); err == nil {
+		t.Fatalf("%s failed - expected error for non-numeric version", name)
+	}
+}
+
+func TestVersionFilter(t *testing.T) {
+	name := "TestVersionFilter"
+	if f, expected := VersionFilter("v", 0), FilterOr(FilterIsNull("v"), FilterEq("v", int64(0))); !reflect.DeepEqual(f, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, f)
+	}
+	if f, expected := VersionFilter("v", 2), FilterEq("v", int64(2)); !reflect.DeepEqual(f, expected) {
+		t.Fatalf("%s failed - Expected: %v / Received: %v", name, expected, f)
+	}
+}
+
+// testVersionRowMapper maps the BO's attribute "meta.version" to the column "ver".
+type testVersionRowMapper struct{}
+
+func (m testVersionRowMapper) ToRow(storageId string, bo IGenericBo) (interface{}, error) {
+	return map[string]interface{}{"id": bo.GboGetAttrUnsafe("id", nil), "ver": bo.GboGetAttrUnsafe("meta.version", nil)}, nil
+}
+
+func (m testVersionRowMapper) ToBo(storageId string, row interface{}) (IGenericBo, error) {
+	rowMap := row.(map[string]interface{})
+	bo := NewGenericBo()
+	bo.GboSetAttr("id", rowMap["id"])
+	bo.GboSetAttr("meta.version", rowMap["ver"])
+	return bo, nil
+}
+
+func (m testVersionRowMapper) ColumnsList(storageId string) []string {
+	return []string{"id", "ver"}
+}
+
+func TestAbstractGenericDao_SetBoVersion(t *testing.T) {
+	name := "TestAbstractGenericDao_SetBoVersion"
+	dao := NewAbstractGenericDao(nil)
+	bo := NewGenericBo()
+	bo.GboSetAttr("id", "1")
+	if err := dao.SetBoVersion("table", bo, 2); err != nil || bo.GboGetAttrUnsafe("version", nil) != nil {
+		t.Fatalf("%s failed - version is not expected to be set if optimistic locking is disabled / Error: %e", name, err)
+	}
+
+	// without row mapper, the version is set to the attribute named after the version field
+	dao.SetVersionField("table", "ver")
+	if err := dao.SetBoVersion("table", bo, 2); err != nil || bo.GboGetAttrUnsafe("ver", nil) != int64(2) {
+		t.Fatalf("%s failed - Received: %#v / Error: %e", name, bo.GboGetAttrUnsafe("ver", nil), err)
+	}
+
+	// with row mapper, the version is set to the attribute mapped to the version field
+	dao.SetRowMapper(testVersionRowMapper{})
+	bo = NewGenericBo()
+	bo.GboSetAttr("id", "1")
+	bo.GboSetAttr("meta.version", 1)
+	bo.GboSetAttr("data", "value")
+	if err := dao.SetBoVersion("table", bo, 2); err != nil {
+		t.Fatalf("%s failed - Error: %e", name, err)
+	}
+	if v, err := bo.GboGetAttr("meta.version", reddo.TypeInt); err != nil || v != int64(2) {
+		t.Fatalf("%s failed - Expected: %#v / Received: %#v / Error: %e", name, int64(2), v, err)
+	}
+	if v := bo.GboGetAttrUnsafe("data", nil); v != "value" || bo.GboGetAttrUnsafe("ver", nil) != nil {
+		t.Fatalf("%s failed - other attributes are not expected to be changed", name)
+	}
+}